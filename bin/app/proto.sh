@@ -29,7 +29,10 @@ echo "== generating protobuf go files =="
 protoc -I. -I/usr/include -I/usr/local/include \
   --go_out=. --go_opt=paths=source_relative \
   --go-grpc_out=. --go-grpc_opt=paths=source_relative \
-  proto/golden.proto
+  proto/goldens/v1/golden.proto proto/golden.proto
+
+echo "== generating OpenAPI spec =="
+go run ./cmd/app openapi -out internal/infrastructure/rest/openapi.json
 
 echo "== generated files =="
 ls -la proto
\ No newline at end of file
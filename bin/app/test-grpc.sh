@@ -20,7 +20,7 @@ NC='\033[0m'
 HOST="localhost"
 PORT="3000"
 DELAY=2
-SERVICE="goldens.GoldenService"
+SERVICE="goldens.v1.GoldenService"
 RESULTS=()
 
 print_header() {
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"markitos-it-svc-goldens/internal/domain"
+	"markitos-it-svc-goldens/internal/infrastructure/archive"
+	"markitos-it-svc-goldens/internal/infrastructure/rest"
+	"os"
+	"strings"
+	"time"
+
+	pb "markitos-it-svc-goldens/proto/goldens/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func runCommand(name string, args []string) {
+	var err error
+	switch name {
+	case "export":
+		err = runExport(args)
+	case "import":
+		err = runImport(args)
+	case "openapi":
+		err = runOpenAPI(args)
+	default:
+		log.Fatalf("❌ Unknown command %q (available: export, import, openapi)", name)
+	}
+
+	if err != nil {
+		log.Fatalf("❌ %s failed: %v", name, err)
+	}
+}
+
+type clientFlags struct {
+	addr  string
+	tls   bool
+	token string
+}
+
+func (c *clientFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&c.addr, "addr", "localhost:"+getEnvOrDefault("GRPC_PORT", "3000"), "gRPC server address")
+	fs.BoolVar(&c.tls, "tls", strings.EqualFold(getEnvOrDefault("GRPC_TLS_ENABLED", "false"), "true"), "use TLS with the system roots")
+	fs.StringVar(&c.token, "token", getEnvOrDefault("EDITOR_TOKEN", ""), "editor token, needed to import and to export unpublished goldens")
+}
+
+// context carries the editor token of the calls, if any.
+func (c *clientFlags) context() context.Context {
+	ctx := context.Background()
+	if c.token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+c.token)
+	}
+	return ctx
+}
+
+func (c *clientFlags) dial() (*grpc.ClientConn, error) {
+	creds := insecure.NewCredentials()
+	if c.tls {
+		creds = credentials.NewClientTLSFromCert(nil, "")
+	}
+	return grpc.NewClient(c.addr, grpc.WithTransportCredentials(creds))
+}
+
+// runExport downloads every golden as a tar.gz of markdown files.
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	var client clientFlags
+	client.register(fs)
+	out := fs.String("out", "goldens.tar.gz", "archive path, - for stdout")
+	fs.Parse(args)
+
+	conn, err := client.dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	stream, err := pb.NewGoldenServiceClient(conn).ExportGoldens(client.context(), &pb.ExportGoldensRequest{})
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "-" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(chunk.Data); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("✅ Exported goldens to %s", *out)
+	return nil
+}
+
+// runImport streams an archive produced by export back through ImportGoldens.
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	var client clientFlags
+	client.register(fs)
+	in := fs.String("in", "goldens.tar.gz", "archive path, - for stdin")
+	dryRun := fs.Bool("dry-run", false, "report changes without applying them")
+	resetStatus := fs.Bool("reset-status", false, "import new goldens as drafts and keep the status of existing ones")
+	fs.Parse(args)
+
+	var r io.Reader = os.Stdin
+	if *in != "-" {
+		f, err := os.Open(*in)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	conn, err := client.dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	stream, err := pb.NewGoldenServiceClient(conn).ImportGoldens(client.context())
+	if err != nil {
+		return err
+	}
+
+	err = archive.Read(r, func(doc domain.Golden) error {
+		return stream.Send(&pb.ImportGoldensRequest{
+			DryRun:      *dryRun,
+			ResetStatus: *resetStatus,
+			Golden: &pb.Golden{
+				Id:          doc.ID,
+				Title:       doc.Title,
+				Description: doc.Description,
+				Category:    doc.Category,
+				Tags:        doc.Tags,
+				UpdatedAt:   timestamppb.New(doc.UpdatedAt),
+				ContentB64:  doc.ContentB64,
+				CoverImage:  doc.CoverImage,
+				Status:      pb.GoldenStatus(pb.GoldenStatus_value["GOLDEN_STATUS_"+strings.ToUpper(string(doc.Status))]),
+				SubmittedAt: optionalTimestamp(doc.SubmittedAt),
+				PublishedAt: optionalTimestamp(doc.PublishedAt),
+				ArchivedAt:  optionalTimestamp(doc.ArchivedAt),
+				PublishAt:   optionalTimestamp(doc.PublishAt),
+				ExpireAt:    optionalTimestamp(doc.ExpireAt),
+			},
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+
+	for _, result := range resp.Results {
+		if result.Status == pb.ImportStatus_IMPORT_STATUS_FAILED {
+			log.Printf("⚠️  %s: %s", result.Id, result.Error)
+		}
+	}
+	fmt.Printf("created=%d updated=%d failed=%d dry_run=%t\n", resp.Created, resp.Updated, resp.Failed, resp.DryRun)
+	return nil
+}
+
+// runOpenAPI writes the OpenAPI document of the HTTP gateway.
+// optionalTimestamp leaves zero times unset.
+func optionalTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func runOpenAPI(args []string) error {
+	fs := flag.NewFlagSet("openapi", flag.ExitOnError)
+	out := fs.String("out", "internal/infrastructure/rest/openapi.json", "output path, - for stdout")
+	fs.Parse(args)
+
+	spec, err := rest.OpenAPI()
+	if err != nil {
+		return err
+	}
+	if *out == "-" {
+		_, err = os.Stdout.Write(spec)
+		return err
+	}
+	return os.WriteFile(*out, spec, 0o644)
+}
@@ -0,0 +1,28 @@
+//go:build kafka
+
+package main
+
+import (
+	"log"
+	"markitos-it-svc-goldens/internal/domain"
+	"markitos-it-svc-goldens/internal/infrastructure/events"
+	"strings"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func init() {
+	newKafkaPublisher = func(source string) domain.EventPublisher {
+		brokers := strings.Split(getEnvRequired("KAFKA_BROKERS"), ",")
+		client, err := kgo.NewClient(kgo.SeedBrokers(brokers...))
+		if err != nil {
+			log.Fatalf("❌ Failed to create Kafka client: %v", err)
+		}
+		log.Printf("📡 Publishing events to Kafka at %v", brokers)
+
+		return events.NewKafkaPublisher(client, events.KafkaConfig{
+			TopicTemplate: getEnvOrDefault("KAFKA_TOPIC", "goldens.events"),
+			Source:        source,
+		})
+	}
+}
@@ -3,20 +3,35 @@ package main
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"expvar"
 	"fmt"
 	"log"
 	"markitos-it-svc-goldens/internal/application/services"
+	"markitos-it-svc-goldens/internal/domain"
+	"markitos-it-svc-goldens/internal/infrastructure/events"
+	"markitos-it-svc-goldens/internal/infrastructure/grpcweb"
+	"markitos-it-svc-goldens/internal/infrastructure/persistence/cache"
+	"markitos-it-svc-goldens/internal/infrastructure/persistence/filesystem"
 	"markitos-it-svc-goldens/internal/infrastructure/persistence/postgres"
+	"markitos-it-svc-goldens/internal/infrastructure/persistence/sqlite"
+	"markitos-it-svc-goldens/internal/infrastructure/rest"
+	"markitos-it-svc-goldens/internal/infrastructure/webhook"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	grpcserver "markitos-it-svc-goldens/internal/infrastructure/grpc"
-	pb "markitos-it-svc-goldens/proto"
+	legacypb "markitos-it-svc-goldens/proto"
+	pb "markitos-it-svc-goldens/proto/goldens/v1"
 
 	_ "github.com/lib/pq"
+	"github.com/nats-io/nats.go"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/reflection"
@@ -39,19 +54,48 @@ func getEnvOrDefault(key, fallback string) string {
 }
 
 func main() {
+	if len(os.Args) > 1 {
+		runCommand(os.Args[1], os.Args[2:])
+		return
+	}
+
 	log.Println("🚀 Starting Goldens gRPC Service...")
-	db, repo := loadDatabase()
-	defer db.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	ctx := context.Background()
-	if err := repo.InitSchema(ctx); err != nil {
-		log.Fatalf("❌ Failed to initialize schema: %v", err)
-	}
+	repo, db, closeRepo := loadRepository(ctx)
+	defer closeRepo()
 
-	if err := repo.SeedData(ctx); err != nil {
-		log.Printf("⚠️  Failed to seed data: %v", err)
+	// The category tree, the outbox, the webhook registry and idempotency keys
+	// live in PostgreSQL.
+	var serviceOpts []services.GoldenServiceOption
+	serverOpts := []grpcserver.ServerOption{grpcserver.WithBatchGetLimit(batchGetLimit())}
+	editors := editorAuth()
+	unaryInterceptors := []grpc.UnaryServerInterceptor{grpcserver.UnaryWriteTracking, editors.Unary}
+	if db != nil {
+		categoryStore := postgres.NewCategoryRepository(db)
+		serviceOpts = append(serviceOpts, services.WithCategories(categoryStore))
+		serverOpts = append(serverOpts, grpcserver.WithCategories(services.NewCategoryService(categoryStore)))
+
+		// Renames and merges invalidate cached goldens through the
+		// invalidation listener, like writes made by other replicas.
+		tagStore := postgres.NewTagRepository(db)
+		if err := tagStore.InitSchema(ctx); err != nil {
+			log.Fatalf("❌ Failed to initialize tag schema: %v", err)
+		}
+		serviceOpts = append(serviceOpts, services.WithTagRegistry(tagStore))
+		serverOpts = append(serverOpts, grpcserver.WithTags(services.NewTagService(tagStore)))
+
+		webhookStore := postgres.NewWebhookRepository(db)
+		if err := webhookStore.InitSchema(ctx); err != nil {
+			log.Fatalf("❌ Failed to initialize webhook schema: %v", err)
+		}
+		webhookHosts := webhookAllowedHosts()
+		serverOpts = append(serverOpts, grpcserver.WithWebhooks(services.NewWebhookService(webhookStore, webhookHosts)))
+		startOutboxRelay(ctx, db, newEventPublisher(webhookStore, webhookHosts))
+		startScheduler(ctx, db)
+		unaryInterceptors = append(unaryInterceptors, newIdempotencyInterceptor(ctx, db))
 	}
-	docService := services.NewGoldenService(repo)
 
 	grpcPort := getEnvRequired("GRPC_PORT")
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%s", grpcPort))
@@ -59,51 +103,458 @@ func main() {
 		log.Fatalf("❌ Failed to listen: %v", err)
 	}
 
+	grpcOpts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unaryInterceptors...),
+		grpc.ChainStreamInterceptor(grpcserver.StreamWriteTracking, editors.Stream),
+	}
 	tlsEnabled := strings.EqualFold(getEnvOrDefault("GRPC_TLS_ENABLED", "false"), "true")
-	var grpcServer *grpc.Server
+	certFile := getEnvOrDefault("GRPC_TLS_CERT_FILE", "certs/server.crt")
+	keyFile := getEnvOrDefault("GRPC_TLS_KEY_FILE", "certs/server.key")
 	if tlsEnabled {
-		certFile := getEnvOrDefault("GRPC_TLS_CERT_FILE", "certs/server.crt")
-		keyFile := getEnvOrDefault("GRPC_TLS_KEY_FILE", "certs/server.key")
 		creds, tlsErr := credentials.NewServerTLSFromFile(certFile, keyFile)
 		if tlsErr != nil {
 			log.Fatalf("tls error: %v", tlsErr)
 		}
-		grpcServer = grpc.NewServer(grpc.Creds(creds))
+		grpcOpts = append(grpcOpts, grpc.Creds(creds))
 		log.Printf("🔐 gRPC TLS enabled using cert=%s key=%s", certFile, keyFile)
 	} else {
-		grpcServer = grpc.NewServer()
 		log.Println("⚠️  gRPC TLS disabled (set GRPC_TLS_ENABLED=true to enable TLS)")
 	}
+	grpcServer := grpc.NewServer(grpcOpts...)
 
-	pb.RegisterGoldenServiceServer(grpcServer, grpcserver.NewGoldenServer(docService))
+	docService := services.NewGoldenService(withCache(ctx, repo), serviceOpts...)
+	goldenServer := grpcserver.NewGoldenServer(docService, serverOpts...)
+	pb.RegisterGoldenServiceServer(grpcServer, goldenServer)
+	legacypb.RegisterGoldenServiceServer(grpcServer, grpcserver.NewLegacyGoldenServer(goldenServer))
 	reflection.Register(grpcServer)
 
+	httpServer := newHTTPGateway(goldenServer, unaryInterceptors)
+	webServer := newWebServer(grpcServer)
+
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 
 	go func() {
-		log.Printf("🎯 gRPC server listening on :%s", grpcPort)
-		if err := grpcServer.Serve(lis); err != nil {
+		log.Printf("🎯 gRPC, gRPC-Web and Connect listening on :%s", grpcPort)
+		var err error
+		if tlsEnabled {
+			err = webServer.ServeTLS(lis, certFile, keyFile)
+		} else {
+			err = webServer.Serve(lis)
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
 			log.Fatalf("❌ Failed to serve: %v", err)
 		}
 	}()
 
 	<-sigChan
 	log.Println("\n🛑 Shutting down gracefully...")
-	grpcServer.GracefulStop()
+	drained := true
+	for _, server := range []*http.Server{httpServer, webServer} {
+		if server == nil {
+			continue
+		}
+		shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Printf("⚠️  HTTP server shutdown: %v", err)
+			drained = drained && server != webServer
+		}
+		cancelShutdown()
+	}
+	// Calls passed to grpc.Server.ServeHTTP cannot be drained by GracefulStop,
+	// which panics on them, so they are left to webServer.Shutdown and only
+	// the ones still running after its timeout are cut off by Stop.
+	if drained {
+		grpcServer.GracefulStop()
+	} else {
+		grpcServer.Stop()
+	}
 	log.Println("👋 Service stopped")
 }
 
-func loadDatabase() (*sql.DB, *postgres.GoldenRepository) {
-	log.Println("🚀 loading database")
+// batchGetLimit reads BATCH_GET_MAX_IDS, the most ids a BatchGetGoldens call
+// may ask for.
+func batchGetLimit() int {
+	limit, err := strconv.Atoi(getEnvOrDefault("BATCH_GET_MAX_IDS", strconv.Itoa(grpcserver.DefaultBatchGetLimit)))
+	if err != nil || limit < 1 {
+		log.Fatalf("❌ Invalid BATCH_GET_MAX_IDS: %q", os.Getenv("BATCH_GET_MAX_IDS"))
+	}
+	return limit
+}
+
+// newHTTPGateway serves the REST/JSON gateway on HTTP_PORT, sharing the gRPC
+// interceptors. It returns nil when HTTP_PORT is not set.
+func newHTTPGateway(server pb.GoldenServiceServer, interceptors []grpc.UnaryServerInterceptor) *http.Server {
+	httpPort := os.Getenv("HTTP_PORT")
+	if httpPort == "" {
+		return nil
+	}
+
+	cors := corsConfig()
+	httpServer := &http.Server{
+		Addr:              ":" + httpPort,
+		Handler:           rest.NewGateway(server, rest.WithInterceptors(interceptors...), rest.WithCORS(cors)),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		log.Printf("🌐 HTTP gateway listening on :%s (cors origins: %v)", httpPort, cors.AllowedOrigins)
+		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("❌ Failed to serve HTTP gateway: %v", err)
+		}
+	}()
+
+	return httpServer
+}
+
+// newWebServer serves gRPC-Web and Connect next to native gRPC on the gRPC
+// port, over h2c when TLS is disabled. grpcweb.Handler sends application/grpc
+// requests to grpc.Server.ServeHTTP and translates the browser protocols.
+func newWebServer(grpcServer *grpc.Server) *http.Server {
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetHTTP2(true)
+	protocols.SetUnencryptedHTTP2(true)
+
+	return &http.Server{
+		Handler:           grpcweb.CORSConfig(corsConfig()).Wrap(grpcweb.NewHandler(grpcServer)),
+		Protocols:         protocols,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+}
+
+// editorAuth reads EDITOR_TOKENS, the comma-separated bearer tokens that
+// grant editor rights: writing, changing the status of goldens and reading
+// drafts. EDITOR_OPEN_ACCESS=true grants them to everyone instead.
+func editorAuth() *grpcserver.EditorAuth {
+	if strings.EqualFold(getEnvOrDefault("EDITOR_OPEN_ACCESS", "false"), "true") {
+		log.Println("⚠️  EDITOR_OPEN_ACCESS enabled: every caller has editor rights, can write and sees unpublished goldens")
+		return grpcserver.NewOpenEditorAuth()
+	}
+
+	var tokens []string
+	if value := getEnvOrDefault("EDITOR_TOKENS", ""); value != "" {
+		tokens = strings.Split(value, ",")
+	}
+	if len(tokens) == 0 {
+		log.Println("⚠️  EDITOR_TOKENS not set: the API is read-only and only serves published goldens")
+	}
+	return grpcserver.NewEditorAuth(tokens)
+}
+
+// webhookAllowedHosts reads WEBHOOK_ALLOWED_HOSTS, the comma-separated hosts
+// webhooks may target over http and on private addresses.
+func webhookAllowedHosts() []string {
+	var hosts []string
+	if value := getEnvOrDefault("WEBHOOK_ALLOWED_HOSTS", ""); value != "" {
+		for _, host := range strings.Split(value, ",") {
+			hosts = append(hosts, strings.TrimSpace(host))
+		}
+	}
+	return hosts
+}
+
+// corsConfig lists the browser origins allowed to call the HTTP gateway and
+// the gRPC-Web/Connect endpoints.
+func corsConfig() rest.CORSConfig {
+	cors := rest.DefaultCORSConfig()
+	if origins := getEnvOrDefault("HTTP_CORS_ALLOWED_ORIGINS", ""); origins != "" {
+		for _, origin := range strings.Split(origins, ",") {
+			cors.AllowedOrigins = append(cors.AllowedOrigins, strings.TrimSpace(origin))
+		}
+	}
+	maxAge, err := time.ParseDuration(getEnvOrDefault("HTTP_CORS_MAX_AGE", cors.MaxAge.String()))
+	if err != nil {
+		log.Fatalf("❌ Invalid HTTP_CORS_MAX_AGE: %v", err)
+	}
+	cors.MaxAge = maxAge
+	return cors
+}
+
+// loadRepository opens the configured storage backend. The returned database
+// is only set for PostgreSQL.
+func loadRepository(ctx context.Context) (domain.Repository, *sql.DB, func()) {
+	switch backend := getEnvOrDefault("STORAGE_BACKEND", "postgres"); backend {
+	case "postgres":
+		db, repo := loadDatabase()
+		if err := repo.InitSchema(ctx); err != nil {
+			log.Fatalf("❌ Failed to initialize schema: %v", err)
+		}
+		if err := repo.SeedData(ctx); err != nil {
+			log.Printf("⚠️  Failed to seed data: %v", err)
+		}
+		closeReplica := loadReadReplica(ctx, repo)
+		return repo, db, func() {
+			closeReplica()
+			db.Close()
+		}
+	case "sqlite":
+		db, repo := loadSQLite()
+		if err := repo.InitSchema(ctx); err != nil {
+			log.Fatalf("❌ Failed to initialize schema: %v", err)
+		}
+		return repo, nil, func() { db.Close() }
+	case "filesystem":
+		return loadFilesystem(ctx), nil, func() {}
+	default:
+		log.Fatalf("❌ Unknown STORAGE_BACKEND %q (expected postgres, sqlite or filesystem)", backend)
+		return nil, nil, nil
+	}
+}
+
+func withCache(ctx context.Context, repo domain.Repository) domain.Repository {
+	if !strings.EqualFold(getEnvOrDefault("CACHE_ENABLED", "false"), "true") {
+		return repo
+	}
+
+	ttl, err := time.ParseDuration(getEnvOrDefault("CACHE_TTL", "30s"))
+	if err != nil {
+		log.Fatalf("❌ Invalid CACHE_TTL: %v", err)
+	}
+	maxBytes, err := strconv.ParseInt(getEnvOrDefault("CACHE_MAX_BYTES", "67108864"), 10, 64)
+	if err != nil {
+		log.Fatalf("❌ Invalid CACHE_MAX_BYTES: %v", err)
+	}
+	loadTimeout, err := time.ParseDuration(getEnvOrDefault("CACHE_LOAD_TIMEOUT", cache.DefaultLoadTimeout.String()))
+	if err != nil {
+		log.Fatalf("❌ Invalid CACHE_LOAD_TIMEOUT: %v", err)
+	}
+
+	cached := cache.NewGoldenRepository(repo, cache.Config{TTL: ttl, MaxBytes: maxBytes, LoadTimeout: loadTimeout})
+	expvar.Publish("goldens_cache", expvar.Func(func() any { return cached.Stats() }))
+	log.Printf("🧠 Read cache enabled (ttl=%s, max_bytes=%d)", ttl, maxBytes)
+
+	// Other replicas write to the same database, so their changes must reach
+	// this cache through PostgreSQL notifications.
+	if _, ok := repo.(*postgres.GoldenRepository); ok {
+		listener := postgres.NewInvalidationListener(postgresDSN(), cached)
+		go func() {
+			if err := listener.Run(ctx); err != nil {
+				log.Fatalf("❌ Cache invalidation listener failed: %v", err)
+			}
+		}()
+	}
+
+	return cached
+}
+
+func startOutboxRelay(ctx context.Context, db *sql.DB, publisher domain.EventPublisher) {
+	if !strings.EqualFold(getEnvOrDefault("OUTBOX_RELAY_ENABLED", "true"), "true") {
+		log.Println("⚠️  Outbox relay disabled, events stay pending in outbox_events")
+		return
+	}
+
+	config := services.DefaultOutboxRelayConfig()
+	interval, err := time.ParseDuration(getEnvOrDefault("OUTBOX_POLL_INTERVAL", config.PollInterval.String()))
+	if err != nil {
+		log.Fatalf("❌ Invalid OUTBOX_POLL_INTERVAL: %v", err)
+	}
+	maxAttempts, err := strconv.Atoi(getEnvOrDefault("OUTBOX_MAX_ATTEMPTS", strconv.Itoa(config.MaxAttempts)))
+	if err != nil {
+		log.Fatalf("❌ Invalid OUTBOX_MAX_ATTEMPTS: %v", err)
+	}
+	config.PollInterval = interval
+	config.MaxAttempts = maxAttempts
+
+	relay := services.NewOutboxRelay(postgres.NewOutboxRepository(db), publisher, config)
+	go relay.Run(ctx)
+	log.Printf("📬 Outbox relay started (poll=%s, max_attempts=%d)", interval, maxAttempts)
+}
+
+// startScheduler runs the scheduler on every replica; the advisory lock
+// taken by postgres.ScheduleRepository lets only one of them work at a time.
+func startScheduler(ctx context.Context, db *sql.DB) {
+	if !strings.EqualFold(getEnvOrDefault("SCHEDULER_ENABLED", "true"), "true") {
+		log.Println("⚠️  Scheduler disabled, scheduled and expired goldens keep their status")
+		return
+	}
+
+	config := services.DefaultSchedulerConfig()
+	interval, err := time.ParseDuration(getEnvOrDefault("SCHEDULER_INTERVAL", config.Interval.String()))
+	if err != nil || interval <= 0 {
+		log.Fatalf("❌ Invalid SCHEDULER_INTERVAL: %q", getEnvOrDefault("SCHEDULER_INTERVAL", ""))
+	}
+	config.Interval = interval
+
+	scheduler := services.NewScheduler(postgres.NewScheduleRepository(db), config)
+	go scheduler.Run(ctx)
+	log.Printf("⏰ Scheduler started (interval=%s)", interval)
+}
+
+// newIdempotencyInterceptor stores the responses of writes sent with an
+// idempotency key and purges expired keys in the background.
+func newIdempotencyInterceptor(ctx context.Context, db *sql.DB) grpc.UnaryServerInterceptor {
+	store := postgres.NewIdempotencyRepository(db)
+	if err := store.InitSchema(ctx); err != nil {
+		log.Fatalf("❌ Failed to initialize idempotency schema: %v", err)
+	}
+
+	config := grpcserver.DefaultIdempotencyConfig()
+	ttl, err := time.ParseDuration(getEnvOrDefault("IDEMPOTENCY_TTL", config.TTL.String()))
+	if err != nil {
+		log.Fatalf("❌ Invalid IDEMPOTENCY_TTL: %v", err)
+	}
+	config.TTL = ttl
+
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := store.PurgeExpired(ctx); err != nil {
+					log.Printf("⚠️  Failed to purge idempotency keys: %v", err)
+				}
+			}
+		}
+	}()
+	log.Printf("🔁 Idempotency keys enabled (ttl=%s)", ttl)
+
+	return grpcserver.UnaryIdempotency(store, config)
+}
+
+// newKafkaPublisher is set by kafka_publisher.go when building with the kafka
+// tag, which links the Kafka client.
+var newKafkaPublisher func(source string) domain.EventPublisher
+
+// newEventPublisher builds the publishers listed in EVENTS_PUBLISHER
+// (comma separated), fanning out when there are several.
+func newEventPublisher(webhookStore domain.WebhookStore, webhookHosts []string) domain.EventPublisher {
+	source := getEnvOrDefault("EVENTS_SOURCE", "markitos-it-svc-goldens")
+
+	var publishers events.Fanout
+	for _, name := range strings.Split(getEnvOrDefault("EVENTS_PUBLISHER", "webhook"), ",") {
+		switch name = strings.TrimSpace(name); name {
+		case "log":
+			publishers = append(publishers, events.LogPublisher{})
+		case "webhook":
+			config := webhook.DefaultConfig()
+			config.AllowedHosts = webhookHosts
+			publishers = append(publishers, webhook.NewDispatcher(webhookStore, nil, config))
+		case "nats":
+			publishers = append(publishers, newNATSPublisher(source))
+		case "kafka":
+			if newKafkaPublisher == nil {
+				log.Fatalf("❌ EVENTS_PUBLISHER=kafka requires building with -tags kafka")
+			}
+			publishers = append(publishers, newKafkaPublisher(source))
+		default:
+			log.Fatalf("❌ Unknown EVENTS_PUBLISHER %q (expected log, webhook, nats or kafka)", name)
+		}
+	}
+
+	if len(publishers) == 1 {
+		return publishers[0]
+	}
+	return publishers
+}
+
+func newNATSPublisher(source string) domain.EventPublisher {
+	url := getEnvOrDefault("NATS_URL", nats.DefaultURL)
+	nc, err := nats.Connect(url, nats.Name("markitos-it-svc-goldens"), nats.MaxReconnects(-1))
+	if err != nil {
+		log.Fatalf("❌ Failed to connect to NATS at %s: %v", url, err)
+	}
+
+	publisher, err := events.NewNATSPublisher(nc, events.NATSConfig{
+		SubjectTemplate: getEnvOrDefault("NATS_SUBJECT", "goldens.{type}"),
+		Source:          source,
+		Stream:          getEnvOrDefault("NATS_STREAM", "GOLDENS"),
+	})
+	if err != nil {
+		log.Fatalf("❌ Failed to set up NATS publisher: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := publisher.EnsureStream(ctx); err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	log.Printf("📡 Publishing events to NATS JetStream at %s", url)
+
+	return publisher
+}
+
+func loadSQLite() (*sql.DB, *sqlite.GoldenRepository) {
+	path := getEnvOrDefault("SQLITE_PATH", "goldens.db")
+	db, err := sql.Open(sqlite.DriverName, sqlite.DSN(path))
+	if err != nil {
+		log.Fatalf("❌ Failed to open SQLite database (is the binary built with -tags sqlite?): %v", err)
+	}
+	// A single connection serializes writers and avoids SQLITE_BUSY errors.
+	db.SetMaxOpenConns(1)
+	if err := db.Ping(); err != nil {
+		log.Fatalf("❌ Failed to open SQLite database %s: %v", path, err)
+	}
+	log.Printf("✅ Connected to SQLite at %s", path)
+
+	return db, sqlite.NewGoldenRepository(db)
+}
+
+func loadFilesystem(ctx context.Context) *filesystem.GoldenRepository {
+	root := getEnvRequired("FS_ROOT")
+	writable := strings.EqualFold(getEnvOrDefault("FS_WRITABLE", "false"), "true")
+	interval, err := time.ParseDuration(getEnvOrDefault("FS_POLL_INTERVAL", "5s"))
+	if err != nil {
+		log.Fatalf("❌ Invalid FS_POLL_INTERVAL: %v", err)
+	}
+
+	repo := filesystem.NewGoldenRepository(root, writable)
+	if err := repo.Load(); err != nil {
+		log.Fatalf("❌ Failed to load goldens from %s: %v", root, err)
+	}
+	go repo.Watch(ctx, interval)
+	log.Printf("📂 Serving goldens from %s (writable=%t, poll=%s)", root, writable, interval)
+
+	return repo
+}
+
+func postgresDSN() string {
 	dbHost := getEnvRequired("DB_HOST")
 	dbPort := getEnvRequired("DB_PORT")
 	dbUser := getEnvRequired("DB_USER")
 	dbPass := getEnvRequired("DB_PASS")
 	dbName := getEnvRequired("DB_NAME")
-	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
 		dbHost, dbPort, dbUser, dbPass, dbName)
-	db, err := sql.Open("postgres", dsn)
+}
+
+// loadReadReplica routes reads to DB_READ_DSN when it is set. The replica is
+// only used once its health check passes, so it may be down at startup.
+func loadReadReplica(ctx context.Context, repo *postgres.GoldenRepository) func() {
+	dsn := os.Getenv("DB_READ_DSN")
+	if dsn == "" {
+		return func() {}
+	}
+
+	config := postgres.DefaultReplicaConfig()
+	maxLag, err := time.ParseDuration(getEnvOrDefault("DB_REPLICA_MAX_LAG", config.MaxLag.String()))
+	if err != nil {
+		log.Fatalf("❌ Invalid DB_REPLICA_MAX_LAG: %v", err)
+	}
+	interval, err := time.ParseDuration(getEnvOrDefault("DB_REPLICA_CHECK_INTERVAL", config.CheckInterval.String()))
+	if err != nil {
+		log.Fatalf("❌ Invalid DB_REPLICA_CHECK_INTERVAL: %v", err)
+	}
+	config.MaxLag = maxLag
+	config.CheckInterval = interval
+
+	replica, err := sql.Open("postgres", dsn)
+	if err != nil {
+		log.Fatalf("❌ Invalid DB_READ_DSN: %v", err)
+	}
+	monitor := postgres.NewReplicaMonitor(replica, config)
+	repo.SetReadReplica(replica, monitor)
+	go monitor.Run(ctx)
+	log.Printf("📖 Read replica configured (max_lag=%s, check=%s)", maxLag, interval)
+
+	return func() { replica.Close() }
+}
+
+func loadDatabase() (*sql.DB, *postgres.GoldenRepository) {
+	log.Println("🚀 loading database")
+	db, err := sql.Open("postgres", postgresDSN())
 	if err != nil {
 		log.Fatalf("❌ Failed to connect to database: %v", err)
 	}
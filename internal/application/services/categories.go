@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"markitos-it-svc-goldens/internal/domain"
+	"slices"
+	"time"
+)
+
+type CategoryService struct {
+	store domain.CategoryStore
+}
+
+func NewCategoryService(store domain.CategoryStore) *CategoryService {
+	return &CategoryService{
+		store: store,
+	}
+}
+
+// ListCategories returns the whole tree ordered as domain.SortCategoryTree.
+func (s *CategoryService) ListCategories(ctx context.Context) ([]domain.Category, error) {
+	categories, err := s.store.ListCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return domain.SortCategoryTree(categories), nil
+}
+
+func (s *CategoryService) GetCategory(ctx context.Context, id string) (*domain.Category, error) {
+	return s.store.GetCategory(ctx, id)
+}
+
+// CreateCategory derives the id from the display name when category has
+// none.
+func (s *CategoryService) CreateCategory(ctx context.Context, category *domain.Category) error {
+	if err := domain.RequireEditor(ctx); err != nil {
+		return err
+	}
+	if category.ID == "" {
+		category.ID = domain.Slugify(category.DisplayName)
+	}
+	if err := category.Validate(); err != nil {
+		return err
+	}
+
+	category.CreatedAt = time.Now().UTC()
+	category.UpdatedAt = category.CreatedAt
+	return s.store.CreateCategory(ctx, category)
+}
+
+// UpdateCategory may move category to another parent, but not below itself.
+func (s *CategoryService) UpdateCategory(ctx context.Context, category *domain.Category) error {
+	if err := domain.RequireEditor(ctx); err != nil {
+		return err
+	}
+	if err := category.Validate(); err != nil {
+		return err
+	}
+
+	if category.ParentID != "" {
+		subtree, err := s.store.CategorySubtree(ctx, category.ID)
+		if err != nil {
+			return err
+		}
+		if slices.Contains(subtree, category.ParentID) {
+			return fmt.Errorf("%w: %s cannot move below its own subcategory %s", domain.ErrInvalidCategory, category.ID, category.ParentID)
+		}
+	}
+
+	category.UpdatedAt = time.Now().UTC()
+	return s.store.UpdateCategory(ctx, category)
+}
+
+func (s *CategoryService) DeleteCategory(ctx context.Context, id string) error {
+	if err := domain.RequireEditor(ctx); err != nil {
+		return err
+	}
+	return s.store.DeleteCategory(ctx, id)
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"markitos-it-svc-goldens/internal/domain"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// fakeCategoryStore keeps categories in memory and records what was written.
+type fakeCategoryStore struct {
+	categories []domain.Category
+	created    []domain.Category
+	updated    []domain.Category
+}
+
+func helperCategoryStore() *fakeCategoryStore {
+	return &fakeCategoryStore{categories: []domain.Category{
+		{ID: "devops", DisplayName: "DevOps"},
+		{ID: "ci-cd", ParentID: "devops", DisplayName: "CI/CD", Position: 1},
+		{ID: "gitops", ParentID: "devops", DisplayName: "GitOps", Position: 2},
+		{ID: "apis", DisplayName: "APIs"},
+	}}
+}
+
+func (s *fakeCategoryStore) ListCategories(ctx context.Context) ([]domain.Category, error) {
+	return slices.Clone(s.categories), nil
+}
+
+func (s *fakeCategoryStore) GetCategory(ctx context.Context, id string) (*domain.Category, error) {
+	for _, c := range s.categories {
+		if c.ID == id {
+			return &c, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", domain.ErrCategoryNotFound, id)
+}
+
+func (s *fakeCategoryStore) CreateCategory(ctx context.Context, category *domain.Category) error {
+	s.created = append(s.created, *category)
+	return nil
+}
+
+func (s *fakeCategoryStore) UpdateCategory(ctx context.Context, category *domain.Category) error {
+	s.updated = append(s.updated, *category)
+	return nil
+}
+
+func (s *fakeCategoryStore) DeleteCategory(ctx context.Context, id string) error {
+	return fmt.Errorf("%w: %s", domain.ErrCategoryInUse, id)
+}
+
+func (s *fakeCategoryStore) ResolveCategory(ctx context.Context, value string) (string, error) {
+	for _, c := range s.categories {
+		if domain.CategoryKey(c.ID) == domain.CategoryKey(value) {
+			return c.ID, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", domain.ErrCategoryNotFound, value)
+}
+
+func (s *fakeCategoryStore) CategorySubtree(ctx context.Context, id string) ([]string, error) {
+	if _, err := s.GetCategory(ctx, id); err != nil {
+		return nil, err
+	}
+	ids := []string{id}
+	for i := 0; i < len(ids); i++ {
+		for _, c := range s.categories {
+			if c.ParentID == ids[i] {
+				ids = append(ids, c.ID)
+			}
+		}
+	}
+	return ids, nil
+}
+
+func TestCategoryService_ListCategories_SortsTree(t *testing.T) {
+	store := helperCategoryStore()
+	slices.Reverse(store.categories)
+
+	categories, err := NewCategoryService(store).ListCategories(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var ids []string
+	for _, c := range categories {
+		ids = append(ids, c.ID)
+	}
+	if want := []string{"apis", "devops", "ci-cd", "gitops"}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("ListCategories() = %v, want %v", ids, want)
+	}
+}
+
+func TestCategoryService_CreateCategory_DerivesID(t *testing.T) {
+	store := helperCategoryStore()
+	svc := NewCategoryService(store)
+
+	category := &domain.Category{ParentID: "devops", DisplayName: "Platform Engineering"}
+	if err := svc.CreateCategory(helperEditorContext(), category); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if category.ID != "platform-engineering" || category.CreatedAt.IsZero() || !category.UpdatedAt.Equal(category.CreatedAt) {
+		t.Fatalf("CreateCategory() = %+v, want derived id and timestamps", category)
+	}
+	if len(store.created) != 1 {
+		t.Fatalf("expected the category to be stored, got %+v", store.created)
+	}
+
+	if err := svc.CreateCategory(context.Background(), &domain.Category{DisplayName: "Reader"}); !errors.Is(err, domain.ErrEditorRequired) {
+		t.Fatalf("CreateCategory() by a reader err = %v, want ErrEditorRequired", err)
+	}
+
+	if err := svc.CreateCategory(helperEditorContext(), &domain.Category{DisplayName: "!!"}); !errors.Is(err, domain.ErrInvalidCategory) {
+		t.Fatalf("CreateCategory() err = %v, want ErrInvalidCategory", err)
+	}
+}
+
+func TestCategoryService_UpdateCategory_RejectsCycles(t *testing.T) {
+	store := helperCategoryStore()
+	svc := NewCategoryService(store)
+	ctx := helperEditorContext()
+
+	move := &domain.Category{ID: "devops", ParentID: "gitops", DisplayName: "DevOps"}
+	if err := svc.UpdateCategory(ctx, move); !errors.Is(err, domain.ErrInvalidCategory) {
+		t.Fatalf("UpdateCategory() err = %v, want ErrInvalidCategory", err)
+	}
+
+	move = &domain.Category{ID: "gitops", ParentID: "apis", DisplayName: "GitOps"}
+	if err := svc.UpdateCategory(ctx, move); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(store.updated) != 1 || store.updated[0].UpdatedAt.IsZero() {
+		t.Fatalf("expected one stored update, got %+v", store.updated)
+	}
+
+	missing := &domain.Category{ID: "gone", ParentID: "apis", DisplayName: "Gone"}
+	if err := svc.UpdateCategory(ctx, missing); !errors.Is(err, domain.ErrCategoryNotFound) {
+		t.Fatalf("UpdateCategory() err = %v, want ErrCategoryNotFound", err)
+	}
+}
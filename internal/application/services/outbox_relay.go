@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"log"
+	"markitos-it-svc-goldens/internal/domain"
+	"time"
+)
+
+type OutboxRelayConfig struct {
+	BatchSize    int
+	PollInterval time.Duration
+	// Lease is how long a claimed event stays hidden from other relays.
+	Lease time.Duration
+	// MaxAttempts is the number of deliveries tried before an event is
+	// moved to the dead-letter state.
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+func DefaultOutboxRelayConfig() OutboxRelayConfig {
+	return OutboxRelayConfig{
+		BatchSize:    50,
+		PollInterval: time.Second,
+		Lease:        30 * time.Second,
+		MaxAttempts:  10,
+		BaseBackoff:  time.Second,
+		MaxBackoff:   5 * time.Minute,
+	}
+}
+
+// OutboxRelay delivers outbox events to a publisher. Events are only marked
+// delivered after Publish succeeds, so a crash in between redelivers them:
+// consumers must tolerate duplicates and can deduplicate on Event.ID.
+type OutboxRelay struct {
+	store     domain.OutboxStore
+	publisher domain.EventPublisher
+	config    OutboxRelayConfig
+}
+
+func NewOutboxRelay(store domain.OutboxStore, publisher domain.EventPublisher, config OutboxRelayConfig) *OutboxRelay {
+	return &OutboxRelay{
+		store:     store,
+		publisher: publisher,
+		config:    config,
+	}
+}
+
+// Run relays events until ctx is done. A full batch is followed immediately
+// by the next one; otherwise the relay waits for PollInterval.
+func (r *OutboxRelay) Run(ctx context.Context) {
+	for {
+		n, err := r.RelayOnce(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("⚠️  Outbox relay failed: %v", err)
+		}
+
+		wait := r.config.PollInterval
+		if err == nil && n == r.config.BatchSize {
+			wait = 0
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// RelayOnce claims one batch of due events and tries to publish each of
+// them, returning how many were claimed. Publishing stops when the lease
+// runs out, since other relays may claim the events from then on; the
+// events left over are picked up again once their lease expires.
+func (r *OutboxRelay) RelayOnce(ctx context.Context) (int, error) {
+	leaseCtx, cancel := context.WithTimeout(ctx, r.config.Lease)
+	defer cancel()
+
+	records, err := r.store.ClaimPending(ctx, r.config.BatchSize, r.config.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, record := range records {
+		if err := r.publisher.Publish(leaseCtx, record.Event); err != nil {
+			if leaseCtx.Err() != nil {
+				// The lease expires and another pass picks the event up.
+				return len(records), ctx.Err()
+			}
+
+			dead := record.Attempts >= r.config.MaxAttempts
+			if dead {
+				log.Printf("⚠️  Outbox event %s (%s) dead-lettered after %d attempts: %v", record.ID, record.Type, record.Attempts, err)
+			}
+			if err := r.store.MarkFailed(ctx, record.ID, err, r.backoff(record.Attempts), dead); err != nil {
+				return len(records), err
+			}
+			continue
+		}
+
+		if err := r.store.MarkDelivered(ctx, record.ID); err != nil {
+			return len(records), err
+		}
+	}
+
+	return len(records), nil
+}
+
+// backoff doubles BaseBackoff per attempt, capped at MaxBackoff.
+func (r *OutboxRelay) backoff(attempts int) time.Duration {
+	delay := r.config.BaseBackoff
+	for i := 1; i < attempts && delay < r.config.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, r.config.MaxBackoff)
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"markitos-it-svc-goldens/internal/domain"
+	"testing"
+	"time"
+)
+
+// memoryOutbox is an in-memory domain.OutboxStore without leases.
+type memoryOutbox struct {
+	pending   []domain.OutboxRecord
+	delivered []string
+	failed    map[string]time.Duration
+	dead      []string
+}
+
+func (o *memoryOutbox) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]domain.OutboxRecord, error) {
+	n := min(limit, len(o.pending))
+	claimed := o.pending[:n]
+	o.pending = o.pending[n:]
+	for i := range claimed {
+		claimed[i].Attempts++
+	}
+	return claimed, nil
+}
+
+func (o *memoryOutbox) MarkDelivered(ctx context.Context, eventID string) error {
+	o.delivered = append(o.delivered, eventID)
+	return nil
+}
+
+func (o *memoryOutbox) MarkFailed(ctx context.Context, eventID string, cause error, retryIn time.Duration, dead bool) error {
+	if dead {
+		o.dead = append(o.dead, eventID)
+		return nil
+	}
+	if o.failed == nil {
+		o.failed = make(map[string]time.Duration)
+	}
+	o.failed[eventID] = retryIn
+	return nil
+}
+
+// flakyPublisher fails every event whose id is in fail.
+type flakyPublisher struct {
+	fail      map[string]bool
+	published []string
+}
+
+func (p *flakyPublisher) Publish(ctx context.Context, event domain.Event) error {
+	if p.fail[event.ID] {
+		return errors.New("broker unavailable")
+	}
+	p.published = append(p.published, event.ID)
+	return nil
+}
+
+func TestOutboxRelay_RelayOnce(t *testing.T) {
+	store := &memoryOutbox{pending: []domain.OutboxRecord{
+		{Event: domain.Event{ID: "ok"}},
+		{Event: domain.Event{ID: "retry"}, Attempts: 1},
+		{Event: domain.Event{ID: "dead"}, Attempts: 2},
+	}}
+	publisher := &flakyPublisher{fail: map[string]bool{"retry": true, "dead": true}}
+	config := DefaultOutboxRelayConfig()
+	config.MaxAttempts = 3
+
+	n, err := NewOutboxRelay(store, publisher, config).RelayOnce(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 3 {
+		t.Fatalf("expected 3 claimed events, got %d", n)
+	}
+	if len(store.delivered) != 1 || store.delivered[0] != "ok" {
+		t.Errorf("expected only ok delivered, got %v", store.delivered)
+	}
+	if retryIn, ok := store.failed["retry"]; !ok || retryIn != 2*config.BaseBackoff {
+		t.Errorf("expected retry rescheduled in %v, got %v (ok=%v)", 2*config.BaseBackoff, retryIn, ok)
+	}
+	if len(store.dead) != 1 || store.dead[0] != "dead" {
+		t.Errorf("expected dead dead-lettered, got %v", store.dead)
+	}
+}
+
+// blockingPublisher holds every event until its context is done.
+type blockingPublisher struct {
+	calls int
+}
+
+func (p *blockingPublisher) Publish(ctx context.Context, event domain.Event) error {
+	p.calls++
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestOutboxRelay_RelayOnce_StopsAtLeaseEnd(t *testing.T) {
+	store := &memoryOutbox{pending: []domain.OutboxRecord{
+		{Event: domain.Event{ID: "slow"}},
+		{Event: domain.Event{ID: "next"}},
+	}}
+	publisher := &blockingPublisher{}
+	config := DefaultOutboxRelayConfig()
+	config.Lease = 10 * time.Millisecond
+
+	n, err := NewOutboxRelay(store, publisher, config).RelayOnce(context.Background())
+	if err != nil || n != 2 {
+		t.Fatalf("RelayOnce() = %d, %v; want 2 claimed and no error", n, err)
+	}
+	if publisher.calls != 1 {
+		t.Errorf("expected publishing to stop at the lease end, got %d calls", publisher.calls)
+	}
+	if len(store.delivered) != 0 || len(store.failed) != 0 || len(store.dead) != 0 {
+		t.Errorf("expected the events left to their lease, got delivered=%v failed=%v dead=%v", store.delivered, store.failed, store.dead)
+	}
+}
+
+func TestOutboxRelay_Backoff(t *testing.T) {
+	relay := NewOutboxRelay(nil, nil, OutboxRelayConfig{BaseBackoff: time.Second, MaxBackoff: 5 * time.Second})
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: time.Second},
+		{attempts: 2, want: 2 * time.Second},
+		{attempts: 3, want: 4 * time.Second},
+		{attempts: 4, want: 5 * time.Second},
+		{attempts: 40, want: 5 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := relay.backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d): want %v, got %v", tt.attempts, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"log"
+	"markitos-it-svc-goldens/internal/domain"
+	"time"
+)
+
+type SchedulerConfig struct {
+	BatchSize int
+	// Interval is the longest the scheduler sleeps, which bounds how late it
+	// notices a publish_at or expire_at set after it went to sleep.
+	Interval time.Duration
+}
+
+func DefaultSchedulerConfig() SchedulerConfig {
+	return SchedulerConfig{
+		BatchSize: 100,
+		Interval:  time.Minute,
+	}
+}
+
+// Reasons recorded with the transitions the scheduler applies.
+const (
+	ReasonScheduledPublish = "scheduled publication"
+	ReasonExpired          = "expired"
+)
+
+// Scheduler publishes scheduled goldens at their publish_at and archives
+// published ones at their expire_at, recording the transitions and emitting
+// GoldenStatusChanged events like TransitionGolden does. Readers stop or
+// start seeing the goldens at those times anyway (see domain.Golden.Live);
+// the scheduler makes the stored status and the events catch up.
+type Scheduler struct {
+	store  domain.ScheduleStore
+	config SchedulerConfig
+}
+
+func NewScheduler(store domain.ScheduleStore, config SchedulerConfig) *Scheduler {
+	return &Scheduler{
+		store:  store,
+		config: config,
+	}
+}
+
+// Run applies due transitions until ctx is done. Between runs it sleeps
+// until the next publish_at or expire_at, at most Interval; a full batch is
+// followed immediately by the next one.
+func (s *Scheduler) Run(ctx context.Context) {
+	for {
+		n, err := s.RunOnce(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("⚠️  Scheduler failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.wait(ctx, n, err)):
+		}
+	}
+}
+
+// wait returns how long Run sleeps after a run that changed n goldens. A
+// next due time in the past means the run failed, or that another replica
+// or an editor holds the due goldens, so it waits the whole Interval rather
+// than spinning until they are released.
+func (s *Scheduler) wait(ctx context.Context, n int, err error) time.Duration {
+	if err != nil {
+		return s.config.Interval
+	}
+	if n == s.config.BatchSize {
+		return 0
+	}
+
+	next, err := s.store.NextDue(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("⚠️  Scheduler failed to read the next due golden: %v", err)
+		}
+		return s.config.Interval
+	}
+	if until := time.Until(next); until > 0 {
+		return min(s.config.Interval, until)
+	}
+	return s.config.Interval
+}
+
+// RunOnce applies the transitions due now to one batch of goldens and
+// returns how many goldens it changed. It changes none when the scheduler of
+// another replica is running.
+func (s *Scheduler) RunOnce(ctx context.Context) (int, error) {
+	// The scheduler reads the goldens it changes whatever their status.
+	ctx = domain.WithEditor(ctx)
+	now := time.Now().UTC()
+	changed := 0
+	_, err := s.store.RunDue(ctx, now, s.config.BatchSize, func(tx domain.Repository, ids []string) error {
+		changed = 0
+		for _, id := range ids {
+			doc, err := tx.GetByID(ctx, id, domain.GoldenViewFull)
+			if err != nil {
+				return err
+			}
+
+			// A golden whose publish_at and expire_at both passed while no
+			// scheduler ran is published then archived, in that order.
+			for {
+				to, at, ok := doc.DueTransition(now)
+				if !ok {
+					break
+				}
+				reason := ReasonScheduledPublish
+				if to == domain.StatusArchived {
+					reason = ReasonExpired
+				}
+				transition, err := doc.Transition(to, reason, at)
+				if err != nil {
+					return err
+				}
+				if err := recordTransition(ctx, tx, doc, transition); err != nil {
+					return err
+				}
+			}
+			changed++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return changed, nil
+}
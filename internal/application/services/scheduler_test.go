@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"markitos-it-svc-goldens/internal/domain"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+// memorySchedule is a domain.ScheduleStore over a workflowRepo. locked
+// stands for the scheduler of another replica holding the lock, and next is
+// what NextDue returns.
+type memorySchedule struct {
+	repo   *workflowRepo
+	locked bool
+	next   time.Time
+}
+
+func (s *memorySchedule) RunDue(ctx context.Context, now time.Time, limit int, fn func(tx domain.Repository, ids []string) error) (bool, error) {
+	if s.locked {
+		return false, nil
+	}
+
+	var ids []string
+	for id, doc := range s.repo.docs {
+		if _, _, ok := doc.DueTransition(now); ok {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+	return true, fn(s.repo, ids)
+}
+
+func (s *memorySchedule) NextDue(ctx context.Context) (time.Time, error) {
+	return s.next, nil
+}
+
+func TestScheduler_RunOnce(t *testing.T) {
+	now := time.Now().UTC()
+	repo := helperWorkflowRepo()
+	repo.docs["due"] = domain.Golden{ID: "due", Status: domain.StatusScheduled, PublishAt: now.Add(-time.Minute)}
+	repo.docs["later"] = domain.Golden{ID: "later", Status: domain.StatusScheduled, PublishAt: now.Add(time.Hour)}
+	repo.docs["expired"] = domain.Golden{ID: "expired", Status: domain.StatusPublished, ExpireAt: now.Add(-time.Minute)}
+	repo.docs["missed"] = domain.Golden{ID: "missed", Status: domain.StatusScheduled, PublishAt: now.Add(-time.Hour), ExpireAt: now.Add(-time.Minute)}
+	store := &memorySchedule{repo: repo}
+
+	n, err := NewScheduler(store, DefaultSchedulerConfig()).RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("RunOnce() unexpected error: %v", err)
+	}
+	if n != 3 {
+		t.Fatalf("RunOnce() changed %d goldens, want 3", n)
+	}
+
+	for id, want := range map[string]domain.GoldenStatus{
+		"due":     domain.StatusPublished,
+		"later":   domain.StatusScheduled,
+		"expired": domain.StatusArchived,
+		"missed":  domain.StatusArchived,
+	} {
+		if got := repo.docs[id].Status; got != want {
+			t.Errorf("status of %s = %s, want %s", id, got, want)
+		}
+	}
+	if !repo.docs["due"].PublishedAt.Equal(repo.docs["due"].PublishAt) {
+		t.Errorf("due published at %v, want its publish_at", repo.docs["due"].PublishedAt)
+	}
+
+	var got []string
+	for _, transition := range repo.transitions {
+		got = append(got, transition.GoldenID+":"+string(transition.To)+":"+transition.Reason)
+	}
+	want := []string{
+		"due:published:" + ReasonScheduledPublish,
+		"expired:archived:" + ReasonExpired,
+		"missed:published:" + ReasonScheduledPublish,
+		"missed:archived:" + ReasonExpired,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("transitions = %v, want %v", got, want)
+	}
+	if len(repo.events) != len(want) || repo.events[0].Type != domain.EventGoldenStatusChanged {
+		t.Fatalf("events = %+v, want one GoldenStatusChanged per transition", repo.events)
+	}
+}
+
+func TestScheduler_RunOnce_SkipsWhileAnotherReplicaRuns(t *testing.T) {
+	repo := helperWorkflowRepo()
+	repo.docs["due"] = domain.Golden{ID: "due", Status: domain.StatusScheduled, PublishAt: time.Now().Add(-time.Minute)}
+
+	n, err := NewScheduler(&memorySchedule{repo: repo, locked: true}, DefaultSchedulerConfig()).RunOnce(context.Background())
+	if err != nil || n != 0 {
+		t.Fatalf("RunOnce() = %d, %v; want nothing changed", n, err)
+	}
+	if repo.docs["due"].Status != domain.StatusScheduled {
+		t.Fatalf("status of due = %s, want it left scheduled", repo.docs["due"].Status)
+	}
+}
+
+func TestScheduler_Wait(t *testing.T) {
+	config := SchedulerConfig{BatchSize: 10, Interval: time.Minute}
+	tests := []struct {
+		name string
+		next time.Time
+		n    int
+		err  error
+		want func(time.Duration) bool
+	}{
+		{name: "full batch", n: 10, want: func(d time.Duration) bool { return d == 0 }},
+		{name: "failed run", next: time.Now().Add(-time.Minute), err: errors.New("boom"), want: func(d time.Duration) bool { return d == time.Minute }},
+		{name: "nothing scheduled", want: func(d time.Duration) bool { return d == time.Minute }},
+		{name: "held by another replica", next: time.Now().Add(-time.Minute), want: func(d time.Duration) bool { return d == time.Minute }},
+		{name: "due before interval", next: time.Now().Add(10 * time.Second), n: 1, want: func(d time.Duration) bool { return d > 0 && d <= 10*time.Second }},
+		{name: "due after interval", next: time.Now().Add(time.Hour), want: func(d time.Duration) bool { return d == time.Minute }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheduler := NewScheduler(&memorySchedule{repo: helperWorkflowRepo(), next: tt.next}, config)
+			if got := scheduler.wait(context.Background(), tt.n, tt.err); !tt.want(got) {
+				t.Fatalf("wait() = %v", got)
+			}
+		})
+	}
+}
@@ -2,35 +2,694 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"markitos-it-svc-goldens/internal/domain"
+	"time"
 )
 
 type GoldenService struct {
-	repo domain.Repository
+	repo       domain.Repository
+	categories domain.CategoryStore
+	tags       domain.TagStore
 }
 
-func NewGoldenService(repo domain.Repository) *GoldenService {
-	return &GoldenService{
+type GoldenServiceOption func(*GoldenService)
+
+// WithCategories checks golden categories against the category tree in
+// store, replacing each with the id of the category it names, and makes
+// category listings include subcategories. Without it categories are free
+// text.
+func WithCategories(store domain.CategoryStore) GoldenServiceOption {
+	return func(s *GoldenService) {
+		s.categories = store
+	}
+}
+
+// WithTagRegistry rewrites golden tags to their canonical form on every
+// write, mapping synonyms registered in store to the tag they stand for.
+// Without it tags are stored as given.
+func WithTagRegistry(store domain.TagStore) GoldenServiceOption {
+	return func(s *GoldenService) {
+		s.tags = store
+	}
+}
+
+func NewGoldenService(repo domain.Repository, opts ...GoldenServiceOption) *GoldenService {
+	s := &GoldenService{
 		repo: repo,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// GetAllGoldens lists the goldens visible to the caller: only published ones
+// unless ctx carries editor rights, see domain.WithEditor. The same applies to
+// every read below.
+func (s *GoldenService) GetAllGoldens(ctx context.Context, view domain.GoldenView) ([]domain.Golden, error) {
+	docs, err := s.repo.GetAll(ctx, view)
+	if err != nil {
+		return nil, err
+	}
+	return visible(ctx, docs), nil
+}
+
+// GetGoldensByCategory lists the goldens in category and, with WithCategories,
+// in all of its subcategories.
+func (s *GoldenService) GetGoldensByCategory(ctx context.Context, category string, view domain.GoldenView) ([]domain.Golden, error) {
+	categories := []string{category}
+	if s.categories != nil {
+		id, err := s.resolveCategory(ctx, category)
+		if err != nil {
+			return nil, err
+		}
+		if categories, err = s.categories.CategorySubtree(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	docs, err := s.repo.GetByCategories(ctx, categories, view)
+	if err != nil {
+		return nil, err
+	}
+	return visible(ctx, docs), nil
 }
 
-func (s *GoldenService) GetAllGoldens(ctx context.Context) ([]domain.Golden, error) {
-	return s.repo.GetAll(ctx)
+func (s *GoldenService) GetGoldenByID(ctx context.Context, id string, view domain.GoldenView) (*domain.Golden, error) {
+	doc, err := s.repo.GetByID(ctx, id, view)
+	if err != nil {
+		return nil, err
+	}
+	if !doc.Visible(ctx) {
+		return nil, fmt.Errorf("%w: %s", domain.ErrNotFound, id)
+	}
+	return doc, nil
 }
 
-func (s *GoldenService) GetGoldenByID(ctx context.Context, id string) (*domain.Golden, error) {
-	return s.repo.GetByID(ctx, id)
+// GetGoldenBySlug also resolves the former slugs of renamed goldens; callers
+// can compare the returned Slug to redirect to the current one.
+func (s *GoldenService) GetGoldenBySlug(ctx context.Context, slug string, view domain.GoldenView) (*domain.Golden, error) {
+	doc, err := s.repo.GetBySlug(ctx, slug, view)
+	if err != nil {
+		return nil, err
+	}
+	if !doc.Visible(ctx) {
+		return nil, fmt.Errorf("%w: slug %s", domain.ErrNotFound, slug)
+	}
+	return doc, nil
 }
 
+// BatchGetGoldens reads ids with a single repository call and returns the
+// goldens in the order of ids, along with the ids that do not exist or are
+// hidden from the caller. Repeated ids are looked up and returned once.
+func (s *GoldenService) BatchGetGoldens(ctx context.Context, ids []string, view domain.GoldenView) ([]domain.Golden, []string, error) {
+	unique := make([]string, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	if len(unique) == 0 {
+		return nil, nil, nil
+	}
+
+	found, err := s.repo.GetByIDs(ctx, unique, view)
+	if err != nil {
+		return nil, nil, err
+	}
+	byID := make(map[string]domain.Golden, len(found))
+	for _, doc := range visible(ctx, found) {
+		byID[doc.ID] = doc
+	}
+
+	docs := make([]domain.Golden, 0, len(found))
+	var missing []string
+	for _, id := range unique {
+		if doc, ok := byID[id]; ok {
+			docs = append(docs, doc)
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	return docs, missing, nil
+}
+
+// ListCategories counts the published goldens per category. A non-empty tag
+// counts only the goldens tagged with it.
+func (s *GoldenService) ListCategories(ctx context.Context, tag string) ([]domain.FacetCount, error) {
+	return s.repo.CountCategories(ctx, tag)
+}
+
+// ListTags counts the published goldens per tag. A non-empty category counts
+// only the goldens in it and, as in GetGoldensByCategory, its subcategories.
+func (s *GoldenService) ListTags(ctx context.Context, category string) ([]domain.FacetCount, error) {
+	var categories []string
+	switch {
+	case category == "":
+	case s.categories == nil:
+		categories = []string{category}
+	default:
+		id, err := s.resolveCategory(ctx, category)
+		if err != nil {
+			return nil, err
+		}
+		if categories, err = s.categories.CategorySubtree(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.repo.CountTags(ctx, categories)
+}
+
+// CreateGolden generates the id when doc has none and derives a free slug
+// from doc.Slug or the title, adding a "-N" suffix on collisions. New goldens
+// start as drafts.
 func (s *GoldenService) CreateGolden(ctx context.Context, doc *domain.Golden) error {
-	return s.repo.Create(ctx, doc)
+	if err := domain.RequireEditor(ctx); err != nil {
+		return err
+	}
+	if doc.ID == "" {
+		doc.ID = domain.NewID()
+	}
+	resetStatus(doc)
+	doc.Status = domain.StatusDraft
+	if err := s.normalizeCategory(ctx, doc); err != nil {
+		return err
+	}
+	if err := s.normalizeTags(ctx, doc); err != nil {
+		return err
+	}
+
+	requested := doc.Slug
+	return retrySlugTaken(func() error {
+		return s.repo.RunInTx(ctx, func(tx domain.Repository) error {
+			doc.Slug = requested
+			if err := assignSlug(ctx, tx, doc, nil); err != nil {
+				return err
+			}
+			if err := tx.Create(ctx, doc); err != nil {
+				return err
+			}
+			return appendGoldenEvents(ctx, tx, domain.EventGoldenCreated, *doc)
+		})
+	})
+}
+
+type updateOptions struct {
+	clearPublishAt bool
+	clearExpireAt  bool
+}
+
+type UpdateOption func(*updateOptions)
+
+// WithClearPublishAt removes the publish_at of the golden, which an update
+// leaving it unset keeps. A scheduled golden must be moved back to review
+// first.
+func WithClearPublishAt() UpdateOption {
+	return func(o *updateOptions) {
+		o.clearPublishAt = true
+	}
+}
+
+// WithClearExpireAt removes the expire_at of the golden, which an update
+// leaving it unset keeps.
+func WithClearExpireAt() UpdateOption {
+	return func(o *updateOptions) {
+		o.clearExpireAt = true
+	}
 }
 
-func (s *GoldenService) UpdateGolden(ctx context.Context, doc *domain.Golden) error {
-	return s.repo.Update(ctx, doc)
+// UpdateGolden keeps the current slug unless doc.Slug asks for another one,
+// in which case the old slug keeps redirecting to the golden and a "-N"
+// suffix is added if another golden uses the new one. The status is
+// left as stored: it only changes through TransitionGolden. An unset
+// publish_at or expire_at keeps the stored one unless opts clear it.
+func (s *GoldenService) UpdateGolden(ctx context.Context, doc *domain.Golden, opts ...UpdateOption) error {
+	if err := domain.RequireEditor(ctx); err != nil {
+		return err
+	}
+	var options updateOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	resetStatus(doc)
+	if doc.Slug != "" {
+		doc.Slug = domain.Slugify(doc.Slug)
+	}
+	if err := s.normalizeCategory(ctx, doc); err != nil {
+		return err
+	}
+	if err := s.normalizeTags(ctx, doc); err != nil {
+		return err
+	}
+
+	requested := doc.Slug
+	return retrySlugTaken(func() error {
+		return s.repo.RunInTx(ctx, func(tx domain.Repository) error {
+			doc.Slug = requested
+			if doc.Slug != "" {
+				if err := assignRenamedSlug(ctx, tx, doc); err != nil {
+					return err
+				}
+			}
+			if err := keepSchedule(ctx, tx, doc, options); err != nil {
+				return err
+			}
+			if err := tx.Update(ctx, doc); err != nil {
+				return err
+			}
+			return appendGoldenEvents(ctx, tx, domain.EventGoldenUpdated, *doc)
+		})
+	})
 }
 
 func (s *GoldenService) DeleteGolden(ctx context.Context, id string) error {
-	return s.repo.Delete(ctx, id)
+	if err := domain.RequireEditor(ctx); err != nil {
+		return err
+	}
+	// The deleted golden is read first so the event still carries its
+	// category and tags for consumers that filter on them. Repeatable read
+	// makes a concurrent update abort (and retry) the delete instead of the
+	// event describing an outdated row.
+	return s.repo.RunInTx(ctx, func(tx domain.Repository) error {
+		doc, err := tx.GetByID(ctx, id, domain.GoldenViewBasic)
+		if err != nil {
+			return err
+		}
+		if err := tx.Delete(ctx, id); err != nil {
+			return err
+		}
+		return appendGoldenEvents(ctx, tx, domain.EventGoldenDeleted, *doc)
+	}, domain.WithIsolation(domain.IsolationRepeatableRead))
+}
+
+// TransitionGolden moves the golden id to status to, recording the transition
+// with reason in its history. Only editors may change a status.
+func (s *GoldenService) TransitionGolden(ctx context.Context, id string, to domain.GoldenStatus, reason string) (*domain.Golden, error) {
+	if err := domain.RequireEditor(ctx); err != nil {
+		return nil, err
+	}
+
+	var doc *domain.Golden
+	err := s.repo.RunInTx(ctx, func(tx domain.Repository) error {
+		var err error
+		doc, err = tx.GetByID(ctx, id, domain.GoldenViewFull)
+		if err != nil {
+			return err
+		}
+		transition, err := doc.Transition(to, reason, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+		return recordTransition(ctx, tx, doc, transition)
+	}, domain.WithIsolation(domain.IsolationRepeatableRead))
+	if err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// recordTransition stores doc after transition along with its history entry
+// and GoldenStatusChanged event.
+func recordTransition(ctx context.Context, tx domain.Repository, doc *domain.Golden, transition domain.GoldenTransition) error {
+	if err := tx.Update(ctx, doc); err != nil {
+		return err
+	}
+	if err := tx.AppendTransition(ctx, transition); err != nil {
+		return err
+	}
+
+	event, err := domain.NewStatusChangedEvent(*doc, transition)
+	if err != nil {
+		return err
+	}
+	return tx.AppendEvents(ctx, event)
+}
+
+// ListGoldenTransitions returns the status history of the golden id, oldest
+// first. Only editors may read it.
+func (s *GoldenService) ListGoldenTransitions(ctx context.Context, id string) ([]domain.GoldenTransition, error) {
+	if err := domain.RequireEditor(ctx); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetByID(ctx, id, domain.GoldenViewBasic); err != nil {
+		return nil, err
+	}
+	return s.repo.ListTransitions(ctx, id)
+}
+
+// visible drops the goldens the caller may not read, see domain.Golden.Visible.
+func visible(ctx context.Context, docs []domain.Golden) []domain.Golden {
+	if domain.IsEditor(ctx) {
+		return docs
+	}
+
+	kept := docs[:0:0]
+	for _, doc := range docs {
+		if doc.Visible(ctx) {
+			kept = append(kept, doc)
+		}
+	}
+	return kept
+}
+
+// keepSchedule copies the stored publish_at and expire_at to the ones doc
+// leaves unset, unless options clear them, and checks the result against the
+// stored status.
+func keepSchedule(ctx context.Context, tx domain.Repository, doc *domain.Golden, options updateOptions) error {
+	stored, err := tx.GetByID(ctx, doc.ID, domain.GoldenViewBasic)
+	if err != nil {
+		return err
+	}
+	if doc.PublishAt.IsZero() && !options.clearPublishAt {
+		doc.PublishAt = stored.PublishAt
+	}
+	if doc.ExpireAt.IsZero() && !options.clearExpireAt {
+		doc.ExpireAt = stored.ExpireAt
+	}
+
+	if stored.Status == domain.StatusScheduled && doc.PublishAt.IsZero() {
+		return fmt.Errorf("%w: %s is scheduled; move it back to review to clear its publish_at", domain.ErrInvalidTransition, doc.ID)
+	}
+	if !doc.PublishAt.IsZero() && !doc.ExpireAt.IsZero() && !doc.ExpireAt.After(doc.PublishAt) {
+		return fmt.Errorf("%w: expire_at must be after publish_at", domain.ErrInvalidSchedule)
+	}
+	return nil
+}
+
+// resetStatus clears the status fields of doc, which writes other than
+// TransitionGolden do not get to set.
+func resetStatus(doc *domain.Golden) {
+	doc.Status = ""
+	doc.SubmittedAt, doc.PublishedAt, doc.ArchivedAt = time.Time{}, time.Time{}, time.Time{}
+}
+
+// normalizeCategory replaces doc.Category with the id of the category it
+// names, see WithCategories.
+func (s *GoldenService) normalizeCategory(ctx context.Context, doc *domain.Golden) error {
+	if s.categories == nil || doc.Category == "" {
+		return nil
+	}
+
+	id, err := s.resolveCategory(ctx, doc.Category)
+	if err != nil {
+		return err
+	}
+	doc.Category = id
+	return nil
+}
+
+// normalizeTags replaces doc.Tags with their canonical form, see
+// WithTagRegistry.
+func (s *GoldenService) normalizeTags(ctx context.Context, doc *domain.Golden) error {
+	if s.tags == nil || len(doc.Tags) == 0 {
+		return nil
+	}
+
+	index, err := s.tagIndex(ctx)
+	if err != nil {
+		return err
+	}
+	doc.Tags = index.Normalize(doc.Tags)
+	return nil
+}
+
+// tagIndex loads the tag registry, or returns nil when there is none.
+func (s *GoldenService) tagIndex(ctx context.Context) (domain.TagIndex, error) {
+	if s.tags == nil {
+		return nil, nil
+	}
+
+	tags, err := s.tags.ListRegisteredTags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tag registry: %w", err)
+	}
+	return domain.NewTagIndex(tags), nil
+}
+
+func (s *GoldenService) resolveCategory(ctx context.Context, value string) (string, error) {
+	id, err := s.categories.ResolveCategory(ctx, value)
+	if errors.Is(err, domain.ErrCategoryNotFound) {
+		return "", fmt.Errorf("%w: %q", domain.ErrUnknownCategory, value)
+	}
+	return id, err
+}
+
+// assignSlug sets doc.Slug to a slug not used by any stored golden nor in
+// reserved, and adds it to reserved when given.
+func assignSlug(ctx context.Context, tx domain.Repository, doc *domain.Golden, reserved map[string]bool) error {
+	base := doc.BaseSlug()
+	taken, err := tx.SlugsInUse(ctx, base)
+	if err != nil {
+		return err
+	}
+	for slug := range reserved {
+		taken = append(taken, slug)
+	}
+
+	doc.Slug = domain.UniqueSlug(base, taken)
+	if reserved != nil {
+		reserved[doc.Slug] = true
+	}
+	return nil
+}
+
+// assignRenamedSlug keeps doc.Slug when it already belongs to the golden,
+// as its current slug or one it redirects from, and otherwise picks a free
+// one like assignSlug.
+func assignRenamedSlug(ctx context.Context, tx domain.Repository, doc *domain.Golden) error {
+	owner, err := tx.GetBySlug(ctx, doc.Slug, domain.GoldenViewBasic)
+	if err == nil && owner.ID == doc.ID {
+		return nil
+	}
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return err
+	}
+	return assignSlug(ctx, tx, doc, nil)
+}
+
+// slugAttempts bounds how often a write is retried after a concurrent one
+// took the slug it picked.
+const slugAttempts = 3
+
+// retrySlugTaken runs fn again, picking the slugs anew, while it fails with
+// domain.ErrSlugTaken.
+func retrySlugTaken(fn func() error) error {
+	var err error
+	for range slugAttempts {
+		if err = fn(); !errors.Is(err, domain.ErrSlugTaken) {
+			return err
+		}
+	}
+	return err
+}
+
+func appendGoldenEvents(ctx context.Context, tx domain.Repository, eventType domain.EventType, docs ...domain.Golden) error {
+	events := make([]domain.Event, 0, len(docs))
+	for _, doc := range docs {
+		event, err := domain.NewGoldenEvent(eventType, doc)
+		if err != nil {
+			return err
+		}
+		events = append(events, event)
+	}
+
+	return tx.AppendEvents(ctx, events...)
+}
+
+// ImportBatchSize is the number of goldens upserted per transaction.
+const ImportBatchSize = 100
+
+type importOptions struct {
+	resetStatus bool
+}
+
+type ImportOption func(*importOptions)
+
+// WithResetStatus ignores the status, status timestamps, publish_at and
+// expire_at of the imported records: new goldens become drafts and existing
+// ones keep what is stored.
+func WithResetStatus() ImportOption {
+	return func(o *importOptions) {
+		o.resetStatus = true
+	}
+}
+
+// ImportGoldens validates and upserts docs in batches of ImportBatchSize,
+// returning one result per input doc in the same order. A failing batch marks
+// all its records as failed without aborting the rest of the import.
+//
+// Records keep their status, status timestamps, publish_at and expire_at, so
+// an export imports back as it was; records without a status create drafts
+// and leave the status of existing goldens alone. The status is set as is,
+// without a transition in the history.
+func (s *GoldenService) ImportGoldens(ctx context.Context, docs []domain.Golden, dryRun bool, opts ...ImportOption) ([]domain.ImportResult, error) {
+	if err := domain.RequireEditor(ctx); err != nil {
+		return nil, err
+	}
+	var options importOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	results := make([]domain.ImportResult, len(docs))
+	tags, err := s.tagIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var batch []domain.Golden
+	var positions []int
+	inBatch := make(map[string]bool)
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		requested := make([]string, len(batch))
+		ids := make([]string, len(batch))
+		for i := range batch {
+			requested[i], ids[i] = batch[i].Slug, batch[i].ID
+		}
+		var upserted []domain.ImportResult
+		err := retrySlugTaken(func() error {
+			return s.repo.RunInTx(ctx, func(tx domain.Repository) error {
+				// Existing goldens keep their slug, so slugs are only picked
+				// for new goldens.
+				existing, err := tx.GetByIDs(ctx, ids, domain.GoldenViewBasic)
+				if err != nil {
+					return err
+				}
+				slugs := make(map[string]string, len(existing))
+				for _, doc := range existing {
+					slugs[doc.ID] = doc.Slug
+				}
+				reserved := make(map[string]bool, len(batch))
+				for i := range batch {
+					if slug, ok := slugs[batch[i].ID]; ok {
+						batch[i].Slug = slug
+						continue
+					}
+					batch[i].Slug = requested[i]
+					if err := assignSlug(ctx, tx, &batch[i], reserved); err != nil {
+						return err
+					}
+				}
+
+				upserted, err = tx.Upsert(ctx, batch, dryRun)
+				if err != nil || dryRun {
+					return err
+				}
+				return appendImportEvents(ctx, tx, batch, upserted)
+			})
+		})
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+
+		byID := make(map[string]domain.ImportResult, len(upserted))
+		for _, result := range upserted {
+			byID[result.ID] = result
+		}
+		for i, pos := range positions {
+			result, ok := byID[batch[i].ID]
+			switch {
+			case err != nil:
+				result = domain.ImportResult{ID: batch[i].ID, Status: domain.ImportStatusFailed, Err: err}
+			case !ok:
+				result = domain.ImportResult{ID: batch[i].ID, Status: domain.ImportStatusFailed, Err: errors.New("missing upsert result")}
+			}
+			results[pos] = result
+		}
+
+		batch, positions = nil, nil
+		clear(inBatch)
+		return nil
+	}
+
+	for i, doc := range docs {
+		if options.resetStatus {
+			resetStatus(&doc)
+			doc.PublishAt, doc.ExpireAt = time.Time{}, time.Time{}
+		}
+		if err := doc.Validate(); err != nil {
+			results[i] = domain.ImportResult{ID: doc.ID, Status: domain.ImportStatusFailed, Err: err}
+			continue
+		}
+		if err := s.normalizeCategory(ctx, &doc); err != nil {
+			results[i] = domain.ImportResult{ID: doc.ID, Status: domain.ImportStatusFailed, Err: err}
+			continue
+		}
+		if tags != nil {
+			doc.Tags = tags.Normalize(doc.Tags)
+		}
+		if doc.UpdatedAt.IsZero() {
+			doc.UpdatedAt = time.Now().UTC()
+		}
+
+		// The same row cannot be upserted twice in one statement.
+		if inBatch[doc.ID] || len(batch) == ImportBatchSize {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+
+		batch = append(batch, doc)
+		positions = append(positions, i)
+		inBatch[doc.ID] = true
+	}
+
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// appendImportEvents describes updated goldens as stored after the upsert,
+// which may have kept their slug, status or schedule.
+func appendImportEvents(ctx context.Context, tx domain.Repository, batch []domain.Golden, upserted []domain.ImportResult) error {
+	statuses := make(map[string]domain.ImportStatus, len(upserted))
+	for _, result := range upserted {
+		statuses[result.ID] = result.Status
+	}
+
+	var created []domain.Golden
+	var updatedIDs []string
+	for _, doc := range batch {
+		switch statuses[doc.ID] {
+		case domain.ImportStatusCreated:
+			if doc.Status == "" {
+				doc.Status = domain.StatusDraft
+			}
+			created = append(created, doc)
+		case domain.ImportStatusUpdated:
+			updatedIDs = append(updatedIDs, doc.ID)
+		}
+	}
+
+	var updated []domain.Golden
+	if len(updatedIDs) > 0 {
+		stored, err := tx.GetByIDs(ctx, updatedIDs, domain.GoldenViewBasic)
+		if err != nil {
+			return err
+		}
+		byID := make(map[string]domain.Golden, len(stored))
+		for _, doc := range stored {
+			byID[doc.ID] = doc
+		}
+		for _, id := range updatedIDs {
+			if doc, ok := byID[id]; ok {
+				updated = append(updated, doc)
+			}
+		}
+	}
+
+	if err := appendGoldenEvents(ctx, tx, domain.EventGoldenCreated, created...); err != nil {
+		return err
+	}
+	return appendGoldenEvents(ctx, tx, domain.EventGoldenUpdated, updated...)
 }
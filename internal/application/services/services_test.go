@@ -2,32 +2,252 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"markitos-it-svc-goldens/internal/domain"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 // fakeRepo is a happy-path fake: all operations succeed and return predictable data.
 type fakeRepo struct{}
 
-func (fakeRepo) GetAll(ctx context.Context) ([]domain.Golden, error) { return nil, nil }
-func (fakeRepo) GetByID(ctx context.Context, id string) (*domain.Golden, error) {
-	return &domain.Golden{ID: id}, nil
+func (fakeRepo) GetAll(ctx context.Context, view domain.GoldenView) ([]domain.Golden, error) {
+	return nil, nil
+}
+func (fakeRepo) GetByID(ctx context.Context, id string, view domain.GoldenView) (*domain.Golden, error) {
+	return &domain.Golden{ID: id, Status: domain.StatusPublished}, nil
+}
+func (fakeRepo) GetByIDs(ctx context.Context, ids []string, view domain.GoldenView) ([]domain.Golden, error) {
+	docs := make([]domain.Golden, 0, len(ids))
+	for _, id := range ids {
+		docs = append(docs, domain.Golden{ID: id, Status: domain.StatusPublished})
+	}
+	return docs, nil
+}
+func (fakeRepo) GetByCategories(ctx context.Context, categories []string, view domain.GoldenView) ([]domain.Golden, error) {
+	docs := make([]domain.Golden, 0, len(categories))
+	for _, category := range categories {
+		docs = append(docs, domain.Golden{ID: "in-" + category, Category: category, Status: domain.StatusPublished})
+	}
+	return docs, nil
+}
+func (fakeRepo) GetBySlug(ctx context.Context, slug string, view domain.GoldenView) (*domain.Golden, error) {
+	return &domain.Golden{ID: slug, Slug: slug, Status: domain.StatusPublished}, nil
+}
+func (fakeRepo) SlugsInUse(ctx context.Context, base string) ([]string, error) { return nil, nil }
+func (fakeRepo) CountCategories(ctx context.Context, tag string) ([]domain.FacetCount, error) {
+	return []domain.FacetCount{{Value: "DevOps", Count: 2}}, nil
+}
+func (fakeRepo) CountTags(ctx context.Context, categories []string) ([]domain.FacetCount, error) {
+	return []domain.FacetCount{{Value: "k8s" + strings.Join(categories, ","), Count: 2}}, nil
 }
 func (fakeRepo) Create(ctx context.Context, doc *domain.Golden) error { return nil }
 func (fakeRepo) Update(ctx context.Context, doc *domain.Golden) error { return nil }
 func (fakeRepo) Delete(ctx context.Context, id string) error          { return nil }
+func (fakeRepo) Upsert(ctx context.Context, docs []domain.Golden, dryRun bool) ([]domain.ImportResult, error) {
+	results := make([]domain.ImportResult, 0, len(docs))
+	for _, doc := range docs {
+		results = append(results, domain.ImportResult{ID: doc.ID, Status: domain.ImportStatusCreated})
+	}
+	return results, nil
+}
+func (fakeRepo) AppendEvents(ctx context.Context, events ...domain.Event) error { return nil }
+func (fakeRepo) AppendTransition(ctx context.Context, transition domain.GoldenTransition) error {
+	return nil
+}
+func (fakeRepo) ListTransitions(ctx context.Context, id string) ([]domain.GoldenTransition, error) {
+	return nil, nil
+}
+func (r fakeRepo) RunInTx(ctx context.Context, fn func(tx domain.Repository) error, opts ...domain.TxOption) error {
+	return fn(r)
+}
 
 // failingRepo always returns an error on every operation.
 type failingRepo struct {
 	err error
 }
 
-func (r failingRepo) GetAll(ctx context.Context) ([]domain.Golden, error)            { return nil, r.err }
-func (r failingRepo) GetByID(ctx context.Context, id string) (*domain.Golden, error) { return nil, r.err }
-func (r failingRepo) Create(ctx context.Context, doc *domain.Golden) error            { return r.err }
-func (r failingRepo) Update(ctx context.Context, doc *domain.Golden) error            { return r.err }
-func (r failingRepo) Delete(ctx context.Context, id string) error                    { return r.err }
+func (r failingRepo) GetAll(ctx context.Context, view domain.GoldenView) ([]domain.Golden, error) {
+	return nil, r.err
+}
+func (r failingRepo) GetByID(ctx context.Context, id string, view domain.GoldenView) (*domain.Golden, error) {
+	return nil, r.err
+}
+func (r failingRepo) GetByIDs(ctx context.Context, ids []string, view domain.GoldenView) ([]domain.Golden, error) {
+	return nil, r.err
+}
+func (r failingRepo) GetByCategories(ctx context.Context, categories []string, view domain.GoldenView) ([]domain.Golden, error) {
+	return nil, r.err
+}
+func (r failingRepo) GetBySlug(ctx context.Context, slug string, view domain.GoldenView) (*domain.Golden, error) {
+	return nil, r.err
+}
+func (r failingRepo) SlugsInUse(ctx context.Context, base string) ([]string, error) {
+	return nil, r.err
+}
+func (r failingRepo) CountCategories(ctx context.Context, tag string) ([]domain.FacetCount, error) {
+	return nil, r.err
+}
+func (r failingRepo) CountTags(ctx context.Context, categories []string) ([]domain.FacetCount, error) {
+	return nil, r.err
+}
+func (r failingRepo) Create(ctx context.Context, doc *domain.Golden) error { return r.err }
+func (r failingRepo) Update(ctx context.Context, doc *domain.Golden) error { return r.err }
+func (r failingRepo) Delete(ctx context.Context, id string) error          { return r.err }
+func (r failingRepo) Upsert(ctx context.Context, docs []domain.Golden, dryRun bool) ([]domain.ImportResult, error) {
+	return nil, r.err
+}
+func (r failingRepo) AppendEvents(ctx context.Context, events ...domain.Event) error { return r.err }
+func (r failingRepo) AppendTransition(ctx context.Context, transition domain.GoldenTransition) error {
+	return r.err
+}
+func (r failingRepo) ListTransitions(ctx context.Context, id string) ([]domain.GoldenTransition, error) {
+	return nil, r.err
+}
+func (r failingRepo) RunInTx(ctx context.Context, fn func(tx domain.Repository) error, opts ...domain.TxOption) error {
+	return fn(r)
+}
+
+// batchGetRepo returns the existing ids backwards and records every lookup.
+type batchGetRepo struct {
+	fakeRepo
+	existing map[string]bool
+	lookups  [][]string
+}
+
+func (r *batchGetRepo) GetByIDs(ctx context.Context, ids []string, view domain.GoldenView) ([]domain.Golden, error) {
+	r.lookups = append(r.lookups, ids)
+	var docs []domain.Golden
+	for i := len(ids) - 1; i >= 0; i-- {
+		if r.existing[ids[i]] {
+			docs = append(docs, domain.Golden{ID: ids[i], Status: domain.StatusPublished})
+		}
+	}
+	return docs, nil
+}
+
+// batchRecordingRepo records the size of every upserted batch.
+type batchRecordingRepo struct {
+	fakeRepo
+	batches []int
+}
+
+func (r *batchRecordingRepo) Upsert(ctx context.Context, docs []domain.Golden, dryRun bool) ([]domain.ImportResult, error) {
+	r.batches = append(r.batches, len(docs))
+	return r.fakeRepo.Upsert(ctx, docs, dryRun)
+}
+func (r *batchRecordingRepo) RunInTx(ctx context.Context, fn func(tx domain.Repository) error, opts ...domain.TxOption) error {
+	return fn(r)
+}
+
+// eventRecordingRepo records the events appended to the outbox.
+type eventRecordingRepo struct {
+	fakeRepo
+	events []domain.Event
+}
+
+func (r *eventRecordingRepo) AppendEvents(ctx context.Context, events ...domain.Event) error {
+	r.events = append(r.events, events...)
+	return nil
+}
+func (r *eventRecordingRepo) RunInTx(ctx context.Context, fn func(tx domain.Repository) error, opts ...domain.TxOption) error {
+	return fn(r)
+}
+
+// slugRepo reports taken slugs and records what was written. owners maps the
+// slugs of GetBySlug to golden ids, stored holds the goldens GetByIDs finds
+// and Upsert updates, and races is the number of writes that lose their slug
+// to a concurrent one.
+type slugRepo struct {
+	fakeRepo
+	taken    []string
+	owners   map[string]string
+	stored   map[string]domain.Golden
+	races    int
+	created  []domain.Golden
+	updated  []domain.Golden
+	upserted []domain.Golden
+	events   []domain.Event
+}
+
+func (r *slugRepo) GetByIDs(ctx context.Context, ids []string, view domain.GoldenView) ([]domain.Golden, error) {
+	var docs []domain.Golden
+	for _, id := range ids {
+		if doc, ok := r.stored[id]; ok {
+			docs = append(docs, doc)
+		}
+	}
+	return docs, nil
+}
+
+func (r *slugRepo) GetBySlug(ctx context.Context, slug string, view domain.GoldenView) (*domain.Golden, error) {
+	if id, ok := r.owners[slug]; ok {
+		return &domain.Golden{ID: id, Slug: slug}, nil
+	}
+	return nil, fmt.Errorf("%w: slug %s", domain.ErrNotFound, slug)
+}
+func (r *slugRepo) SlugsInUse(ctx context.Context, base string) ([]string, error) {
+	return r.taken, nil
+}
+func (r *slugRepo) race(slug string) error {
+	if r.races == 0 {
+		return nil
+	}
+	r.races--
+	r.taken = append(r.taken, slug)
+	return fmt.Errorf("%w: %s", domain.ErrSlugTaken, slug)
+}
+func (r *slugRepo) Create(ctx context.Context, doc *domain.Golden) error {
+	if err := r.race(doc.Slug); err != nil {
+		return err
+	}
+	r.created = append(r.created, *doc)
+	return nil
+}
+func (r *slugRepo) Update(ctx context.Context, doc *domain.Golden) error {
+	if err := r.race(doc.Slug); err != nil {
+		return err
+	}
+	r.updated = append(r.updated, *doc)
+	return nil
+}
+func (r *slugRepo) Upsert(ctx context.Context, docs []domain.Golden, dryRun bool) ([]domain.ImportResult, error) {
+	r.upserted = append(r.upserted, docs...)
+	results := make([]domain.ImportResult, 0, len(docs))
+	for _, doc := range docs {
+		status := domain.ImportStatusCreated
+		if _, ok := r.stored[doc.ID]; ok {
+			status = domain.ImportStatusUpdated
+		}
+		results = append(results, domain.ImportResult{ID: doc.ID, Status: status})
+	}
+	return results, nil
+}
+func (r *slugRepo) AppendEvents(ctx context.Context, events ...domain.Event) error {
+	r.events = append(r.events, events...)
+	return nil
+}
+func (r *slugRepo) RunInTx(ctx context.Context, fn func(tx domain.Repository) error, opts ...domain.TxOption) error {
+	return fn(r)
+}
+
+// helperEditorContext is the context of a caller with editor rights, which
+// every write requires.
+func helperEditorContext() context.Context {
+	return domain.WithEditor(context.Background())
+}
+
+func helperEventTypes(events []domain.Event) []domain.EventType {
+	types := make([]domain.EventType, 0, len(events))
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+	return types
+}
 
 // ---------------------------------------------------------------------------
 // NewGoldenService
@@ -53,7 +273,7 @@ func TestNewGoldenService_WithRepo(t *testing.T) {
 
 func TestGoldenService_GetAllGoldens_ReturnsResults(t *testing.T) {
 	svc := NewGoldenService(fakeRepo{})
-	got, err := svc.GetAllGoldens(context.Background())
+	got, err := svc.GetAllGoldens(context.Background(), domain.GoldenViewFull)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -65,7 +285,7 @@ func TestGoldenService_GetAllGoldens_ReturnsResults(t *testing.T) {
 func TestGoldenService_GetAllGoldens_PropagatesError(t *testing.T) {
 	want := errors.New("repo down")
 	svc := NewGoldenService(failingRepo{err: want})
-	_, err := svc.GetAllGoldens(context.Background())
+	_, err := svc.GetAllGoldens(context.Background(), domain.GoldenViewFull)
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
@@ -80,7 +300,7 @@ func TestGoldenService_GetAllGoldens_PropagatesError(t *testing.T) {
 
 func TestGoldenService_GetGoldenByID_ReturnsCorrectID(t *testing.T) {
 	svc := NewGoldenService(fakeRepo{})
-	got, err := svc.GetGoldenByID(context.Background(), "my-id")
+	got, err := svc.GetGoldenByID(context.Background(), "my-id", domain.GoldenViewFull)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -92,7 +312,7 @@ func TestGoldenService_GetGoldenByID_ReturnsCorrectID(t *testing.T) {
 func TestGoldenService_GetGoldenByID_PropagatesError(t *testing.T) {
 	want := errors.New("not found")
 	svc := NewGoldenService(failingRepo{err: want})
-	_, err := svc.GetGoldenByID(context.Background(), "any")
+	_, err := svc.GetGoldenByID(context.Background(), "any", domain.GoldenViewFull)
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
@@ -105,17 +325,217 @@ func TestGoldenService_GetGoldenByID_PropagatesError(t *testing.T) {
 // CreateGolden
 // ---------------------------------------------------------------------------
 
+func TestGoldenService_BatchGetGoldens_KeepsRequestOrder(t *testing.T) {
+	repo := &batchGetRepo{existing: map[string]bool{"a": true, "b": true, "c": true}}
+	svc := NewGoldenService(repo)
+
+	docs, missing, err := svc.BatchGetGoldens(context.Background(), []string{"c", "x", "a", "c", "b"}, domain.GoldenViewBasic)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	var ids []string
+	for _, doc := range docs {
+		ids = append(ids, doc.ID)
+	}
+	if !reflect.DeepEqual(ids, []string{"c", "a", "b"}) {
+		t.Fatalf("ids = %v, want [c a b]", ids)
+	}
+	if !reflect.DeepEqual(missing, []string{"x"}) {
+		t.Fatalf("missing = %v, want [x]", missing)
+	}
+	if !reflect.DeepEqual(repo.lookups, [][]string{{"c", "x", "a", "b"}}) {
+		t.Fatalf("lookups = %v, want a single lookup of the distinct ids", repo.lookups)
+	}
+}
+
+func TestGoldenService_BatchGetGoldens_EmptySkipsRepository(t *testing.T) {
+	repo := &batchGetRepo{}
+	docs, missing, err := NewGoldenService(repo).BatchGetGoldens(context.Background(), nil, domain.GoldenViewBasic)
+	if err != nil || docs != nil || missing != nil || repo.lookups != nil {
+		t.Fatalf("BatchGetGoldens() = %v, %v, %v with lookups %v", docs, missing, err, repo.lookups)
+	}
+}
+
+func TestGoldenService_BatchGetGoldens_PropagatesError(t *testing.T) {
+	want := errors.New("boom")
+	_, _, err := NewGoldenService(failingRepo{err: want}).BatchGetGoldens(context.Background(), []string{"a"}, domain.GoldenViewBasic)
+	if !errors.Is(err, want) {
+		t.Fatalf("err = %v, want %v", err, want)
+	}
+}
+
+func TestGoldenService_ListFacets(t *testing.T) {
+	svc := NewGoldenService(fakeRepo{})
+
+	categories, err := svc.ListCategories(context.Background(), "k8s")
+	if err != nil || len(categories) != 1 || categories[0].Value != "DevOps" {
+		t.Fatalf("ListCategories() = %v, %v", categories, err)
+	}
+	tags, err := svc.ListTags(context.Background(), "DevOps")
+	if err != nil || len(tags) != 1 || tags[0].Value != "k8sDevOps" {
+		t.Fatalf("ListTags() = %v, %v", tags, err)
+	}
+
+	// With categories the tags of the whole subtree are counted.
+	tree := NewGoldenService(fakeRepo{}, WithCategories(helperCategoryStore()))
+	if tags, err := tree.ListTags(context.Background(), "Dev Ops"); err != nil || tags[0].Value != "k8sdevops,ci-cd,gitops" {
+		t.Fatalf("ListTags() with categories = %v, %v; want the subtree counted", tags, err)
+	}
+	if _, err := tree.ListTags(context.Background(), "Cooking"); !errors.Is(err, domain.ErrUnknownCategory) {
+		t.Fatalf("ListTags() err = %v, want ErrUnknownCategory", err)
+	}
+
+	want := errors.New("boom")
+	failing := NewGoldenService(failingRepo{err: want})
+	if _, err := failing.ListCategories(context.Background(), ""); !errors.Is(err, want) {
+		t.Fatalf("ListCategories() err = %v, want %v", err, want)
+	}
+	if _, err := failing.ListTags(context.Background(), ""); !errors.Is(err, want) {
+		t.Fatalf("ListTags() err = %v, want %v", err, want)
+	}
+}
+
+func TestGoldenService_GetGoldensByCategory(t *testing.T) {
+	ctx := context.Background()
+
+	docs, err := NewGoldenService(fakeRepo{}).GetGoldensByCategory(ctx, "DevOps", domain.GoldenViewBasic)
+	if err != nil || len(docs) != 1 || docs[0].Category != "DevOps" {
+		t.Fatalf("without categories GetGoldensByCategory() = %+v, %v, want an exact match", docs, err)
+	}
+
+	svc := NewGoldenService(fakeRepo{}, WithCategories(helperCategoryStore()))
+	docs, err = svc.GetGoldensByCategory(ctx, "Dev Ops", domain.GoldenViewBasic)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var categories []string
+	for _, doc := range docs {
+		categories = append(categories, doc.Category)
+	}
+	if want := []string{"devops", "ci-cd", "gitops"}; !reflect.DeepEqual(categories, want) {
+		t.Fatalf("GetGoldensByCategory() read categories %v, want the subtree %v", categories, want)
+	}
+
+	if _, err := svc.GetGoldensByCategory(ctx, "Cooking", domain.GoldenViewBasic); !errors.Is(err, domain.ErrUnknownCategory) {
+		t.Fatalf("GetGoldensByCategory() err = %v, want ErrUnknownCategory", err)
+	}
+}
+
+func TestGoldenService_WriteMethods_NormalizeCategory(t *testing.T) {
+	ctx := helperEditorContext()
+	repo := &slugRepo{}
+	svc := NewGoldenService(repo, WithCategories(helperCategoryStore()))
+
+	doc := &domain.Golden{Title: "Pipelines", Category: "Dev Ops"}
+	if err := svc.CreateGolden(ctx, doc); err != nil {
+		t.Fatalf("CreateGolden() unexpected error: %v", err)
+	}
+	if doc.Category != "devops" || repo.created[0].Category != "devops" {
+		t.Fatalf("CreateGolden() stored category %q, want devops", repo.created[0].Category)
+	}
+
+	update := &domain.Golden{ID: doc.ID, Title: "Pipelines", Category: "CI/CD"}
+	if err := svc.UpdateGolden(ctx, update); err != nil || update.Category != "ci-cd" {
+		t.Fatalf("UpdateGolden() category = %q, %v, want ci-cd", update.Category, err)
+	}
+
+	if err := svc.CreateGolden(ctx, &domain.Golden{Title: "Soup", Category: "Cooking"}); !errors.Is(err, domain.ErrUnknownCategory) {
+		t.Fatalf("CreateGolden() err = %v, want ErrUnknownCategory", err)
+	}
+	if len(repo.created) != 1 {
+		t.Fatalf("expected the golden with an unknown category not to be stored, got %+v", repo.created)
+	}
+
+	results, err := svc.ImportGoldens(ctx, []domain.Golden{
+		{ID: "a", Title: "A", Category: "devops"},
+		{ID: "b", Title: "B", Category: "Cooking"},
+	}, false)
+	if err != nil {
+		t.Fatalf("ImportGoldens() unexpected error: %v", err)
+	}
+	if results[0].Status != domain.ImportStatusCreated || results[1].Status != domain.ImportStatusFailed || !errors.Is(results[1].Err, domain.ErrUnknownCategory) {
+		t.Fatalf("ImportGoldens() = %+v, want the unknown category to fail alone", results)
+	}
+}
+
+func TestGoldenService_WriteMethods_NormalizeTags(t *testing.T) {
+	ctx := helperEditorContext()
+	repo := &slugRepo{}
+	svc := NewGoldenService(repo, WithTagRegistry(helperTagStore()))
+
+	doc := &domain.Golden{Title: "Pipelines", Tags: []string{"CI/CD", "cicd", "K8s", "Go Lang"}}
+	if err := svc.CreateGolden(ctx, doc); err != nil {
+		t.Fatalf("CreateGolden() unexpected error: %v", err)
+	}
+	if want := []string{"ci-cd", "kubernetes", "go-lang"}; !reflect.DeepEqual(repo.created[0].Tags, want) {
+		t.Fatalf("CreateGolden() stored tags %v, want %v", repo.created[0].Tags, want)
+	}
+
+	update := &domain.Golden{ID: doc.ID, Title: "Pipelines", Tags: []string{"Continuous Delivery"}}
+	if err := svc.UpdateGolden(ctx, update); err != nil || !reflect.DeepEqual(update.Tags, []string{"ci-cd"}) {
+		t.Fatalf("UpdateGolden() tags = %v, %v, want [ci-cd]", update.Tags, err)
+	}
+
+	if _, err := svc.ImportGoldens(ctx, []domain.Golden{{ID: "a", Title: "A", Tags: []string{"k8s", "Kubernetes"}}}, false); err != nil {
+		t.Fatalf("ImportGoldens() unexpected error: %v", err)
+	}
+	if want := []string{"kubernetes"}; !reflect.DeepEqual(repo.upserted[0].Tags, want) {
+		t.Fatalf("ImportGoldens() stored tags %v, want %v", repo.upserted[0].Tags, want)
+	}
+
+	plain := &domain.Golden{Title: "Plain", Tags: []string{"CI/CD"}}
+	if err := NewGoldenService(&slugRepo{}).CreateGolden(ctx, plain); err != nil || plain.Tags[0] != "CI/CD" {
+		t.Fatalf("without a registry CreateGolden() tags = %v, %v, want them as given", plain.Tags, err)
+	}
+}
+
 func TestGoldenService_CreateGolden_Success(t *testing.T) {
 	svc := NewGoldenService(fakeRepo{})
-	if err := svc.CreateGolden(context.Background(), &domain.Golden{ID: "new-id"}); err != nil {
+	if err := svc.CreateGolden(helperEditorContext(), &domain.Golden{ID: "new-id"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestGoldenService_CreateGolden_GeneratesIDAndSlug(t *testing.T) {
+	repo := &slugRepo{taken: []string{"getting-started", "getting-started-2"}}
+	svc := NewGoldenService(repo)
+
+	doc := &domain.Golden{Title: "Getting Started"}
+	if err := svc.CreateGolden(helperEditorContext(), doc); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
+	if len(doc.ID) != 36 {
+		t.Errorf("expected a generated UUID, got %q", doc.ID)
+	}
+	if doc.Slug != "getting-started-3" {
+		t.Errorf("expected collision suffix, got %q", doc.Slug)
+	}
+	if len(repo.created) != 1 || repo.created[0].Slug != doc.Slug {
+		t.Errorf("expected the slug to be stored, got %+v", repo.created)
+	}
+}
+
+func TestGoldenService_CreateGolden_RetriesTakenSlug(t *testing.T) {
+	repo := &slugRepo{races: 1}
+	doc := &domain.Golden{Title: "Getting Started"}
+	if err := NewGoldenService(repo).CreateGolden(helperEditorContext(), doc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if doc.Slug != "getting-started-2" || len(repo.created) != 1 {
+		t.Fatalf("expected the next free slug after the race, got %q and %+v", doc.Slug, repo.created)
+	}
+
+	repo = &slugRepo{races: slugAttempts}
+	if err := NewGoldenService(repo).CreateGolden(helperEditorContext(), &domain.Golden{Title: "Getting Started"}); !errors.Is(err, domain.ErrAlreadyExists) {
+		t.Fatalf("expected ErrAlreadyExists once the attempts run out, got %v", err)
+	}
 }
 
 func TestGoldenService_CreateGolden_PropagatesError(t *testing.T) {
 	want := errors.New("create failed")
 	svc := NewGoldenService(failingRepo{err: want})
-	err := svc.CreateGolden(context.Background(), &domain.Golden{})
+	err := svc.CreateGolden(helperEditorContext(), &domain.Golden{})
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
@@ -130,15 +550,96 @@ func TestGoldenService_CreateGolden_PropagatesError(t *testing.T) {
 
 func TestGoldenService_UpdateGolden_Success(t *testing.T) {
 	svc := NewGoldenService(fakeRepo{})
-	if err := svc.UpdateGolden(context.Background(), &domain.Golden{ID: "existing-id"}); err != nil {
+	if err := svc.UpdateGolden(helperEditorContext(), &domain.Golden{ID: "existing-id"}); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
 
+func TestGoldenService_UpdateGolden_Slug(t *testing.T) {
+	ctx := helperEditorContext()
+	repo := &slugRepo{
+		taken:  []string{"intro", "intro-2", "old-intro"},
+		owners: map[string]string{"intro": "other-id", "intro-2": "other-id", "old-intro": "doc-id"},
+	}
+	svc := NewGoldenService(repo)
+
+	cases := []struct {
+		name string
+		slug string
+		want string
+	}{
+		{name: "taken by another golden", slug: "Intro", want: "intro-3"},
+		{name: "redirecting to the golden", slug: "old-intro", want: "old-intro"},
+		{name: "free", slug: "welcome", want: "welcome"},
+		{name: "unset", slug: "", want: ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc := &domain.Golden{ID: "doc-id", Title: "Intro", Slug: tc.slug}
+			if err := svc.UpdateGolden(ctx, doc); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := repo.updated[len(repo.updated)-1].Slug; got != tc.want {
+				t.Fatalf("stored slug %q, want %q", got, tc.want)
+			}
+		})
+	}
+
+	repo.races = 1
+	doc := &domain.Golden{ID: "doc-id", Title: "Intro", Slug: "intro"}
+	if err := svc.UpdateGolden(ctx, doc); err != nil || doc.Slug != "intro-4" {
+		t.Fatalf("UpdateGolden() after a race = %q, %v; want intro-4", doc.Slug, err)
+	}
+}
+
+func TestGoldenService_UpdateGolden_Schedule(t *testing.T) {
+	ctx := helperEditorContext()
+	publishAt := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	expireAt := publishAt.Add(24 * time.Hour)
+
+	cases := []struct {
+		name        string
+		status      domain.GoldenStatus
+		publishAt   time.Time
+		opts        []UpdateOption
+		wantPublish time.Time
+		wantExpire  time.Time
+		wantErr     error
+	}{
+		{name: "unset keeps the stored times", status: domain.StatusInReview, wantPublish: publishAt, wantExpire: expireAt},
+		{name: "cleared", status: domain.StatusInReview, opts: []UpdateOption{WithClearPublishAt(), WithClearExpireAt()}},
+		{name: "expire_at cleared while scheduled", status: domain.StatusScheduled, opts: []UpdateOption{WithClearExpireAt()}, wantPublish: publishAt},
+		{name: "publish_at cleared while scheduled", status: domain.StatusScheduled, opts: []UpdateOption{WithClearPublishAt()}, wantErr: domain.ErrInvalidTransition},
+		{name: "publish_at after the stored expire_at", status: domain.StatusInReview, publishAt: expireAt.Add(time.Hour), wantErr: domain.ErrInvalidSchedule},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := helperWorkflowRepo()
+			repo.docs["golden"] = domain.Golden{ID: "golden", Title: "Golden", Status: tc.status, PublishAt: publishAt, ExpireAt: expireAt}
+			svc := NewGoldenService(repo)
+
+			err := svc.UpdateGolden(ctx, &domain.Golden{ID: "golden", Title: "Golden", PublishAt: tc.publishAt}, tc.opts...)
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("UpdateGolden() error = %v, want %v", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UpdateGolden() unexpected error: %v", err)
+			}
+			stored := repo.docs["golden"]
+			if !stored.PublishAt.Equal(tc.wantPublish) || !stored.ExpireAt.Equal(tc.wantExpire) {
+				t.Fatalf("stored publish_at %v and expire_at %v, want %v and %v", stored.PublishAt, stored.ExpireAt, tc.wantPublish, tc.wantExpire)
+			}
+		})
+	}
+}
+
 func TestGoldenService_UpdateGolden_PropagatesError(t *testing.T) {
 	want := errors.New("update failed")
 	svc := NewGoldenService(failingRepo{err: want})
-	err := svc.UpdateGolden(context.Background(), &domain.Golden{})
+	err := svc.UpdateGolden(helperEditorContext(), &domain.Golden{})
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
@@ -153,7 +654,7 @@ func TestGoldenService_UpdateGolden_PropagatesError(t *testing.T) {
 
 func TestGoldenService_DeleteGolden_Success(t *testing.T) {
 	svc := NewGoldenService(fakeRepo{})
-	if err := svc.DeleteGolden(context.Background(), "existing-id"); err != nil {
+	if err := svc.DeleteGolden(helperEditorContext(), "existing-id"); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
@@ -161,7 +662,7 @@ func TestGoldenService_DeleteGolden_Success(t *testing.T) {
 func TestGoldenService_DeleteGolden_PropagatesError(t *testing.T) {
 	want := errors.New("delete failed")
 	svc := NewGoldenService(failingRepo{err: want})
-	err := svc.DeleteGolden(context.Background(), "any")
+	err := svc.DeleteGolden(helperEditorContext(), "any")
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
@@ -170,3 +671,433 @@ func TestGoldenService_DeleteGolden_PropagatesError(t *testing.T) {
 	}
 }
 
+// ---------------------------------------------------------------------------
+// Domain events
+// ---------------------------------------------------------------------------
+
+func TestGoldenService_WriteMethods_AppendEvents(t *testing.T) {
+	repo := &eventRecordingRepo{}
+	svc := NewGoldenService(repo)
+	ctx := helperEditorContext()
+
+	if err := svc.CreateGolden(ctx, &domain.Golden{ID: "a", Title: "A"}); err != nil {
+		t.Fatalf("CreateGolden: %v", err)
+	}
+	if err := svc.UpdateGolden(ctx, &domain.Golden{ID: "a", Title: "A2"}); err != nil {
+		t.Fatalf("UpdateGolden: %v", err)
+	}
+	if err := svc.DeleteGolden(ctx, "a"); err != nil {
+		t.Fatalf("DeleteGolden: %v", err)
+	}
+
+	want := []domain.EventType{domain.EventGoldenCreated, domain.EventGoldenUpdated, domain.EventGoldenDeleted}
+	if got := helperEventTypes(repo.events); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected events %v, got %v", want, got)
+	}
+	for _, event := range repo.events {
+		if event.AggregateID != "a" {
+			t.Errorf("%s.AggregateID: want %q, got %q", event.Type, "a", event.AggregateID)
+		}
+	}
+}
+
+func TestGoldenService_ImportGoldens_AppendsEventsUnlessDryRun(t *testing.T) {
+	docs := []domain.Golden{{ID: "a", Title: "A"}, {ID: "b", Title: "B"}}
+
+	for _, dryRun := range []bool{false, true} {
+		repo := &eventRecordingRepo{}
+		svc := NewGoldenService(repo)
+		if _, err := svc.ImportGoldens(helperEditorContext(), docs, dryRun); err != nil {
+			t.Fatalf("dryRun=%v: unexpected error: %v", dryRun, err)
+		}
+
+		want := 2
+		if dryRun {
+			want = 0
+		}
+		if len(repo.events) != want {
+			t.Errorf("dryRun=%v: expected %d events, got %d", dryRun, want, len(repo.events))
+		}
+	}
+}
+
+func TestGoldenService_ImportGoldens_AssignsDistinctSlugsPerBatch(t *testing.T) {
+	repo := &slugRepo{taken: []string{"keptn"}}
+	svc := NewGoldenService(repo)
+	docs := []domain.Golden{
+		{ID: "a", Title: "Keptn"},
+		{ID: "b", Title: "Keptn"},
+	}
+
+	if _, err := svc.ImportGoldens(helperEditorContext(), docs, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := []string{repo.upserted[0].Slug, repo.upserted[1].Slug}
+	if !reflect.DeepEqual(got, []string{"keptn-2", "keptn-3"}) {
+		t.Fatalf("slugs = %v", got)
+	}
+}
+
+func TestGoldenService_ImportGoldens_KeepsSlugsOfExistingGoldens(t *testing.T) {
+	repo := &slugRepo{
+		taken:  []string{"keptn"},
+		stored: map[string]domain.Golden{"a": {ID: "a", Title: "Keptn", Slug: "keptn", Status: domain.StatusPublished}},
+	}
+	svc := NewGoldenService(repo)
+	docs := []domain.Golden{
+		{ID: "a", Title: "Keptn"},
+		{ID: "b", Title: "Keptn"},
+	}
+
+	if _, err := svc.ImportGoldens(helperEditorContext(), docs, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := []string{repo.upserted[0].Slug, repo.upserted[1].Slug}
+	if !reflect.DeepEqual(got, []string{"keptn", "keptn-2"}) {
+		t.Fatalf("slugs = %v", got)
+	}
+
+	// The update event describes the golden as stored, not the record.
+	if types := helperEventTypes(repo.events); !reflect.DeepEqual(types, []domain.EventType{domain.EventGoldenCreated, domain.EventGoldenUpdated}) {
+		t.Fatalf("events = %v", types)
+	}
+	var payload domain.GoldenEventPayload
+	if err := json.Unmarshal(repo.events[1].Payload, &payload); err != nil {
+		t.Fatalf("invalid payload: %v", err)
+	}
+	if payload.ID != "a" || payload.Status != string(domain.StatusPublished) {
+		t.Fatalf("update event payload = %+v, want the stored published golden", payload)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// ImportGoldens
+// ---------------------------------------------------------------------------
+
+func TestGoldenService_ImportGoldens_ReportsResultsInInputOrder(t *testing.T) {
+	svc := NewGoldenService(fakeRepo{})
+	docs := []domain.Golden{
+		{ID: "a", Title: "A"},
+		{ID: "", Title: "missing id"},
+		{ID: "b", Title: "B", ContentB64: "not base64!"},
+		{ID: "c", Title: "C", ContentB64: "Yw=="},
+	}
+
+	got, err := svc.ImportGoldens(helperEditorContext(), docs, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []domain.ImportStatus{
+		domain.ImportStatusCreated,
+		domain.ImportStatusFailed,
+		domain.ImportStatusFailed,
+		domain.ImportStatusCreated,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d results, got %d", len(want), len(got))
+	}
+	for i, status := range want {
+		if got[i].Status != status {
+			t.Errorf("results[%d].Status: want %v, got %v", i, status, got[i].Status)
+		}
+		if got[i].ID != docs[i].ID {
+			t.Errorf("results[%d].ID: want %q, got %q", i, docs[i].ID, got[i].ID)
+		}
+	}
+	if got[1].Err == nil || got[2].Err == nil {
+		t.Fatal("expected validation errors for invalid docs")
+	}
+}
+
+func TestGoldenService_ImportGoldens_SplitsBatches(t *testing.T) {
+	repo := &batchRecordingRepo{}
+	svc := NewGoldenService(repo)
+
+	docs := make([]domain.Golden, 0, ImportBatchSize+3)
+	for i := 0; i < ImportBatchSize+1; i++ {
+		docs = append(docs, domain.Golden{ID: fmt.Sprintf("id-%d", i), Title: "t"})
+	}
+	// A repeated id forces a new batch so the same row is never upserted twice.
+	docs = append(docs, domain.Golden{ID: "dup", Title: "t"}, domain.Golden{ID: "dup", Title: "t2"})
+
+	if _, err := svc.ImportGoldens(helperEditorContext(), docs, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []int{ImportBatchSize, 2, 1}
+	if !reflect.DeepEqual(repo.batches, want) {
+		t.Fatalf("expected batches %v, got %v", want, repo.batches)
+	}
+}
+
+func TestGoldenService_ImportGoldens_Status(t *testing.T) {
+	publishedAt := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	expireAt := publishedAt.AddDate(1, 0, 0)
+	docs := []domain.Golden{
+		{ID: "a", Title: "A", Status: domain.StatusPublished, PublishedAt: publishedAt, ExpireAt: expireAt},
+		{ID: "b", Title: "B", Status: domain.StatusScheduled},
+	}
+
+	repo := &slugRepo{}
+	results, err := NewGoldenService(repo).ImportGoldens(helperEditorContext(), docs, false)
+	if err != nil {
+		t.Fatalf("ImportGoldens() unexpected error: %v", err)
+	}
+	if got := repo.upserted[0]; got.Status != domain.StatusPublished || !got.PublishedAt.Equal(publishedAt) || !got.ExpireAt.Equal(expireAt) {
+		t.Fatalf("ImportGoldens() stored %+v, want the imported status and times", got)
+	}
+	if results[1].Status != domain.ImportStatusFailed {
+		t.Fatalf("ImportGoldens() = %+v, want the scheduled golden without publish_at to fail", results[1])
+	}
+
+	repo = &slugRepo{}
+	if _, err := NewGoldenService(repo).ImportGoldens(helperEditorContext(), docs, false, WithResetStatus()); err != nil {
+		t.Fatalf("ImportGoldens() unexpected error: %v", err)
+	}
+	for _, got := range repo.upserted {
+		if got.Status != "" || !got.PublishedAt.IsZero() || !got.ExpireAt.IsZero() {
+			t.Fatalf("ImportGoldens() with WithResetStatus stored %+v, want no status fields", got)
+		}
+	}
+}
+
+func TestGoldenService_ImportGoldens_MarksFailedBatch(t *testing.T) {
+	want := errors.New("upsert failed")
+	svc := NewGoldenService(failingRepo{err: want})
+
+	got, err := svc.ImportGoldens(helperEditorContext(), []domain.Golden{{ID: "a", Title: "A"}}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || got[0].Status != domain.ImportStatusFailed {
+		t.Fatalf("expected one failed result, got %+v", got)
+	}
+	if !errors.Is(got[0].Err, want) {
+		t.Fatalf("expected %v, got %v", want, got[0].Err)
+	}
+}
+
+func TestGoldenService_ImportGoldens_StopsOnCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(helperEditorContext())
+	cancel()
+	svc := NewGoldenService(fakeRepo{})
+
+	_, err := svc.ImportGoldens(ctx, []domain.Golden{{ID: "a", Title: "A"}}, false)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Editorial workflow
+// ---------------------------------------------------------------------------
+
+// workflowRepo stores one golden per status and records transitions.
+type workflowRepo struct {
+	eventRecordingRepo
+	docs        map[string]domain.Golden
+	transitions []domain.GoldenTransition
+}
+
+func helperWorkflowRepo() *workflowRepo {
+	return &workflowRepo{docs: map[string]domain.Golden{
+		"draft":     {ID: "draft", Status: domain.StatusDraft},
+		"in-review": {ID: "in-review", Status: domain.StatusInReview},
+		"published": {ID: "published", Status: domain.StatusPublished},
+		"archived":  {ID: "archived", Status: domain.StatusArchived},
+	}}
+}
+
+func (r *workflowRepo) GetAll(ctx context.Context, view domain.GoldenView) ([]domain.Golden, error) {
+	docs := make([]domain.Golden, 0, len(r.docs))
+	for _, id := range []string{"draft", "in-review", "published", "archived"} {
+		docs = append(docs, r.docs[id])
+	}
+	return docs, nil
+}
+func (r *workflowRepo) GetByID(ctx context.Context, id string, view domain.GoldenView) (*domain.Golden, error) {
+	doc, ok := r.docs[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", domain.ErrNotFound, id)
+	}
+	return &doc, nil
+}
+func (r *workflowRepo) GetByIDs(ctx context.Context, ids []string, view domain.GoldenView) ([]domain.Golden, error) {
+	var docs []domain.Golden
+	for _, id := range ids {
+		if doc, ok := r.docs[id]; ok {
+			docs = append(docs, doc)
+		}
+	}
+	return docs, nil
+}
+func (r *workflowRepo) Update(ctx context.Context, doc *domain.Golden) error {
+	r.docs[doc.ID] = *doc
+	return nil
+}
+func (r *workflowRepo) AppendTransition(ctx context.Context, transition domain.GoldenTransition) error {
+	r.transitions = append(r.transitions, transition)
+	return nil
+}
+func (r *workflowRepo) ListTransitions(ctx context.Context, id string) ([]domain.GoldenTransition, error) {
+	return r.transitions, nil
+}
+func (r *workflowRepo) RunInTx(ctx context.Context, fn func(tx domain.Repository) error, opts ...domain.TxOption) error {
+	return fn(r)
+}
+
+func TestGoldenService_Reads_HideUnpublishedFromReaders(t *testing.T) {
+	svc := NewGoldenService(helperWorkflowRepo())
+	reader, editor := context.Background(), domain.WithEditor(context.Background())
+
+	for _, tt := range []struct {
+		name string
+		ctx  context.Context
+		want []string
+	}{
+		{name: "reader", ctx: reader, want: []string{"published"}},
+		{name: "editor", ctx: editor, want: []string{"draft", "in-review", "published", "archived"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			docs, err := svc.GetAllGoldens(tt.ctx, domain.GoldenViewBasic)
+			if err != nil {
+				t.Fatalf("GetAllGoldens() unexpected error: %v", err)
+			}
+			var ids []string
+			for _, doc := range docs {
+				ids = append(ids, doc.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.want) {
+				t.Fatalf("GetAllGoldens() = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+
+	if _, err := svc.GetGoldenByID(reader, "draft", domain.GoldenViewFull); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("GetGoldenByID(draft) err = %v, want ErrNotFound for readers", err)
+	}
+	if _, err := svc.GetGoldenByID(editor, "draft", domain.GoldenViewFull); err != nil {
+		t.Fatalf("GetGoldenByID(draft) unexpected error for editors: %v", err)
+	}
+
+	docs, missing, err := svc.BatchGetGoldens(reader, []string{"published", "archived"}, domain.GoldenViewBasic)
+	if err != nil || len(docs) != 1 || !reflect.DeepEqual(missing, []string{"archived"}) {
+		t.Fatalf("BatchGetGoldens() = %v, %v, %v; want archived reported missing", docs, missing, err)
+	}
+}
+
+func TestGoldenService_Reads_HonourPublishAndExpireAt(t *testing.T) {
+	repo := helperWorkflowRepo()
+	repo.docs["due"] = domain.Golden{ID: "due", Status: domain.StatusScheduled, PublishAt: time.Now().Add(-time.Minute)}
+	repo.docs["later"] = domain.Golden{ID: "later", Status: domain.StatusScheduled, PublishAt: time.Now().Add(time.Hour)}
+	repo.docs["expired"] = domain.Golden{ID: "expired", Status: domain.StatusPublished, ExpireAt: time.Now().Add(-time.Minute)}
+	svc := NewGoldenService(repo)
+
+	if _, err := svc.GetGoldenByID(context.Background(), "due", domain.GoldenViewBasic); err != nil {
+		t.Fatalf("GetGoldenByID(due) unexpected error: %v", err)
+	}
+	for _, id := range []string{"later", "expired"} {
+		if _, err := svc.GetGoldenByID(context.Background(), id, domain.GoldenViewBasic); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("GetGoldenByID(%s) err = %v, want ErrNotFound for readers", id, err)
+		}
+	}
+}
+
+func TestGoldenService_CreateGolden_StartsAsDraft(t *testing.T) {
+	repo := &slugRepo{}
+	svc := NewGoldenService(repo)
+
+	doc := &domain.Golden{Title: "Keptn", Status: domain.StatusPublished, PublishedAt: time.Now()}
+	if err := svc.CreateGolden(helperEditorContext(), doc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := repo.created[0]; got.Status != domain.StatusDraft || !got.PublishedAt.IsZero() {
+		t.Fatalf("CreateGolden() stored %s published at %v, want a draft", got.Status, got.PublishedAt)
+	}
+}
+
+func TestGoldenService_TransitionGolden(t *testing.T) {
+	repo := helperWorkflowRepo()
+	svc := NewGoldenService(repo)
+	editor := domain.WithEditor(context.Background())
+
+	doc, err := svc.TransitionGolden(editor, "in-review", domain.StatusPublished, "approved")
+	if err != nil {
+		t.Fatalf("TransitionGolden() unexpected error: %v", err)
+	}
+	if doc.Status != domain.StatusPublished || doc.PublishedAt.IsZero() || repo.docs["in-review"].Status != domain.StatusPublished {
+		t.Fatalf("TransitionGolden() = %+v, want a stored published golden", doc)
+	}
+	if len(repo.transitions) != 1 || repo.transitions[0].From != domain.StatusInReview || repo.transitions[0].Reason != "approved" {
+		t.Fatalf("expected the transition in the history, got %+v", repo.transitions)
+	}
+
+	if len(repo.events) != 1 || repo.events[0].Type != domain.EventGoldenStatusChanged {
+		t.Fatalf("expected a GoldenStatusChanged event, got %v", helperEventTypes(repo.events))
+	}
+	var payload domain.StatusChangedEventPayload
+	if err := json.Unmarshal(repo.events[0].Payload, &payload); err != nil {
+		t.Fatalf("invalid event payload: %v", err)
+	}
+	if payload.Status != "published" || payload.PreviousStatus != "in_review" || payload.Reason != "approved" {
+		t.Fatalf("unexpected event payload %+v", payload)
+	}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		id   string
+		to   domain.GoldenStatus
+		want error
+	}{
+		{name: "reader", ctx: context.Background(), id: "draft", to: domain.StatusInReview, want: domain.ErrEditorRequired},
+		{name: "skip-review", ctx: editor, id: "draft", to: domain.StatusPublished, want: domain.ErrInvalidTransition},
+		{name: "unknown-status", ctx: editor, id: "draft", to: "", want: domain.ErrInvalidStatus},
+		{name: "missing", ctx: editor, id: "missing", to: domain.StatusInReview, want: domain.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.TransitionGolden(tt.ctx, tt.id, tt.to, ""); !errors.Is(err, tt.want) {
+				t.Fatalf("TransitionGolden() err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+	if len(repo.transitions) != 1 {
+		t.Fatalf("failed transitions should not be recorded, got %+v", repo.transitions)
+	}
+}
+
+func TestGoldenService_ListGoldenTransitions_RequiresEditor(t *testing.T) {
+	svc := NewGoldenService(helperWorkflowRepo())
+
+	if _, err := svc.ListGoldenTransitions(context.Background(), "draft"); !errors.Is(err, domain.ErrEditorRequired) {
+		t.Fatalf("ListGoldenTransitions() err = %v, want ErrEditorRequired", err)
+	}
+	if _, err := svc.ListGoldenTransitions(domain.WithEditor(context.Background()), "missing"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("ListGoldenTransitions() err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestGoldenService_WriteMethods_RequireEditor(t *testing.T) {
+	repo := &eventRecordingRepo{}
+	svc := NewGoldenService(repo)
+	ctx := context.Background()
+
+	for name, write := range map[string]func() error{
+		"create": func() error { return svc.CreateGolden(ctx, &domain.Golden{ID: "g", Title: "G"}) },
+		"update": func() error { return svc.UpdateGolden(ctx, &domain.Golden{ID: "g", Title: "G"}) },
+		"delete": func() error { return svc.DeleteGolden(ctx, "g") },
+		"import": func() error {
+			_, err := svc.ImportGoldens(ctx, []domain.Golden{{ID: "g", Title: "G"}}, false)
+			return err
+		},
+	} {
+		if err := write(); !errors.Is(err, domain.ErrEditorRequired) {
+			t.Errorf("%s: err = %v, want ErrEditorRequired", name, err)
+		}
+	}
+	if len(repo.events) != 0 {
+		t.Fatalf("readers should not write, got events %v", helperEventTypes(repo.events))
+	}
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"markitos-it-svc-goldens/internal/domain"
+	"slices"
+	"strings"
+	"time"
+)
+
+type TagService struct {
+	store domain.TagStore
+}
+
+func NewTagService(store domain.TagStore) *TagService {
+	return &TagService{
+		store: store,
+	}
+}
+
+func (s *TagService) ListRegisteredTags(ctx context.Context) ([]domain.RegisteredTag, error) {
+	return s.store.ListRegisteredTags(ctx)
+}
+
+// RegisterTag stores tag.Name in canonical form and drops synonyms that
+// repeat an earlier spelling.
+func (s *TagService) RegisterTag(ctx context.Context, tag *domain.RegisteredTag) error {
+	if err := domain.RequireEditor(ctx); err != nil {
+		return err
+	}
+	tag.Name = domain.Slugify(tag.Name)
+	synonyms := make([]string, 0, len(tag.Synonyms))
+	seen := make(map[string]bool, len(tag.Synonyms))
+	for _, synonym := range tag.Synonyms {
+		synonym = strings.TrimSpace(synonym)
+		if key := domain.TagKey(synonym); !seen[key] {
+			seen[key] = true
+			synonyms = append(synonyms, synonym)
+		}
+	}
+	tag.Synonyms = synonyms
+	if err := tag.Validate(); err != nil {
+		return err
+	}
+
+	tag.CreatedAt = time.Now().UTC()
+	return s.store.RegisterTag(ctx, tag)
+}
+
+func (s *TagService) UnregisterTag(ctx context.Context, name string) error {
+	if err := domain.RequireEditor(ctx); err != nil {
+		return err
+	}
+	return s.store.UnregisterTag(ctx, name)
+}
+
+// RenameTag renames from, as stored in goldens, to the canonical form of to.
+func (s *TagService) RenameTag(ctx context.Context, from, to string) (int, error) {
+	if err := domain.RequireEditor(ctx); err != nil {
+		return 0, err
+	}
+	target, err := canonicalTag(to)
+	if err != nil {
+		return 0, err
+	}
+	if from == "" {
+		return 0, fmt.Errorf("%w: the tag to rename is required", domain.ErrInvalidTag)
+	}
+	if from == target {
+		return 0, fmt.Errorf("%w: %q is already named %q", domain.ErrInvalidTag, from, target)
+	}
+	return s.store.RenameTag(ctx, from, target)
+}
+
+// MergeTags merges sources, as stored in goldens, into the canonical form of
+// target. A source equal to the target is ignored.
+func (s *TagService) MergeTags(ctx context.Context, sources []string, target string) (int, error) {
+	if err := domain.RequireEditor(ctx); err != nil {
+		return 0, err
+	}
+	target, err := canonicalTag(target)
+	if err != nil {
+		return 0, err
+	}
+
+	var merged []string
+	for _, source := range sources {
+		if source != "" && source != target && !slices.Contains(merged, source) {
+			merged = append(merged, source)
+		}
+	}
+	if len(merged) == 0 {
+		return 0, fmt.Errorf("%w: at least one tag other than %q must be merged", domain.ErrInvalidTag, target)
+	}
+	return s.store.MergeTags(ctx, merged, target)
+}
+
+func canonicalTag(tag string) (string, error) {
+	canonical := domain.Slugify(tag)
+	if canonical == "" {
+		return "", fmt.Errorf("%w: %q has no letters or digits", domain.ErrInvalidTag, tag)
+	}
+	return canonical, nil
+}
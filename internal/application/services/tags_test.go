@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"markitos-it-svc-goldens/internal/domain"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// fakeTagStore keeps the registry in memory and records renames and merges.
+type fakeTagStore struct {
+	tags       []domain.RegisteredTag
+	registered []domain.RegisteredTag
+	renamed    [][2]string
+	merged     map[string][]string
+}
+
+func helperTagStore() *fakeTagStore {
+	return &fakeTagStore{tags: []domain.RegisteredTag{
+		{Name: "ci-cd", Synonyms: []string{"continuous delivery"}},
+		{Name: "kubernetes", Synonyms: []string{"k8s"}},
+	}}
+}
+
+func (s *fakeTagStore) ListRegisteredTags(ctx context.Context) ([]domain.RegisteredTag, error) {
+	return slices.Clone(s.tags), nil
+}
+
+func (s *fakeTagStore) RegisterTag(ctx context.Context, tag *domain.RegisteredTag) error {
+	s.registered = append(s.registered, *tag)
+	return nil
+}
+
+func (s *fakeTagStore) UnregisterTag(ctx context.Context, name string) error {
+	return nil
+}
+
+func (s *fakeTagStore) RenameTag(ctx context.Context, from, to string) (int, error) {
+	s.renamed = append(s.renamed, [2]string{from, to})
+	return 1, nil
+}
+
+func (s *fakeTagStore) MergeTags(ctx context.Context, sources []string, target string) (int, error) {
+	if s.merged == nil {
+		s.merged = make(map[string][]string)
+	}
+	s.merged[target] = sources
+	return len(sources), nil
+}
+
+func TestTagService_RegisterTag_Canonicalizes(t *testing.T) {
+	store := helperTagStore()
+	svc := NewTagService(store)
+
+	tag := &domain.RegisteredTag{Name: "Kubernetes Engine", Synonyms: []string{" GKE ", "gke", "Google Kubernetes"}}
+	if err := svc.RegisterTag(helperEditorContext(), tag); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tag.Name != "kubernetes-engine" || !reflect.DeepEqual(tag.Synonyms, []string{"GKE", "Google Kubernetes"}) || tag.CreatedAt.IsZero() {
+		t.Fatalf("RegisterTag() = %+v, want a slug name and distinct synonyms", tag)
+	}
+	if len(store.registered) != 1 {
+		t.Fatalf("expected the tag to be stored, got %+v", store.registered)
+	}
+
+	if err := svc.RegisterTag(helperEditorContext(), &domain.RegisteredTag{Name: "ci-cd", Synonyms: []string{"CICD"}}); !errors.Is(err, domain.ErrInvalidTag) {
+		t.Fatalf("RegisterTag() err = %v, want ErrInvalidTag", err)
+	}
+}
+
+func TestTagService_RenameAndMerge(t *testing.T) {
+	store := helperTagStore()
+	svc := NewTagService(store)
+	ctx := helperEditorContext()
+
+	if _, err := svc.RenameTag(context.Background(), "K8S", "Kubernetes Engine"); !errors.Is(err, domain.ErrEditorRequired) {
+		t.Fatalf("RenameTag() by a reader err = %v, want ErrEditorRequired", err)
+	}
+	if _, err := svc.RenameTag(ctx, "K8S", "Kubernetes Engine"); err != nil {
+		t.Fatalf("RenameTag() unexpected error: %v", err)
+	}
+	if want := [][2]string{{"K8S", "kubernetes-engine"}}; !reflect.DeepEqual(store.renamed, want) {
+		t.Fatalf("RenameTag() stored %v, want %v", store.renamed, want)
+	}
+
+	updated, err := svc.MergeTags(ctx, []string{"CI/CD", "cicd", "ci-cd", "CI/CD", ""}, "CI-CD")
+	if err != nil {
+		t.Fatalf("MergeTags() unexpected error: %v", err)
+	}
+	if want := []string{"CI/CD", "cicd"}; updated != 2 || !reflect.DeepEqual(store.merged["ci-cd"], want) {
+		t.Fatalf("MergeTags() merged %v into ci-cd, want %v", store.merged, want)
+	}
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{name: "rename-to-nothing", call: func() error { _, err := svc.RenameTag(ctx, "k8s", "!!"); return err }},
+		{name: "rename-missing-from", call: func() error { _, err := svc.RenameTag(ctx, "", "kubernetes"); return err }},
+		{name: "rename-to-itself", call: func() error { _, err := svc.RenameTag(ctx, "kubernetes", "Kubernetes"); return err }},
+		{name: "merge-into-itself", call: func() error { _, err := svc.MergeTags(ctx, []string{"ci-cd"}, "ci-cd"); return err }},
+		{name: "merge-nothing", call: func() error { _, err := svc.MergeTags(ctx, nil, "ci-cd"); return err }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, domain.ErrInvalidTag) {
+				t.Fatalf("expected ErrInvalidTag, got %v", err)
+			}
+		})
+	}
+}
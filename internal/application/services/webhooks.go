@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"markitos-it-svc-goldens/internal/domain"
+	"time"
+)
+
+const (
+	DefaultDeliveriesLimit = 50
+	MaxDeliveriesLimit     = 500
+)
+
+// WebhookService manages the webhook registry, which only editors may read
+// or change.
+type WebhookService struct {
+	store domain.WebhookStore
+	// allowedHosts may be targeted over http and on private addresses.
+	allowedHosts []string
+}
+
+func NewWebhookService(store domain.WebhookStore, allowedHosts []string) *WebhookService {
+	return &WebhookService{
+		store:        store,
+		allowedHosts: allowedHosts,
+	}
+}
+
+// CreateSubscription validates sub and fills in its id, creation time and,
+// unless the caller chose one, a random signing secret.
+func (s *WebhookService) CreateSubscription(ctx context.Context, sub *domain.WebhookSubscription) error {
+	if err := domain.RequireEditor(ctx); err != nil {
+		return err
+	}
+	if err := sub.Validate(s.allowedHosts); err != nil {
+		return err
+	}
+
+	id, err := randomHex(16)
+	if err != nil {
+		return err
+	}
+	sub.ID = id
+	if sub.Secret == "" {
+		if sub.Secret, err = randomHex(32); err != nil {
+			return err
+		}
+	}
+	sub.CreatedAt = time.Now().UTC()
+
+	return s.store.CreateSubscription(ctx, sub)
+}
+
+func (s *WebhookService) ListSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	if err := domain.RequireEditor(ctx); err != nil {
+		return nil, err
+	}
+	return s.store.ListSubscriptions(ctx)
+}
+
+func (s *WebhookService) DeleteSubscription(ctx context.Context, id string) error {
+	if err := domain.RequireEditor(ctx); err != nil {
+		return err
+	}
+	return s.store.DeleteSubscription(ctx, id)
+}
+
+// ListDeliveries returns up to limit deliveries, newest first. A limit of
+// zero means DefaultDeliveriesLimit and larger values are capped.
+func (s *WebhookService) ListDeliveries(ctx context.Context, subscriptionID string, limit int) ([]domain.WebhookDelivery, error) {
+	if err := domain.RequireEditor(ctx); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = DefaultDeliveriesLimit
+	}
+	return s.store.ListDeliveries(ctx, subscriptionID, min(limit, MaxDeliveriesLimit))
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate random value: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
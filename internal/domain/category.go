@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// MaxCategoryIDLength matches the goldens.category column.
+const MaxCategoryIDLength = 100
+
+var (
+	ErrInvalidCategory  = errors.New("invalid category")
+	ErrCategoryNotFound = errors.New("category not found")
+	ErrCategoryExists   = errors.New("category already exists")
+	// ErrCategoryInUse is returned when deleting a category that still has
+	// goldens or subcategories.
+	ErrCategoryInUse = errors.New("category in use")
+	// ErrUnknownCategory is returned when a golden names a category that does
+	// not exist.
+	ErrUnknownCategory = errors.New("unknown category")
+)
+
+// Category groups goldens in a tree. Goldens refer to it by ID, a slug that
+// never changes; DisplayName is what users see.
+type Category struct {
+	ID       string
+	ParentID string
+	// DisplayName and Description are free text.
+	DisplayName string
+	Description string
+	// Position orders siblings, lowest first.
+	Position  int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (c Category) Validate() error {
+	if c.ID == "" || c.ID != Slugify(c.ID) {
+		return fmt.Errorf("%w: id %q must be a lowercase slug", ErrInvalidCategory, c.ID)
+	}
+	if len(c.ID) > MaxCategoryIDLength {
+		return fmt.Errorf("%w: id must be at most %d characters", ErrInvalidCategory, MaxCategoryIDLength)
+	}
+	if strings.TrimSpace(c.DisplayName) == "" {
+		return fmt.Errorf("%w: display name is required", ErrInvalidCategory)
+	}
+	if c.ParentID == c.ID {
+		return fmt.Errorf("%w: %s cannot be its own parent", ErrInvalidCategory, c.ID)
+	}
+	return nil
+}
+
+// CategoryKey folds the spellings of a category that only differ in case,
+// accents, spacing or punctuation, such as "DevOps", "devops" and "Dev Ops",
+// into the same key. No two categories may share a key.
+func CategoryKey(value string) string {
+	return strings.ReplaceAll(Slugify(value), "-", "")
+}
+
+// SortCategoryTree orders categories depth first: every category comes right
+// before its subcategories, and siblings are ordered by Position and then by
+// DisplayName. Categories whose parent is not in the list are roots.
+func SortCategoryTree(categories []Category) []Category {
+	known := make(map[string]bool, len(categories))
+	for _, c := range categories {
+		known[c.ID] = true
+	}
+	children := make(map[string][]Category)
+	for _, c := range categories {
+		parent := c.ParentID
+		if !known[parent] {
+			parent = ""
+		}
+		children[parent] = append(children[parent], c)
+	}
+
+	sorted := make([]Category, 0, len(categories))
+	visited := make(map[string]bool, len(categories))
+	var walk func(parent string)
+	walk = func(parent string) {
+		siblings := children[parent]
+		slices.SortFunc(siblings, func(a, b Category) int {
+			if a.Position != b.Position {
+				return cmp.Compare(a.Position, b.Position)
+			}
+			return cmp.Or(cmp.Compare(a.DisplayName, b.DisplayName), cmp.Compare(a.ID, b.ID))
+		})
+		for _, c := range siblings {
+			if visited[c.ID] {
+				continue
+			}
+			visited[c.ID] = true
+			sorted = append(sorted, c)
+			walk(c.ID)
+		}
+	}
+	walk("")
+	// Only a parent cycle leaves categories unreachable from the roots.
+	for _, c := range categories {
+		if !visited[c.ID] {
+			visited[c.ID] = true
+			sorted = append(sorted, c)
+			walk(c.ID)
+		}
+	}
+	return sorted
+}
+
+type CategoryStore interface {
+	// ListCategories returns every category, in no particular order.
+	ListCategories(ctx context.Context) ([]Category, error)
+	GetCategory(ctx context.Context, id string) (*Category, error)
+	CreateCategory(ctx context.Context, category *Category) error
+	// UpdateCategory replaces everything but the id and creation time.
+	UpdateCategory(ctx context.Context, category *Category) error
+	DeleteCategory(ctx context.Context, id string) error
+	// ResolveCategory returns the id of the category whose key, see
+	// CategoryKey, equals the key of value.
+	ResolveCategory(ctx context.Context, value string) (string, error)
+	// CategorySubtree returns id followed by the ids of all its descendants.
+	CategorySubtree(ctx context.Context, id string) ([]string, error)
+}
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCategory_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		category Category
+		wantErr  bool
+	}{
+		{name: "root", category: Category{ID: "devops", DisplayName: "DevOps"}},
+		{name: "child", category: Category{ID: "ci-cd", ParentID: "devops", DisplayName: "CI/CD"}},
+		{name: "missing-id", category: Category{DisplayName: "DevOps"}, wantErr: true},
+		{name: "id-not-slug", category: Category{ID: "Dev Ops", DisplayName: "Dev Ops"}, wantErr: true},
+		{name: "missing-name", category: Category{ID: "devops", DisplayName: "  "}, wantErr: true},
+		{name: "own-parent", category: Category{ID: "devops", ParentID: "devops", DisplayName: "DevOps"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.category.Validate()
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidCategory) {
+				t.Fatalf("expected ErrInvalidCategory, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCategoryKey(t *testing.T) {
+	for _, value := range []string{"DevOps", "devops", "Dev Ops", "dev-ops", " DEV_OPS "} {
+		if got := CategoryKey(value); got != "devops" {
+			t.Errorf("CategoryKey(%q) = %q, want devops", value, got)
+		}
+	}
+	if CategoryKey("Dev Ops") == CategoryKey("Dev Tools") {
+		t.Error("CategoryKey() folded different categories together")
+	}
+}
+
+func TestSortCategoryTree(t *testing.T) {
+	categories := []Category{
+		{ID: "cloud", DisplayName: "Cloud", Position: 2},
+		{ID: "k8s", ParentID: "cloud", DisplayName: "Kubernetes"},
+		{ID: "ci-cd", ParentID: "devops", DisplayName: "CI/CD", Position: 1},
+		{ID: "devops", DisplayName: "DevOps", Position: 1},
+		{ID: "gitops", ParentID: "devops", DisplayName: "GitOps", Position: 0},
+		{ID: "aws", ParentID: "cloud", DisplayName: "AWS"},
+		{ID: "orphan", ParentID: "gone", DisplayName: "Orphan", Position: 3},
+	}
+
+	var ids []string
+	for _, c := range SortCategoryTree(categories) {
+		ids = append(ids, c.ID)
+	}
+
+	want := []string{"devops", "gitops", "ci-cd", "cloud", "aws", "k8s", "orphan"}
+	if !reflect.DeepEqual(ids, want) {
+		t.Fatalf("SortCategoryTree() = %v, want %v", ids, want)
+	}
+}
+
+func TestSortCategoryTree_KeepsCycles(t *testing.T) {
+	categories := []Category{
+		{ID: "a", ParentID: "b", DisplayName: "A"},
+		{ID: "b", ParentID: "a", DisplayName: "B"},
+	}
+
+	if got := SortCategoryTree(categories); len(got) != 2 {
+		t.Fatalf("SortCategoryTree() = %+v, want both categories", got)
+	}
+}
@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+type writeTrackerKey struct{}
+
+// WithWriteTracking returns a context that remembers whether a write was
+// made through it, so that later reads of the same request can avoid stale
+// replicas and see their own writes.
+func WithWriteTracking(ctx context.Context) context.Context {
+	return context.WithValue(ctx, writeTrackerKey{}, new(atomic.Bool))
+}
+
+// MarkWrite records a write on ctx. It is a no-op without WithWriteTracking.
+func MarkWrite(ctx context.Context) {
+	if wrote, ok := ctx.Value(writeTrackerKey{}).(*atomic.Bool); ok {
+		wrote.Store(true)
+	}
+}
+
+// HasWritten reports whether MarkWrite was called on ctx.
+func HasWritten(ctx context.Context) bool {
+	wrote, ok := ctx.Value(writeTrackerKey{}).(*atomic.Bool)
+	return ok && wrote.Load()
+}
@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"context"
+	"testing"
+)
+
+func TestWriteTracking(t *testing.T) {
+	MarkWrite(context.Background())
+	if HasWritten(context.Background()) {
+		t.Fatal("untracked context must never report writes")
+	}
+
+	ctx := WithWriteTracking(context.Background())
+	if HasWritten(ctx) {
+		t.Fatal("expected no write yet")
+	}
+
+	// Writes made through derived contexts are visible to the whole request.
+	child, cancel := context.WithCancel(ctx)
+	defer cancel()
+	MarkWrite(child)
+	if !HasWritten(ctx) {
+		t.Fatal("expected write to be recorded")
+	}
+}
@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrNotFound      = errors.New("golden not found")
+	ErrAlreadyExists = errors.New("golden already exists")
+	// ErrSlugTaken is the ErrAlreadyExists of a slug, rather than an id,
+	// already used by another golden.
+	ErrSlugTaken = fmt.Errorf("%w: slug taken", ErrAlreadyExists)
+	// ErrUnavailable marks failures of the storage backend itself, such as a
+	// lost connection or a database failover, that may succeed on retry.
+	ErrUnavailable = errors.New("storage unavailable")
+	// ErrReadOnly marks writes to a storage backend that only serves reads.
+	ErrReadOnly = errors.New("storage is read-only")
+)
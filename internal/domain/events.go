@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type EventType string
+
+const (
+	EventGoldenCreated EventType = "GoldenCreated"
+	EventGoldenUpdated EventType = "GoldenUpdated"
+	EventGoldenDeleted EventType = "GoldenDeleted"
+	// EventGoldenStatusChanged is raised by status transitions, with a
+	// StatusChangedEventPayload.
+	EventGoldenStatusChanged EventType = "GoldenStatusChanged"
+)
+
+type Event struct {
+	ID          string
+	Type        EventType
+	AggregateID string
+	OccurredAt  time.Time
+	Payload     []byte
+}
+
+// GoldenEventPayload is the JSON body of golden events. The content itself
+// is left out; consumers fetch it by id when they need it.
+type GoldenEventPayload struct {
+	ID          string    `json:"id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Category    string    `json:"category"`
+	Tags        []string  `json:"tags"`
+	CoverImage  string    `json:"cover_image"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	ContentSize int64     `json:"content_size"`
+	ContentHash string    `json:"content_hash"`
+	Status      string    `json:"status"`
+}
+
+// StatusChangedEventPayload is the JSON body of GoldenStatusChanged events:
+// the golden in its new status, the status it left and the reason given.
+type StatusChangedEventPayload struct {
+	GoldenEventPayload
+	PreviousStatus string `json:"previous_status"`
+	Reason         string `json:"reason"`
+}
+
+func NewGoldenEvent(eventType EventType, doc Golden) (Event, error) {
+	return newEvent(eventType, doc.ID, goldenEventPayload(doc))
+}
+
+func NewStatusChangedEvent(doc Golden, transition GoldenTransition) (Event, error) {
+	return newEvent(EventGoldenStatusChanged, doc.ID, StatusChangedEventPayload{
+		GoldenEventPayload: goldenEventPayload(doc),
+		PreviousStatus:     string(transition.From),
+		Reason:             transition.Reason,
+	})
+}
+
+func goldenEventPayload(doc Golden) GoldenEventPayload {
+	doc = doc.WithView(GoldenViewBasic)
+	return GoldenEventPayload{
+		ID:          doc.ID,
+		Title:       doc.Title,
+		Description: doc.Description,
+		Category:    doc.Category,
+		Tags:        doc.Tags,
+		CoverImage:  doc.CoverImage,
+		UpdatedAt:   doc.UpdatedAt,
+		ContentSize: doc.ContentSize,
+		ContentHash: doc.ContentHash,
+		Status:      string(doc.Status),
+	}
+}
+
+func newEvent(eventType EventType, aggregateID string, body any) (Event, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return Event{}, fmt.Errorf("failed to encode %s payload: %w", eventType, err)
+	}
+
+	id, err := newEventID()
+	if err != nil {
+		return Event{}, err
+	}
+
+	return Event{
+		ID:          id,
+		Type:        eventType,
+		AggregateID: aggregateID,
+		OccurredAt:  time.Now().UTC(),
+		Payload:     payload,
+	}, nil
+}
+
+func newEventID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate event id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+type EventPublisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+type OutboxRecord struct {
+	Event
+	Attempts int
+}
+
+// OutboxStore hands out pending events to relays. Claimed records are leased
+// so that concurrent relays on other replicas skip them until the lease ends.
+type OutboxStore interface {
+	ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]OutboxRecord, error)
+	MarkDelivered(ctx context.Context, eventID string) error
+	MarkFailed(ctx context.Context, eventID string, cause error, retryIn time.Duration, dead bool) error
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestNewGoldenEvent(t *testing.T) {
+	prefix := HelperRandomAlphaPrefix(t, 8)
+	doc := Golden{
+		ID:         prefix,
+		Title:      prefix + "-title",
+		Category:   "DevOps",
+		Tags:       []string{"go"},
+		UpdatedAt:  time.Date(2026, 3, 6, 12, 0, 0, 0, time.UTC),
+		ContentB64: "Y29udGVudA==",
+	}
+
+	event, err := NewGoldenEvent(EventGoldenUpdated, doc)
+	if err != nil {
+		t.Fatalf("NewGoldenEvent() unexpected error: %v", err)
+	}
+	if event.ID == "" || event.Type != EventGoldenUpdated || event.AggregateID != prefix || event.OccurredAt.IsZero() {
+		t.Fatalf("unexpected event envelope: %+v", event)
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		t.Fatalf("payload is not JSON: %v", err)
+	}
+	if _, ok := payload["content_b64"]; ok {
+		t.Error("payload must not carry the content")
+	}
+	if payload["category"] != "DevOps" || payload["content_hash"] == "" {
+		t.Errorf("unexpected payload: %v", payload)
+	}
+
+	other, _ := NewGoldenEvent(EventGoldenUpdated, doc)
+	if other.ID == event.ID {
+		t.Error("expected unique event ids")
+	}
+}
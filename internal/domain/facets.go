@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"cmp"
+	"slices"
+)
+
+// FacetCount is a category or tag with the number of goldens that have it.
+type FacetCount struct {
+	Value string
+	Count int
+}
+
+// CountFacets tallies the values of docs in the order the repositories return
+// facets: most used first, then alphabetically. Empty values are skipped and
+// a golden counts once per value.
+func CountFacets(docs []Golden, values func(Golden) []string) []FacetCount {
+	counts := make(map[string]int)
+	for _, doc := range docs {
+		seen := make(map[string]bool)
+		for _, value := range values(doc) {
+			if value != "" && !seen[value] {
+				seen[value] = true
+				counts[value]++
+			}
+		}
+	}
+
+	facets := make([]FacetCount, 0, len(counts))
+	for value, count := range counts {
+		facets = append(facets, FacetCount{Value: value, Count: count})
+	}
+	slices.SortFunc(facets, func(a, b FacetCount) int {
+		if a.Count != b.Count {
+			return cmp.Compare(b.Count, a.Count)
+		}
+		return cmp.Compare(a.Value, b.Value)
+	})
+	return facets
+}
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCountFacets(t *testing.T) {
+	docs := []Golden{
+		{ID: "a", Category: "DevOps", Tags: []string{"k8s", "ci-cd", "k8s"}},
+		{ID: "b", Category: "APIs", Tags: []string{"rest"}},
+		{ID: "c", Category: "DevOps", Tags: []string{"ci-cd"}},
+		{ID: "d", Tags: []string{"ci-cd", "rest"}},
+	}
+
+	categories := CountFacets(docs, func(doc Golden) []string { return []string{doc.Category} })
+	want := []FacetCount{{Value: "DevOps", Count: 2}, {Value: "APIs", Count: 1}}
+	if !reflect.DeepEqual(categories, want) {
+		t.Fatalf("categories = %v, want %v", categories, want)
+	}
+
+	tags := CountFacets(docs, func(doc Golden) []string { return doc.Tags })
+	want = []FacetCount{{Value: "ci-cd", Count: 3}, {Value: "rest", Count: 2}, {Value: "k8s", Count: 1}}
+	if !reflect.DeepEqual(tags, want) {
+		t.Fatalf("tags = %v, want %v", tags, want)
+	}
+}
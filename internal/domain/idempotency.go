@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrIdempotencyMismatch means an idempotency key was reused for a
+	// different request.
+	ErrIdempotencyMismatch = errors.New("idempotency key reused with a different request")
+	// ErrIdempotencyInProgress means the first request with the key has not
+	// finished yet.
+	ErrIdempotencyInProgress = errors.New("request with this idempotency key is still in progress")
+)
+
+// IdempotencyRecord remembers the outcome of a write made with an idempotency
+// key. Response is nil while the first request is still running.
+type IdempotencyRecord struct {
+	Key         string
+	Method      string
+	RequestHash string
+	Response    []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+type IdempotencyStore interface {
+	// Reserve claims key for method during lease. It returns nil when the
+	// caller now owns the key and must Complete or Release it, or the live
+	// record of an earlier request otherwise. Expired records, including
+	// reservations whose owner died, are claimed again.
+	Reserve(ctx context.Context, key, method, requestHash string, lease time.Duration) (*IdempotencyRecord, error)
+	// Complete stores the response of a reserved key and keeps it for ttl.
+	Complete(ctx context.Context, key, method string, response []byte, ttl time.Duration) error
+	// Release forgets a reserved key whose request failed, so it can be retried.
+	Release(ctx context.Context, key, method string) error
+	// PurgeExpired deletes expired records and returns how many were removed.
+	PurgeExpired(ctx context.Context) (int64, error)
+}
@@ -0,0 +1,15 @@
+package domain
+
+type ImportStatus int
+
+const (
+	ImportStatusCreated ImportStatus = iota + 1
+	ImportStatusUpdated
+	ImportStatusFailed
+)
+
+type ImportResult struct {
+	ID     string
+	Status ImportStatus
+	Err    error
+}
@@ -1,11 +1,25 @@
 package domain
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 )
 
+type GoldenView int
+
+const (
+	GoldenViewFull GoldenView = iota
+	GoldenViewBasic
+)
+
 type Golden struct {
 	ID          string
+	Slug        string
 	Title       string
 	Description string
 	Category    string
@@ -13,4 +27,59 @@ type Golden struct {
 	UpdatedAt   time.Time
 	ContentB64  string
 	CoverImage  string
+	ContentSize int64
+	ContentHash string
+	Status      GoldenStatus
+	// SubmittedAt, PublishedAt and ArchivedAt are the last times the golden
+	// entered each status, zero if it never did.
+	SubmittedAt time.Time
+	PublishedAt time.Time
+	ArchivedAt  time.Time
+	// PublishAt is when a scheduled golden goes live and ExpireAt when a
+	// live golden stops being visible and gets archived; zero means never.
+	PublishAt time.Time
+	ExpireAt  time.Time
+}
+
+// ContentDigest returns the size in bytes and the hex encoded SHA-256 of the
+// base64 content, so clients can detect changes without downloading it.
+func ContentDigest(contentB64 string) (int64, string) {
+	sum := sha256.Sum256([]byte(contentB64))
+	return int64(len(contentB64)), hex.EncodeToString(sum[:])
+}
+
+// WithView projects the golden for the given view. The basic view drops the
+// content and keeps only its digest.
+func (g Golden) WithView(view GoldenView) Golden {
+	if g.ContentHash == "" && g.ContentB64 != "" {
+		g.ContentSize, g.ContentHash = ContentDigest(g.ContentB64)
+	}
+	if view == GoldenViewBasic {
+		g.ContentB64 = ""
+	}
+	return g
+}
+
+// Validate checks the fields required to persist a golden.
+func (g Golden) Validate() error {
+	if strings.TrimSpace(g.ID) == "" {
+		return errors.New("id is required")
+	}
+	if strings.TrimSpace(g.Title) == "" {
+		return errors.New("title is required")
+	}
+	if _, err := base64.StdEncoding.DecodeString(g.ContentB64); err != nil {
+		return fmt.Errorf("content_b64 is not valid base64: %w", err)
+	}
+	if g.Status != "" && !g.Status.Valid() {
+		return fmt.Errorf("%w: %q", ErrInvalidStatus, g.Status)
+	}
+	if g.Status == StatusScheduled && g.PublishAt.IsZero() {
+		return fmt.Errorf("%w: a scheduled golden needs a publish_at", ErrInvalidStatus)
+	}
+	if !g.PublishAt.IsZero() && !g.ExpireAt.IsZero() && !g.ExpireAt.After(g.PublishAt) {
+		return fmt.Errorf("%w: expire_at must be after publish_at", ErrInvalidSchedule)
+	}
+
+	return nil
 }
@@ -81,4 +81,89 @@ func TestGolden_ShouldExposeZeroValueAsEmptyDomainState(t *testing.T) {
 	if golden.CoverImage != "" {
 		t.Fatalf("expected empty CoverImage, got %s", golden.CoverImage)
 	}
+	if golden.ContentSize != 0 {
+		t.Fatalf("expected zero ContentSize, got %d", golden.ContentSize)
+	}
+	if golden.ContentHash != "" {
+		t.Fatalf("expected empty ContentHash, got %s", golden.ContentHash)
+	}
+}
+
+func TestContentDigest_ShouldBeStableForSameContent(t *testing.T) {
+	content := HelperRandomAlphaPrefix(t, 16)
+
+	size, hash := ContentDigest(content)
+	otherSize, otherHash := ContentDigest(content)
+
+	if size != int64(len(content)) {
+		t.Fatalf("expected size %d, got %d", len(content), size)
+	}
+	if len(hash) != 64 {
+		t.Fatalf("expected hex sha256 of length 64, got %q", hash)
+	}
+	if size != otherSize || hash != otherHash {
+		t.Fatalf("expected stable digest, got %d/%s and %d/%s", size, hash, otherSize, otherHash)
+	}
+}
+
+func TestGolden_WithView_ShouldProjectContent(t *testing.T) {
+	content := HelperRandomAlphaPrefix(t, 12)
+	wantSize, wantHash := ContentDigest(content)
+	golden := Golden{ID: "id", ContentB64: content}
+
+	full := golden.WithView(GoldenViewFull)
+	if full.ContentB64 != content {
+		t.Fatalf("expected full view to keep content, got %q", full.ContentB64)
+	}
+	if full.ContentSize != wantSize || full.ContentHash != wantHash {
+		t.Fatalf("expected full view digest %d/%s, got %d/%s", wantSize, wantHash, full.ContentSize, full.ContentHash)
+	}
+
+	basic := golden.WithView(GoldenViewBasic)
+	if basic.ContentB64 != "" {
+		t.Fatalf("expected basic view without content, got %q", basic.ContentB64)
+	}
+	if basic.ContentSize != wantSize || basic.ContentHash != wantHash {
+		t.Fatalf("expected basic view digest %d/%s, got %d/%s", wantSize, wantHash, basic.ContentSize, basic.ContentHash)
+	}
+	if golden.ContentB64 != content {
+		t.Fatalf("expected original golden untouched, got %q", golden.ContentB64)
+	}
+}
+
+func TestGolden_Validate(t *testing.T) {
+	prefix := HelperRandomAlphaPrefix(t, 8)
+	tests := []struct {
+		name    string
+		golden  Golden
+		wantErr bool
+	}{
+		{name: prefix + "-valid", golden: Golden{ID: prefix, Title: prefix, ContentB64: "Y29udGVudA=="}},
+		{name: prefix + "-valid-without-content", golden: Golden{ID: prefix, Title: prefix}},
+		{name: prefix + "-missing-id", golden: Golden{Title: prefix}, wantErr: true},
+		{name: prefix + "-blank-title", golden: Golden{ID: prefix, Title: "  "}, wantErr: true},
+		{name: prefix + "-invalid-content", golden: Golden{ID: prefix, Title: prefix, ContentB64: "%%%"}, wantErr: true},
+		{name: prefix + "-expire-only", golden: Golden{ID: prefix, Title: prefix, ExpireAt: time.Now()}},
+		{name: prefix + "-scheduled", golden: Golden{ID: prefix, Title: prefix, Status: StatusScheduled, PublishAt: time.Now()}},
+		{name: prefix + "-scheduled-without-publish-at", golden: Golden{ID: prefix, Title: prefix, Status: StatusScheduled}, wantErr: true},
+		{name: prefix + "-expire-before-publish", golden: Golden{ID: prefix, Title: prefix, PublishAt: time.Now(), ExpireAt: time.Now().Add(-time.Hour)}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.golden.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewTxOptions(t *testing.T) {
+	if got := NewTxOptions(); got != (TxOptions{Isolation: IsolationDefault, MaxRetries: DefaultTxMaxRetries}) {
+		t.Errorf("NewTxOptions() = %+v", got)
+	}
+
+	got := NewTxOptions(WithIsolation(IsolationSerializable), WithMaxRetries(0))
+	if got != (TxOptions{Isolation: IsolationSerializable, MaxRetries: 0}) {
+		t.Errorf("NewTxOptions(opts) = %+v", got)
+	}
 }
@@ -4,10 +4,99 @@ import (
 	"context"
 )
 
+// Repository stores goldens. Its reads may leave out the goldens that are not
+// live when ctx has no editor rights, see WithEditor; callers filter them
+// anyway.
 type Repository interface {
-	GetAll(ctx context.Context) ([]Golden, error)
-	GetByID(ctx context.Context, id string) (*Golden, error)
+	GetAll(ctx context.Context, view GoldenView) ([]Golden, error)
+	GetByID(ctx context.Context, id string, view GoldenView) (*Golden, error)
+	// GetByIDs returns the goldens among ids in no particular order, leaving
+	// out the ids that do not exist.
+	GetByIDs(ctx context.Context, ids []string, view GoldenView) ([]Golden, error)
+	// GetByCategories returns the goldens in any of categories, most recently
+	// updated first like GetAll.
+	GetByCategories(ctx context.Context, categories []string, view GoldenView) ([]Golden, error)
+	// GetBySlug finds a golden by its current slug or, failing that, by a
+	// slug it had before a rename.
+	GetBySlug(ctx context.Context, slug string, view GoldenView) (*Golden, error)
+	// SlugsInUse lists the current and former slugs equal to base or to base
+	// with a "-N" suffix, to pick a free one with UniqueSlug.
+	SlugsInUse(ctx context.Context, base string) ([]string, error)
+	// CountCategories counts the published goldens per category, only among
+	// those tagged tag unless it is empty. Goldens without category are left
+	// out.
+	CountCategories(ctx context.Context, tag string) ([]FacetCount, error)
+	// CountTags counts the published goldens per tag, only among those in
+	// one of categories unless it is empty.
+	CountTags(ctx context.Context, categories []string) ([]FacetCount, error)
+	// Create stores a golden without Status as a draft.
 	Create(ctx context.Context, doc *Golden) error
+	// Update keeps the stored slug when doc.Slug is empty. A changed slug
+	// leaves a redirect from the old one for GetBySlug. With an empty
+	// doc.Status the stored status and its timestamps are kept and copied to
+	// doc; otherwise they are written as given. PublishAt and ExpireAt are
+	// always written as given, a zero one clearing the stored value.
 	Update(ctx context.Context, doc *Golden) error
 	Delete(ctx context.Context, id string) error
+	// Upsert creates or updates all docs atomically. With dryRun the changes
+	// are rolled back but the results still report what would have happened.
+	// The status fields are written as Update does, new goldens without
+	// Status becoming drafts; existing goldens keep their slug, and a zero
+	// PublishAt or ExpireAt keeps the stored one.
+	Upsert(ctx context.Context, docs []Golden, dryRun bool) ([]ImportResult, error)
+	// AppendTransition adds an entry to the status history of a golden.
+	AppendTransition(ctx context.Context, transition GoldenTransition) error
+	// ListTransitions returns the status history of a golden, oldest first.
+	ListTransitions(ctx context.Context, id string) ([]GoldenTransition, error)
+	// AppendEvents records events in the outbox for later delivery.
+	AppendEvents(ctx context.Context, events ...Event) error
+	// RunInTx runs fn with a repository bound to a single transaction, which
+	// is committed when fn returns nil and rolled back otherwise. fn may run
+	// more than once when the transaction is retried, so it must not have
+	// side effects outside tx. Calls on an already bound repository join its
+	// transaction and ignore opts.
+	RunInTx(ctx context.Context, fn func(tx Repository) error, opts ...TxOption) error
+}
+
+type IsolationLevel int
+
+const (
+	// IsolationDefault uses the database default, READ COMMITTED in PostgreSQL.
+	IsolationDefault IsolationLevel = iota
+	IsolationReadCommitted
+	IsolationRepeatableRead
+	IsolationSerializable
+)
+
+// DefaultTxMaxRetries is how many times RunInTx reruns fn after a
+// serialization failure or deadlock unless WithMaxRetries says otherwise.
+const DefaultTxMaxRetries = 3
+
+type TxOptions struct {
+	Isolation IsolationLevel
+	// MaxRetries bounds the reruns of the whole transaction when the
+	// database aborts it because of a conflict with a concurrent one.
+	MaxRetries int
+}
+
+type TxOption func(*TxOptions)
+
+func WithIsolation(level IsolationLevel) TxOption {
+	return func(o *TxOptions) {
+		o.Isolation = level
+	}
+}
+
+func WithMaxRetries(n int) TxOption {
+	return func(o *TxOptions) {
+		o.MaxRetries = n
+	}
+}
+
+func NewTxOptions(opts ...TxOption) TxOptions {
+	o := TxOptions{MaxRetries: DefaultTxMaxRetries}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
 }
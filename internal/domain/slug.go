@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxSlugLength leaves room for a collision suffix within VARCHAR(255).
+const MaxSlugLength = 200
+
+// transliterations covers the Latin letters that do not decompose into a
+// base letter plus combining marks.
+var transliterations = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'Æ': "ae", 'œ': "oe", 'Œ': "oe", 'ø': "o", 'Ø': "o",
+	'ł': "l", 'Ł': "l", 'đ': "d", 'Đ': "d", 'ð': "d", 'Ð': "d", 'þ': "th", 'Þ': "th",
+	'ı': "i", '&': "and",
+}
+
+// Slugify derives a lowercase, URL friendly slug from s: accents are
+// stripped, other runs of characters become single hyphens, and the result is
+// cut to MaxSlugLength. It returns "" when s has no letters or digits.
+func Slugify(s string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range norm.NFD.String(s) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+
+		var part string
+		switch {
+		case transliterations[r] != "":
+			part = transliterations[r]
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			// Scripts without a Latin transliteration are kept as is.
+			part = string(unicode.ToLower(r))
+		default:
+			hyphen = b.Len() > 0
+			continue
+		}
+
+		if hyphen {
+			b.WriteByte('-')
+			hyphen = false
+		}
+		b.WriteString(part)
+	}
+
+	slug := b.String()
+	if len(slug) > MaxSlugLength {
+		slug = strings.TrimRight(truncateUTF8(slug, MaxSlugLength), "-")
+	}
+	return slug
+}
+
+// UniqueSlug returns base, or base with the lowest "-N" suffix (from 2) that
+// is not in taken.
+func UniqueSlug(base string, taken []string) string {
+	used := make(map[string]bool, len(taken))
+	for _, slug := range taken {
+		used[slug] = true
+	}
+	if !used[base] {
+		return base
+	}
+
+	for n := 2; ; n++ {
+		candidate := base + "-" + strconv.Itoa(n)
+		if !used[candidate] {
+			return candidate
+		}
+	}
+}
+
+func truncateUTF8(s string, n int) string {
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// BaseSlug returns the slug g asks for before collisions are resolved: its own
+// Slug when set, otherwise one derived from its title or, for titles without
+// letters or digits, from its id.
+func (g Golden) BaseSlug() string {
+	for _, source := range []string{g.Slug, g.Title, g.ID} {
+		if slug := Slugify(source); slug != "" {
+			return slug
+		}
+	}
+	return "golden"
+}
@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "Getting Started with Keptn", want: "getting-started-with-keptn"},
+		{in: "  CI/CD -- Pipelines!  ", want: "ci-cd-pipelines"},
+		{in: "Configuración de España", want: "configuracion-de-espana"},
+		{in: "Straße & Smørrebrød", want: "strasse-and-smorrebrod"},
+		{in: "Łódź Œuvre", want: "lodz-oeuvre"},
+		{in: "YouTube Data API v3", want: "youtube-data-api-v3"},
+		{in: "Привет мир", want: "привет-мир"},
+		{in: "!!!", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := Slugify(tt.in); got != tt.want {
+				t.Errorf("Slugify(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlugify_Truncates(t *testing.T) {
+	got := Slugify(strings.Repeat("é", MaxSlugLength) + " tail")
+	if len(got) > MaxSlugLength || !utf8.ValidString(got) || strings.HasSuffix(got, "-") {
+		t.Fatalf("Slugify() = %q (%d bytes)", got, len(got))
+	}
+
+	got = Slugify(strings.Repeat("ab ", MaxSlugLength))
+	if len(got) > MaxSlugLength || strings.HasSuffix(got, "-") {
+		t.Fatalf("Slugify() = %q (%d bytes)", got, len(got))
+	}
+}
+
+func TestUniqueSlug(t *testing.T) {
+	tests := []struct {
+		name  string
+		taken []string
+		want  string
+	}{
+		{name: "free", taken: nil, want: "keptn"},
+		{name: "taken", taken: []string{"keptn"}, want: "keptn-2"},
+		{name: "gap", taken: []string{"keptn", "keptn-2", "keptn-4"}, want: "keptn-3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UniqueSlug("keptn", tt.taken); got != tt.want {
+				t.Errorf("UniqueSlug() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGolden_BaseSlug(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  Golden
+		want string
+	}{
+		{name: "explicit", doc: Golden{ID: "id", Slug: "My Slug", Title: "Title"}, want: "my-slug"},
+		{name: "title", doc: Golden{ID: "id", Title: "Hello World"}, want: "hello-world"},
+		{name: "id", doc: Golden{ID: "legacy-id", Title: "???"}, want: "legacy-id"},
+		{name: "fallback", doc: Golden{ID: "!", Title: "?"}, want: "golden"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.doc.BaseSlug(); got != tt.want {
+				t.Errorf("BaseSlug() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewID(t *testing.T) {
+	a, b := NewID(), NewID()
+	if a == b {
+		t.Fatal("expected distinct ids")
+	}
+	if len(a) != 36 || a[14] != '7' || !strings.ContainsRune("89ab", rune(a[19])) {
+		t.Fatalf("NewID() = %q is not a UUIDv7", a)
+	}
+	if a[:13] > b[:13] {
+		t.Fatalf("ids must sort by creation time: %s > %s", a, b)
+	}
+}
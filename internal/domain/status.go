@@ -0,0 +1,178 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// GoldenStatus is the editorial state of a golden. Only published goldens,
+// and scheduled ones whose publish_at has come, are visible to readers
+// without editor rights.
+type GoldenStatus string
+
+const (
+	StatusDraft     GoldenStatus = "draft"
+	StatusInReview  GoldenStatus = "in_review"
+	StatusScheduled GoldenStatus = "scheduled"
+	StatusPublished GoldenStatus = "published"
+	StatusArchived  GoldenStatus = "archived"
+)
+
+// MaxTransitionReasonLength bounds the comment kept with a transition.
+const MaxTransitionReasonLength = 2000
+
+var (
+	ErrInvalidStatus     = errors.New("invalid status")
+	ErrInvalidTransition = errors.New("invalid status transition")
+	ErrInvalidSchedule   = errors.New("invalid schedule")
+	ErrEditorRequired    = errors.New("editor rights required")
+)
+
+// statusTransitions is the editorial workflow: a draft is submitted for
+// review, then either sent back to draft or published, and a published
+// golden is eventually archived. Archived goldens can be reopened as drafts.
+// A reviewed golden with a future publish_at can be scheduled instead; the
+// scheduler publishes it at that time unless it is sent back to review.
+var statusTransitions = map[GoldenStatus][]GoldenStatus{
+	StatusDraft:     {StatusInReview},
+	StatusInReview:  {StatusDraft, StatusScheduled, StatusPublished},
+	StatusScheduled: {StatusInReview, StatusPublished},
+	StatusPublished: {StatusArchived},
+	StatusArchived:  {StatusDraft},
+}
+
+func (s GoldenStatus) Valid() bool {
+	_, ok := statusTransitions[s]
+	return ok
+}
+
+func (s GoldenStatus) CanTransitionTo(to GoldenStatus) bool {
+	return slices.Contains(statusTransitions[s], to)
+}
+
+// GoldenTransition is an entry of the status history of a golden.
+type GoldenTransition struct {
+	GoldenID string
+	From     GoldenStatus
+	To       GoldenStatus
+	Reason   string
+	At       time.Time
+}
+
+// Transition moves g to status to at the given time, recording it in the
+// timestamp of the new status, and returns the history entry to store.
+func (g *Golden) Transition(to GoldenStatus, reason string, at time.Time) (GoldenTransition, error) {
+	if !to.Valid() {
+		return GoldenTransition{}, fmt.Errorf("%w: %q", ErrInvalidStatus, to)
+	}
+	if len(reason) > MaxTransitionReasonLength {
+		return GoldenTransition{}, fmt.Errorf("%w: reason must be at most %d characters", ErrInvalidTransition, MaxTransitionReasonLength)
+	}
+	if !g.Status.CanTransitionTo(to) {
+		return GoldenTransition{}, fmt.Errorf("%w: %s cannot go from %s to %s", ErrInvalidTransition, g.ID, g.Status, to)
+	}
+	if to == StatusScheduled && !g.PublishAt.After(at) {
+		return GoldenTransition{}, fmt.Errorf("%w: %s needs a future publish_at to be scheduled", ErrInvalidTransition, g.ID)
+	}
+
+	switch to {
+	case StatusInReview:
+		g.SubmittedAt = at
+	case StatusPublished:
+		g.PublishedAt = at
+	case StatusArchived:
+		g.ArchivedAt = at
+	}
+	transition := GoldenTransition{GoldenID: g.ID, From: g.Status, To: to, Reason: reason, At: at}
+	g.Status = to
+	return transition, nil
+}
+
+// Live reports whether g is shown to readers at now: published, or
+// scheduled with its publish_at reached, and not past its expire_at. It does
+// not wait for the scheduler to flip the status.
+func (g Golden) Live(now time.Time) bool {
+	switch g.Status {
+	case StatusPublished:
+	case StatusScheduled:
+		if g.PublishAt.IsZero() || g.PublishAt.After(now) {
+			return false
+		}
+	default:
+		return false
+	}
+	return g.ExpireAt.IsZero() || g.ExpireAt.After(now)
+}
+
+// DueTransition returns the status change the scheduler owes g at now: the
+// publication of a scheduled golden once its publish_at has come, or the
+// archiving of a published one past its expire_at. at is the scheduled time.
+func (g Golden) DueTransition(now time.Time) (to GoldenStatus, at time.Time, ok bool) {
+	switch {
+	case g.Status == StatusScheduled && !g.PublishAt.IsZero() && !g.PublishAt.After(now):
+		return StatusPublished, g.PublishAt, true
+	case g.Status == StatusPublished && !g.ExpireAt.IsZero() && !g.ExpireAt.After(now):
+		return StatusArchived, g.ExpireAt, true
+	}
+	return "", time.Time{}, false
+}
+
+// ScheduleStore hands due goldens to the scheduler. Every replica runs a
+// scheduler, so RunDue holds a lock that lets only one of them at a time
+// flip statuses.
+type ScheduleStore interface {
+	// RunDue calls fn in a transaction with the ids of at most limit goldens
+	// that have a transition due at now, see Golden.DueTransition. It
+	// reports false without calling fn when another replica holds the lock.
+	RunDue(ctx context.Context, now time.Time, limit int, fn func(tx Repository, ids []string) error) (bool, error)
+	// NextDue returns the earliest publish_at or expire_at still pending,
+	// zero if there is none.
+	NextDue(ctx context.Context) (time.Time, error)
+}
+
+// Visible reports whether g may be shown to a reader now, see WithEditor.
+func (g Golden) Visible(ctx context.Context) bool {
+	return IsEditor(ctx) || g.Live(time.Now())
+}
+
+type editorKey struct{}
+
+// WithEditor returns a context whose caller has editor rights: it may write
+// goldens, categories and tags, change the status of goldens and read them
+// whatever their status.
+func WithEditor(ctx context.Context) context.Context {
+	return context.WithValue(ctx, editorKey{}, true)
+}
+
+func IsEditor(ctx context.Context) bool {
+	editor, _ := ctx.Value(editorKey{}).(bool)
+	return editor
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a context naming its caller with id, an opaque value
+// that tells apart callers with the same rights without revealing their
+// credentials.
+func WithPrincipal(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, principalKey{}, id)
+}
+
+// Principal returns the id given to WithPrincipal, or "" for an anonymous
+// caller.
+func Principal(ctx context.Context) string {
+	id, _ := ctx.Value(principalKey{}).(string)
+	return id
+}
+
+// RequireEditor returns ErrEditorRequired unless the caller is an editor.
+// Every write goes through it: readers only ever read live goldens.
+func RequireEditor(ctx context.Context) error {
+	if !IsEditor(ctx) {
+		return ErrEditorRequired
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGolden_Transition(t *testing.T) {
+	at := time.Date(2026, 3, 6, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		from    GoldenStatus
+		to      GoldenStatus
+		reason  string
+		wantErr error
+	}{
+		{name: "submit", from: StatusDraft, to: StatusInReview},
+		{name: "reject", from: StatusInReview, to: StatusDraft, reason: "needs examples"},
+		{name: "publish", from: StatusInReview, to: StatusPublished},
+		{name: "schedule", from: StatusInReview, to: StatusScheduled},
+		{name: "unschedule", from: StatusScheduled, to: StatusInReview},
+		{name: "publish-scheduled", from: StatusScheduled, to: StatusPublished},
+		{name: "schedule-draft", from: StatusDraft, to: StatusScheduled, wantErr: ErrInvalidTransition},
+		{name: "archive", from: StatusPublished, to: StatusArchived},
+		{name: "reopen", from: StatusArchived, to: StatusDraft},
+		{name: "skip-review", from: StatusDraft, to: StatusPublished, wantErr: ErrInvalidTransition},
+		{name: "unpublish", from: StatusPublished, to: StatusDraft, wantErr: ErrInvalidTransition},
+		{name: "same-status", from: StatusDraft, to: StatusDraft, wantErr: ErrInvalidTransition},
+		{name: "unknown-status", from: StatusDraft, to: "deleted", wantErr: ErrInvalidStatus},
+		{name: "long-reason", from: StatusDraft, to: StatusInReview, reason: string(make([]byte, MaxTransitionReasonLength+1)), wantErr: ErrInvalidTransition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := Golden{ID: "g", Status: tt.from, PublishAt: at.Add(time.Hour)}
+			transition, err := doc.Transition(tt.to, tt.reason, at)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) || doc.Status != tt.from {
+					t.Fatalf("Transition() err = %v, status %s; want %v and no change", err, doc.Status, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Transition() unexpected error: %v", err)
+			}
+			want := GoldenTransition{GoldenID: "g", From: tt.from, To: tt.to, Reason: tt.reason, At: at}
+			if transition != want || doc.Status != tt.to {
+				t.Fatalf("Transition() = %+v, status %s; want %+v", transition, doc.Status, want)
+			}
+		})
+	}
+}
+
+func TestGolden_Transition_SetsTimestamps(t *testing.T) {
+	doc := Golden{Status: StatusDraft}
+	times := map[GoldenStatus]*time.Time{
+		StatusInReview:  &doc.SubmittedAt,
+		StatusPublished: &doc.PublishedAt,
+		StatusArchived:  &doc.ArchivedAt,
+	}
+	at := time.Date(2026, 3, 6, 12, 0, 0, 0, time.UTC)
+	for _, to := range []GoldenStatus{StatusInReview, StatusPublished, StatusArchived} {
+		at = at.Add(time.Hour)
+		if _, err := doc.Transition(to, "", at); err != nil {
+			t.Fatalf("Transition(%s) unexpected error: %v", to, err)
+		}
+		if !times[to].Equal(at) {
+			t.Fatalf("Transition(%s) set %v, want %v", to, *times[to], at)
+		}
+	}
+}
+
+func TestGolden_Visible(t *testing.T) {
+	ctx := context.Background()
+	if !(Golden{Status: StatusPublished}).Visible(ctx) {
+		t.Fatal("published goldens should be visible to readers")
+	}
+	if (Golden{Status: StatusDraft}).Visible(ctx) {
+		t.Fatal("drafts should be hidden from readers")
+	}
+	if !(Golden{Status: StatusDraft}).Visible(WithEditor(ctx)) {
+		t.Fatal("drafts should be visible to editors")
+	}
+}
+
+func TestGolden_Transition_ScheduleNeedsFuturePublishAt(t *testing.T) {
+	at := time.Date(2026, 3, 6, 12, 0, 0, 0, time.UTC)
+	for _, publishAt := range []time.Time{{}, at, at.Add(-time.Minute)} {
+		doc := Golden{ID: "g", Status: StatusInReview, PublishAt: publishAt}
+		if _, err := doc.Transition(StatusScheduled, "", at); !errors.Is(err, ErrInvalidTransition) {
+			t.Fatalf("Transition() with publish_at %v err = %v, want ErrInvalidTransition", publishAt, err)
+		}
+	}
+}
+
+func TestGolden_Live(t *testing.T) {
+	now := time.Date(2026, 3, 6, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		doc  Golden
+		want bool
+	}{
+		{name: "published", doc: Golden{Status: StatusPublished}, want: true},
+		{name: "draft", doc: Golden{Status: StatusDraft, PublishAt: now.Add(-time.Hour)}},
+		{name: "scheduled-future", doc: Golden{Status: StatusScheduled, PublishAt: now.Add(time.Hour)}},
+		{name: "scheduled-due", doc: Golden{Status: StatusScheduled, PublishAt: now}, want: true},
+		{name: "scheduled-without-time", doc: Golden{Status: StatusScheduled}},
+		{name: "expired", doc: Golden{Status: StatusPublished, ExpireAt: now}},
+		{name: "expiring", doc: Golden{Status: StatusPublished, ExpireAt: now.Add(time.Second)}, want: true},
+		{name: "scheduled-expired", doc: Golden{Status: StatusScheduled, PublishAt: now.Add(-time.Hour), ExpireAt: now.Add(-time.Minute)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.doc.Live(now); got != tt.want {
+				t.Fatalf("Live() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGolden_DueTransition(t *testing.T) {
+	now := time.Date(2026, 3, 6, 12, 0, 0, 0, time.UTC)
+	publishAt, expireAt := now.Add(-time.Hour), now.Add(-time.Minute)
+	doc := Golden{Status: StatusScheduled, PublishAt: publishAt, ExpireAt: expireAt}
+
+	to, at, ok := doc.DueTransition(now)
+	if !ok || to != StatusPublished || !at.Equal(publishAt) {
+		t.Fatalf("DueTransition() = %s, %v, %v; want published at %v", to, at, ok, publishAt)
+	}
+	doc.Status = StatusPublished
+	to, at, ok = doc.DueTransition(now)
+	if !ok || to != StatusArchived || !at.Equal(expireAt) {
+		t.Fatalf("DueTransition() = %s, %v, %v; want archived at %v", to, at, ok, expireAt)
+	}
+	doc.Status = StatusArchived
+	if _, _, ok := doc.DueTransition(now); ok {
+		t.Fatal("DueTransition() on an archived golden should be nothing")
+	}
+	if _, _, ok := (Golden{Status: StatusScheduled, PublishAt: now.Add(time.Second)}).DueTransition(now); ok {
+		t.Fatal("DueTransition() before publish_at should be nothing")
+	}
+}
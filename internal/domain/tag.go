@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrInvalidTag  = errors.New("invalid tag")
+	ErrTagNotFound = errors.New("tag not found")
+	ErrTagExists   = errors.New("tag already exists")
+)
+
+// RegisteredTag is the canonical form of a tag in the tag registry. Goldens
+// written with any of its Synonyms, or with a spelling of Name or a synonym
+// that only differs in case, accents, spacing or punctuation, get Name
+// instead.
+type RegisteredTag struct {
+	Name      string
+	Synonyms  []string
+	CreatedAt time.Time
+}
+
+func (t RegisteredTag) Validate() error {
+	if t.Name == "" || t.Name != Slugify(t.Name) {
+		return fmt.Errorf("%w: name %q must be a lowercase slug", ErrInvalidTag, t.Name)
+	}
+	for _, synonym := range t.Synonyms {
+		if TagKey(synonym) == "" {
+			return fmt.Errorf("%w: synonym %q has no letters or digits", ErrInvalidTag, synonym)
+		}
+		if TagKey(synonym) == TagKey(t.Name) {
+			return fmt.Errorf("%w: synonym %q is a spelling of %s itself", ErrInvalidTag, synonym, t.Name)
+		}
+	}
+	return nil
+}
+
+// TagKey folds tag spellings like CategoryKey, so "ci-cd", "CI/CD" and
+// "cicd" share a key.
+func TagKey(tag string) string {
+	return CategoryKey(tag)
+}
+
+// TagIndex maps the keys of registered tags and their synonyms to the
+// canonical names.
+type TagIndex map[string]string
+
+// NewTagIndex indexes tags. A spelling of a registered name always maps to
+// that name, even if it is also listed as a synonym of another tag.
+func NewTagIndex(tags []RegisteredTag) TagIndex {
+	index := make(TagIndex)
+	for _, tag := range tags {
+		for _, synonym := range tag.Synonyms {
+			index[TagKey(synonym)] = tag.Name
+		}
+	}
+	for _, tag := range tags {
+		index[TagKey(tag.Name)] = tag.Name
+	}
+	return index
+}
+
+// Normalize returns tags in canonical form: registered tags and synonyms
+// become the registered name, the rest become slugs. Tags without letters or
+// digits are dropped and duplicates are kept once, in their first position.
+func (index TagIndex) Normalize(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		canonical := Slugify(tag)
+		if name, ok := index[TagKey(canonical)]; ok {
+			canonical = name
+		}
+		if canonical != "" && !seen[canonical] {
+			seen[canonical] = true
+			normalized = append(normalized, canonical)
+		}
+	}
+	return normalized
+}
+
+type TagStore interface {
+	// ListRegisteredTags returns the registry ordered by name.
+	ListRegisteredTags(ctx context.Context) ([]RegisteredTag, error)
+	// RegisterTag adds tag to the registry or replaces its synonyms.
+	RegisterTag(ctx context.Context, tag *RegisteredTag) error
+	// UnregisterTag removes a tag and its synonyms from the registry. Goldens
+	// keep the tag.
+	UnregisterTag(ctx context.Context, name string) error
+	// RenameTag replaces from with to in every golden and in the registry,
+	// keeping from as a synonym of to, and returns the number of goldens
+	// changed. to must not be in use yet; use MergeTags otherwise.
+	RenameTag(ctx context.Context, from, to string) (int, error)
+	// MergeTags replaces every tag in sources with target in every golden,
+	// registers target with sources as synonyms and returns the number of
+	// goldens changed.
+	MergeTags(ctx context.Context, sources []string, target string) (int, error)
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRegisteredTag_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		tag     RegisteredTag
+		wantErr bool
+	}{
+		{name: "plain", tag: RegisteredTag{Name: "kubernetes"}},
+		{name: "synonyms", tag: RegisteredTag{Name: "kubernetes", Synonyms: []string{"k8s", "Kube"}}},
+		{name: "missing-name", tag: RegisteredTag{}, wantErr: true},
+		{name: "name-not-slug", tag: RegisteredTag{Name: "CI/CD"}, wantErr: true},
+		{name: "empty-synonym", tag: RegisteredTag{Name: "ci-cd", Synonyms: []string{"//"}}, wantErr: true},
+		{name: "synonym-spells-name", tag: RegisteredTag{Name: "ci-cd", Synonyms: []string{"CICD"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.tag.Validate()
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidTag) {
+				t.Fatalf("expected ErrInvalidTag, got %v", err)
+			}
+		})
+	}
+}
+
+func TestTagIndex_Normalize(t *testing.T) {
+	index := NewTagIndex([]RegisteredTag{
+		{Name: "ci-cd", Synonyms: []string{"continuous delivery"}},
+		{Name: "kubernetes", Synonyms: []string{"k8s", "ci-cd"}},
+	})
+
+	got := index.Normalize([]string{"CI/CD", "K8s", "cicd", "Continuous Delivery", "Go Lang", "!!", "ci-cd", "kubernetes"})
+	want := []string{"ci-cd", "kubernetes", "go-lang"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Normalize() = %v, want %v", got, want)
+	}
+
+	if got := index.Normalize(nil); got != nil {
+		t.Fatalf("Normalize(nil) = %v, want nil", got)
+	}
+}
@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"slices"
+	"time"
+)
+
+var (
+	ErrInvalidWebhook  = errors.New("invalid webhook subscription")
+	ErrWebhookNotFound = errors.New("webhook subscription not found")
+)
+
+// WebhookSubscription receives the events matching its filters. Empty
+// filters match everything.
+type WebhookSubscription struct {
+	ID         string
+	URL        string
+	Secret     string
+	EventTypes []EventType
+	Categories []string
+	CreatedAt  time.Time
+}
+
+// Validate checks the subscription. Its URL must be https and must not name
+// a loopback, private or link-local address, so subscribers cannot make the
+// service call into its own network. Hosts in allowedHosts skip both checks.
+func (s WebhookSubscription) Validate(allowedHosts []string) error {
+	u, err := url.Parse(s.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return fmt.Errorf("%w: url must be an absolute http(s) URL", ErrInvalidWebhook)
+	}
+	if !slices.Contains(allowedHosts, u.Hostname()) {
+		if u.Scheme != "https" {
+			return fmt.Errorf("%w: url must use https", ErrInvalidWebhook)
+		}
+		if u.Hostname() == "localhost" {
+			return fmt.Errorf("%w: url must not point at localhost", ErrInvalidWebhook)
+		}
+		if addr, err := netip.ParseAddr(u.Hostname()); err == nil && !PublicAddr(addr) {
+			return fmt.Errorf("%w: url must not point at a private address", ErrInvalidWebhook)
+		}
+	}
+	for _, eventType := range s.EventTypes {
+		switch eventType {
+		case EventGoldenCreated, EventGoldenUpdated, EventGoldenDeleted, EventGoldenStatusChanged:
+		default:
+			return fmt.Errorf("%w: unknown event type %q", ErrInvalidWebhook, eventType)
+		}
+	}
+	return nil
+}
+
+// Matches reports whether an event of eventType about a golden in category
+// passes the subscription filters.
+func (s WebhookSubscription) Matches(eventType EventType, category string) bool {
+	if len(s.EventTypes) > 0 && !slices.Contains(s.EventTypes, eventType) {
+		return false
+	}
+	if len(s.Categories) > 0 && !slices.Contains(s.Categories, category) {
+		return false
+	}
+	return true
+}
+
+// PublicAddr reports whether addr may be the target of a webhook: anything
+// but loopback, private, link-local, unspecified and multicast addresses.
+func PublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsValid() && !addr.IsLoopback() && !addr.IsPrivate() && !addr.IsLinkLocalUnicast() &&
+		!addr.IsLinkLocalMulticast() && !addr.IsUnspecified() && !addr.IsMulticast()
+}
+
+// WebhookDelivery is one attempt to POST an event to a subscription.
+type WebhookDelivery struct {
+	ID             int64
+	SubscriptionID string
+	EventID        string
+	EventType      EventType
+	Attempt        int
+	StatusCode     int
+	Error          string
+	Duration       time.Duration
+	Succeeded      bool
+	CreatedAt      time.Time
+}
+
+type WebhookStore interface {
+	CreateSubscription(ctx context.Context, sub *WebhookSubscription) error
+	ListSubscriptions(ctx context.Context) ([]WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id string) error
+	RecordDelivery(ctx context.Context, delivery *WebhookDelivery) error
+	// ListDeliveries returns the latest deliveries first, optionally
+	// restricted to one subscription.
+	ListDeliveries(ctx context.Context, subscriptionID string, limit int) ([]WebhookDelivery, error)
+	// ListEventDeliveries returns every delivery of one event, oldest first.
+	ListEventDeliveries(ctx context.Context, eventID string) ([]WebhookDelivery, error)
+}
@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWebhookSubscription_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		sub     WebhookSubscription
+		allowed []string
+		wantErr bool
+	}{
+		{name: "https", sub: WebhookSubscription{URL: "https://hooks.example.com/x"}},
+		{name: "https-public-ip", sub: WebhookSubscription{URL: "https://203.0.113.7/x"}},
+		{name: "http", sub: WebhookSubscription{URL: "http://hooks.example.com/x"}, wantErr: true},
+		{name: "localhost", sub: WebhookSubscription{URL: "https://localhost:8080"}, wantErr: true},
+		{name: "loopback", sub: WebhookSubscription{URL: "https://127.0.0.1/x"}, wantErr: true},
+		{name: "private", sub: WebhookSubscription{URL: "https://10.0.0.8/x"}, wantErr: true},
+		{name: "link-local", sub: WebhookSubscription{URL: "https://169.254.169.254/latest"}, wantErr: true},
+		{name: "mapped-loopback", sub: WebhookSubscription{URL: "https://[::ffff:127.0.0.1]/x"}, wantErr: true},
+		{name: "allowed-http-with-filter", sub: WebhookSubscription{URL: "http://localhost:8080", EventTypes: []EventType{EventGoldenDeleted}}, allowed: []string{"localhost"}},
+		{name: "relative", sub: WebhookSubscription{URL: "/hooks"}, wantErr: true},
+		{name: "other-scheme", sub: WebhookSubscription{URL: "ftp://example.com"}, wantErr: true},
+		{name: "unknown-event", sub: WebhookSubscription{URL: "https://example.com", EventTypes: []EventType{"GoldenRenamed"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.sub.Validate(tt.allowed)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidWebhook) {
+				t.Fatalf("expected ErrInvalidWebhook, got %v", err)
+			}
+		})
+	}
+}
+
+func TestWebhookSubscription_Matches(t *testing.T) {
+	sub := WebhookSubscription{EventTypes: []EventType{EventGoldenCreated}, Categories: []string{"DevOps"}}
+
+	tests := []struct {
+		eventType EventType
+		category  string
+		want      bool
+	}{
+		{eventType: EventGoldenCreated, category: "DevOps", want: true},
+		{eventType: EventGoldenUpdated, category: "DevOps", want: false},
+		{eventType: EventGoldenCreated, category: "APIs", want: false},
+	}
+	for _, tt := range tests {
+		if got := sub.Matches(tt.eventType, tt.category); got != tt.want {
+			t.Errorf("Matches(%s, %s) = %v, want %v", tt.eventType, tt.category, got, tt.want)
+		}
+	}
+	if !(WebhookSubscription{}).Matches(EventGoldenDeleted, "") {
+		t.Error("expected empty filters to match everything")
+	}
+}
@@ -0,0 +1,103 @@
+// Package archive reads and writes tar.gz bundles of goldens, one markdown
+// file with front matter per golden.
+package archive
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"markitos-it-svc-goldens/internal/domain"
+	"markitos-it-svc-goldens/internal/infrastructure/markdown"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// maxEntrySize guards Read against decompression bombs.
+const maxEntrySize = 32 << 20
+
+func Write(w io.Writer, docs []domain.Golden) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	for _, doc := range docs {
+		data, err := markdown.Encode(doc)
+		if err != nil {
+			return err
+		}
+
+		modTime := doc.UpdatedAt
+		if modTime.IsZero() {
+			modTime = time.Now()
+		}
+		header := &tar.Header{
+			Name:    markdown.FileName(doc.ID),
+			Mode:    0o644,
+			Size:    int64(len(data)),
+			ModTime: modTime.UTC(),
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to write archive header for %s: %w", doc.ID, err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			return fmt.Errorf("failed to write archive entry for %s: %w", doc.ID, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to close tar writer: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to close gzip writer: %w", err)
+	}
+
+	return nil
+}
+
+// Read calls fn for every markdown file in the archive, in archive order.
+func Read(r io.Reader, fn func(domain.Golden) error) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("failed to open gzip stream: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg || !strings.EqualFold(path.Ext(header.Name), ".md") {
+			continue
+		}
+		if header.Size > maxEntrySize {
+			return fmt.Errorf("archive entry %s exceeds %d bytes", header.Name, maxEntrySize)
+		}
+
+		data, err := io.ReadAll(io.LimitReader(tr, maxEntrySize))
+		if err != nil {
+			return fmt.Errorf("failed to read archive entry %s: %w", header.Name, err)
+		}
+		doc, err := markdown.Decode(data)
+		if err != nil {
+			return fmt.Errorf("failed to decode archive entry %s: %w", header.Name, err)
+		}
+		if doc.ID == "" {
+			doc.ID = strings.TrimSuffix(path.Base(header.Name), path.Ext(header.Name))
+			if unescaped, err := url.PathUnescape(doc.ID); err == nil {
+				doc.ID = unescaped
+			}
+		}
+
+		if err := fn(doc); err != nil {
+			return err
+		}
+	}
+}
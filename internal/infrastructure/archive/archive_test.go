@@ -0,0 +1,114 @@
+package archive
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"errors"
+	"markitos-it-svc-goldens/internal/domain"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func helperRandomGoldens(t *testing.T, n int) []domain.Golden {
+	t.Helper()
+
+	docs := make([]domain.Golden, 0, n)
+	for i := 0; i < n; i++ {
+		prefix := domain.HelperRandomAlphaPrefix(t, 8)
+		docs = append(docs, domain.Golden{
+			ID:          prefix + "/golden-id",
+			Title:       prefix + "-title",
+			Description: prefix + "-description",
+			Category:    prefix + "-category",
+			Tags:        []string{prefix + "-go"},
+			UpdatedAt:   time.Date(2026, 3, 6, 12, 0, 0, 0, time.UTC),
+			ContentB64:  base64.StdEncoding.EncodeToString([]byte("# " + prefix)),
+			CoverImage:  "https://example.com/" + prefix + "/cover.png",
+		})
+	}
+	return docs
+}
+
+func TestWriteRead_RoundTrip(t *testing.T) {
+	docs := helperRandomGoldens(t, 3)
+
+	var buf bytes.Buffer
+	if err := Write(&buf, docs); err != nil {
+		t.Fatalf("Write() unexpected error: %v", err)
+	}
+
+	var got []domain.Golden
+	err := Read(&buf, func(doc domain.Golden) error {
+		got = append(got, doc)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Read() unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, docs) {
+		t.Fatalf("round trip mismatch:\nwant %+v\ngot  %+v", docs, got)
+	}
+}
+
+func TestRead_SkipsNonMarkdownAndFallsBackToFileName(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	entries := map[string]string{
+		"README.txt":        "ignored",
+		"no%2Fid-golden.md": "---\ntitle: \"From file name\"\n---\nbody",
+	}
+	for _, name := range []string{"README.txt", "no%2Fid-golden.md"} {
+		data := entries[name]
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(data))}); err != nil {
+			t.Fatalf("failed to write header: %v", err)
+		}
+		if _, err := tw.Write([]byte(data)); err != nil {
+			t.Fatalf("failed to write entry: %v", err)
+		}
+	}
+	tw.Close()
+	gz.Close()
+
+	var got []domain.Golden
+	if err := Read(&buf, func(doc domain.Golden) error {
+		got = append(got, doc)
+		return nil
+	}); err != nil {
+		t.Fatalf("Read() unexpected error: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("expected 1 golden, got %d", len(got))
+	}
+	if got[0].ID != "no/id-golden" {
+		t.Fatalf("expected ID from file name, got %q", got[0].ID)
+	}
+}
+
+func TestRead_Errors(t *testing.T) {
+	t.Run("not-gzip", func(t *testing.T) {
+		if err := Read(bytes.NewBufferString("plain"), func(domain.Golden) error { return nil }); err == nil {
+			t.Fatal("Read() expected error for non gzip input")
+		}
+	})
+
+	t.Run("callback-error-stops-reading", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := Write(&buf, helperRandomGoldens(t, 2)); err != nil {
+			t.Fatalf("Write() unexpected error: %v", err)
+		}
+
+		want := errors.New("stop")
+		calls := 0
+		err := Read(&buf, func(domain.Golden) error {
+			calls++
+			return want
+		})
+		if !errors.Is(err, want) || calls != 1 {
+			t.Fatalf("expected to stop after first callback with %v, got err=%v calls=%d", want, err, calls)
+		}
+	})
+}
@@ -0,0 +1,47 @@
+package events
+
+import (
+	"encoding/json"
+	"markitos-it-svc-goldens/internal/domain"
+	"strings"
+	"time"
+)
+
+const (
+	CloudEventsContentType = "application/cloudevents+json"
+	// TypePrefix namespaces domain event types in the CloudEvents type
+	// attribute, e.g. it.markitos.goldens.GoldenCreated.
+	TypePrefix = "it.markitos.goldens."
+)
+
+// CloudEvent is the CloudEvents 1.0 structured JSON representation of a
+// domain event.
+type CloudEvent struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            time.Time       `json:"time"`
+	DataContentType string          `json:"datacontenttype"`
+	Data            json.RawMessage `json:"data"`
+}
+
+func NewCloudEvent(event domain.Event, source string) CloudEvent {
+	return CloudEvent{
+		SpecVersion:     "1.0",
+		ID:              event.ID,
+		Source:          source,
+		Type:            TypePrefix + string(event.Type),
+		Subject:         event.AggregateID,
+		Time:            event.OccurredAt,
+		DataContentType: "application/json",
+		Data:            event.Payload,
+	}
+}
+
+// Destination expands a subject or topic template. {type} is replaced by the
+// event type, so "goldens.{type}" yields "goldens.GoldenCreated".
+func Destination(template string, eventType domain.EventType) string {
+	return strings.ReplaceAll(template, "{type}", string(eventType))
+}
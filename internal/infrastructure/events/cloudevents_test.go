@@ -0,0 +1,73 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"markitos-it-svc-goldens/internal/domain"
+	"testing"
+)
+
+func helperEvent(t *testing.T, eventType domain.EventType) domain.Event {
+	t.Helper()
+
+	prefix := domain.HelperRandomAlphaPrefix(t, 8)
+	event, err := domain.NewGoldenEvent(eventType, domain.Golden{ID: prefix, Title: prefix + "-title"})
+	if err != nil {
+		t.Fatalf("NewGoldenEvent() unexpected error: %v", err)
+	}
+	return event
+}
+
+func TestNewCloudEvent(t *testing.T) {
+	event := helperEvent(t, domain.EventGoldenUpdated)
+
+	got := NewCloudEvent(event, "svc")
+	if got.SpecVersion != "1.0" || got.ID != event.ID || got.Source != "svc" {
+		t.Errorf("unexpected envelope: %+v", got)
+	}
+	if got.Type != "it.markitos.goldens.GoldenUpdated" {
+		t.Errorf("Type: got %q", got.Type)
+	}
+	if got.Subject != event.AggregateID || !got.Time.Equal(event.OccurredAt) || string(got.Data) != string(event.Payload) {
+		t.Errorf("unexpected attributes: %+v", got)
+	}
+}
+
+func TestDestination(t *testing.T) {
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{template: "goldens.{type}", want: "goldens.GoldenDeleted"},
+		{template: "goldens.events", want: "goldens.events"},
+		{template: "{type}", want: "GoldenDeleted"},
+	}
+	for _, tt := range tests {
+		if got := Destination(tt.template, domain.EventGoldenDeleted); got != tt.want {
+			t.Errorf("Destination(%q): want %q, got %q", tt.template, tt.want, got)
+		}
+	}
+}
+
+type recordingPublisher struct {
+	err    error
+	events []domain.Event
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, event domain.Event) error {
+	p.events = append(p.events, event)
+	return p.err
+}
+
+func TestFanout_PublishesToAll(t *testing.T) {
+	want := errors.New("broker down")
+	ok, failing := &recordingPublisher{}, &recordingPublisher{err: want}
+
+	err := Fanout{failing, ok}.Publish(context.Background(), helperEvent(t, domain.EventGoldenCreated))
+	if !errors.Is(err, want) {
+		t.Fatalf("expected %v, got %v", want, err)
+	}
+	if len(ok.events) != 1 || len(failing.events) != 1 {
+		t.Fatal("expected every publisher to receive the event")
+	}
+}
@@ -0,0 +1,22 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"markitos-it-svc-goldens/internal/domain"
+)
+
+// Fanout publishes every event to all its publishers. A failure in any of
+// them fails the event so the outbox retries it; the others then see the
+// event again, which at-least-once consumers already tolerate.
+type Fanout []domain.EventPublisher
+
+func (f Fanout) Publish(ctx context.Context, event domain.Event) error {
+	var errs []error
+	for _, publisher := range f {
+		if err := publisher.Publish(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
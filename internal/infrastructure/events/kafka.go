@@ -0,0 +1,51 @@
+//go:build kafka
+
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"markitos-it-svc-goldens/internal/domain"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+type KafkaConfig struct {
+	// TopicTemplate is expanded with Destination for every event.
+	TopicTemplate string
+	Source        string
+}
+
+// KafkaPublisher produces CloudEvents keyed by golden id, so all events of a
+// golden land in the same partition. That does not make them ordered: the
+// outbox relay goes on with later events of a golden while a failed one
+// waits for its retry, so consumers compare the CloudEvent time.
+type KafkaPublisher struct {
+	client *kgo.Client
+	config KafkaConfig
+}
+
+func NewKafkaPublisher(client *kgo.Client, config KafkaConfig) *KafkaPublisher {
+	return &KafkaPublisher{client: client, config: config}
+}
+
+func (p *KafkaPublisher) Publish(ctx context.Context, event domain.Event) error {
+	data, err := json.Marshal(NewCloudEvent(event, p.config.Source))
+	if err != nil {
+		return fmt.Errorf("failed to encode cloud event: %w", err)
+	}
+
+	record := &kgo.Record{
+		Topic: Destination(p.config.TopicTemplate, event.Type),
+		Key:   []byte(event.AggregateID),
+		Value: data,
+		Headers: []kgo.RecordHeader{
+			{Key: "content-type", Value: []byte(CloudEventsContentType)},
+		},
+	}
+	if err := p.client.ProduceSync(ctx, record).FirstErr(); err != nil {
+		return fmt.Errorf("failed to produce %s to %s: %w", event.ID, record.Topic, err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+//go:build kafka
+
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"markitos-it-svc-goldens/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// helperKafkaCluster starts an in-process Kafka cluster with the topics
+// already created.
+func helperKafkaCluster(t *testing.T, topics ...string) []string {
+	t.Helper()
+
+	cluster, err := kfake.NewCluster(kfake.NumBrokers(1), kfake.SeedTopics(3, topics...))
+	if err != nil {
+		t.Fatalf("failed to start kafka cluster: %v", err)
+	}
+	t.Cleanup(cluster.Close)
+	return cluster.ListenAddrs()
+}
+
+func helperKafkaClient(t *testing.T, opts ...kgo.Opt) *kgo.Client {
+	t.Helper()
+
+	client, err := kgo.NewClient(opts...)
+	if err != nil {
+		t.Fatalf("failed to create kafka client: %v", err)
+	}
+	t.Cleanup(client.Close)
+	return client
+}
+
+func TestKafkaPublisher_Publish(t *testing.T) {
+	brokers := helperKafkaCluster(t, "goldens.GoldenCreated")
+	publisher := NewKafkaPublisher(helperKafkaClient(t, kgo.SeedBrokers(brokers...)), KafkaConfig{TopicTemplate: "goldens.{type}", Source: "test"})
+	event := helperEvent(t, domain.EventGoldenCreated)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := publisher.Publish(ctx, event); err != nil {
+		t.Fatalf("Publish() unexpected error: %v", err)
+	}
+
+	consumer := helperKafkaClient(t, kgo.SeedBrokers(brokers...), kgo.ConsumeTopics("goldens.GoldenCreated"))
+	fetches := consumer.PollFetches(ctx)
+	if errs := fetches.Errors(); len(errs) > 0 {
+		t.Fatalf("PollFetches() errors: %v", errs)
+	}
+	records := fetches.Records()
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(records))
+	}
+	record := records[0]
+	if string(record.Key) != event.AggregateID {
+		t.Errorf("key: want %q, got %q", event.AggregateID, record.Key)
+	}
+	if len(record.Headers) != 1 || record.Headers[0].Key != "content-type" || string(record.Headers[0].Value) != CloudEventsContentType {
+		t.Errorf("unexpected headers %+v", record.Headers)
+	}
+
+	var ce CloudEvent
+	if err := json.Unmarshal(record.Value, &ce); err != nil {
+		t.Fatalf("record is not a cloud event: %v", err)
+	}
+	if ce.ID != event.ID || ce.Type != TypePrefix+string(domain.EventGoldenCreated) || ce.Subject != event.AggregateID {
+		t.Errorf("unexpected cloud event: %+v", ce)
+	}
+}
+
+func TestKafkaPublisher_Publish_ContextDone(t *testing.T) {
+	brokers := helperKafkaCluster(t, "goldens.GoldenDeleted")
+	publisher := NewKafkaPublisher(helperKafkaClient(t, kgo.SeedBrokers(brokers...)), KafkaConfig{TopicTemplate: "goldens.{type}", Source: "test"})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := publisher.Publish(ctx, helperEvent(t, domain.EventGoldenDeleted)); err == nil {
+		t.Fatal("expected error when the context is done")
+	}
+}
@@ -0,0 +1,16 @@
+package events
+
+import (
+	"context"
+	"log"
+	"markitos-it-svc-goldens/internal/domain"
+)
+
+// LogPublisher writes every event to the standard logger. It is the default
+// publisher when no broker is configured.
+type LogPublisher struct{}
+
+func (LogPublisher) Publish(ctx context.Context, event domain.Event) error {
+	log.Printf("📣 %s %s (event %s): %s", event.Type, event.AggregateID, event.ID, event.Payload)
+	return nil
+}
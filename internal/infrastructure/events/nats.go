@@ -0,0 +1,68 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"markitos-it-svc-goldens/internal/domain"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+type NATSConfig struct {
+	// SubjectTemplate is expanded with Destination for every event.
+	SubjectTemplate string
+	Source          string
+	// Stream is the JetStream stream created by EnsureStream.
+	Stream string
+}
+
+// NATSPublisher publishes CloudEvents to JetStream and waits for the stream
+// acknowledgement. The event id is sent as Nats-Msg-Id, so redeliveries from
+// the outbox inside the stream's duplicate window are dropped by the server.
+type NATSPublisher struct {
+	js     jetstream.JetStream
+	config NATSConfig
+}
+
+func NewNATSPublisher(nc *nats.Conn, config NATSConfig) (*NATSPublisher, error) {
+	js, err := jetstream.New(nc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create jetstream context: %w", err)
+	}
+	return &NATSPublisher{js: js, config: config}, nil
+}
+
+// EnsureStream creates or updates the configured stream so that it captures
+// every subject the publisher can produce.
+func (p *NATSPublisher) EnsureStream(ctx context.Context) error {
+	subjects := strings.ReplaceAll(p.config.SubjectTemplate, "{type}", "*")
+	_, err := p.js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:       p.config.Stream,
+		Subjects:   []string{subjects},
+		Duplicates: 10 * time.Minute,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to ensure stream %s: %w", p.config.Stream, err)
+	}
+	return nil
+}
+
+func (p *NATSPublisher) Publish(ctx context.Context, event domain.Event) error {
+	data, err := json.Marshal(NewCloudEvent(event, p.config.Source))
+	if err != nil {
+		return fmt.Errorf("failed to encode cloud event: %w", err)
+	}
+
+	msg := nats.NewMsg(Destination(p.config.SubjectTemplate, event.Type))
+	msg.Data = data
+	msg.Header.Set("Content-Type", CloudEventsContentType)
+
+	if _, err := p.js.PublishMsg(ctx, msg, jetstream.WithMsgID(event.ID)); err != nil {
+		return fmt.Errorf("failed to publish %s to %s: %w", event.ID, msg.Subject, err)
+	}
+	return nil
+}
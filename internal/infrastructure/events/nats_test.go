@@ -0,0 +1,114 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"markitos-it-svc-goldens/internal/domain"
+	"testing"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/test"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// helperJetStream starts an embedded NATS server with JetStream enabled and
+// returns a connection to it.
+func helperJetStream(t *testing.T) *nats.Conn {
+	t.Helper()
+
+	opts := natsserver.DefaultTestOptions
+	opts.Port = -1
+	opts.JetStream = true
+	opts.StoreDir = t.TempDir()
+	server := natsserver.RunServer(&opts)
+	t.Cleanup(server.Shutdown)
+
+	nc, err := nats.Connect(server.ClientURL(), nats.Timeout(2*time.Second))
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(nc.Close)
+	return nc
+}
+
+func helperNATSPublisher(t *testing.T, nc *nats.Conn, template string) *NATSPublisher {
+	t.Helper()
+
+	publisher, err := NewNATSPublisher(nc, NATSConfig{SubjectTemplate: template, Source: "test", Stream: "GOLDENS"})
+	if err != nil {
+		t.Fatalf("NewNATSPublisher() unexpected error: %v", err)
+	}
+	return publisher
+}
+
+func TestNATSPublisher_Publish(t *testing.T) {
+	nc := helperJetStream(t)
+	publisher := helperNATSPublisher(t, nc, "goldens.{type}")
+	event := helperEvent(t, domain.EventGoldenCreated)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := publisher.EnsureStream(ctx); err != nil {
+		t.Fatalf("EnsureStream() unexpected error: %v", err)
+	}
+	if err := publisher.Publish(ctx, event); err != nil {
+		t.Fatalf("Publish() unexpected error: %v", err)
+	}
+	// A redelivery from the outbox is dropped as a duplicate.
+	if err := publisher.Publish(ctx, event); err != nil {
+		t.Fatalf("Publish() retry unexpected error: %v", err)
+	}
+
+	js, err := jetstream.New(nc)
+	if err != nil {
+		t.Fatalf("failed to create jetstream context: %v", err)
+	}
+	stream, err := js.Stream(ctx, "GOLDENS")
+	if err != nil {
+		t.Fatalf("failed to look up stream: %v", err)
+	}
+	info, err := stream.Info(ctx)
+	if err != nil {
+		t.Fatalf("failed to read stream info: %v", err)
+	}
+	if info.State.Msgs != 1 {
+		t.Fatalf("expected 1 stored message, got %d", info.State.Msgs)
+	}
+
+	msg, err := stream.GetMsg(ctx, 1)
+	if err != nil {
+		t.Fatalf("failed to read stored message: %v", err)
+	}
+	if msg.Subject != "goldens.GoldenCreated" {
+		t.Errorf("subject: got %q", msg.Subject)
+	}
+	if got := msg.Header.Get("Nats-Msg-Id"); got != event.ID {
+		t.Errorf("Nats-Msg-Id: want %q, got %q", event.ID, got)
+	}
+	if got := msg.Header.Get("Content-Type"); got != CloudEventsContentType {
+		t.Errorf("Content-Type: got %q", got)
+	}
+
+	var ce CloudEvent
+	if err := json.Unmarshal(msg.Data, &ce); err != nil {
+		t.Fatalf("message is not a cloud event: %v", err)
+	}
+	if ce.ID != event.ID || ce.Type != TypePrefix+string(domain.EventGoldenCreated) || ce.Subject != event.AggregateID {
+		t.Errorf("unexpected cloud event: %+v", ce)
+	}
+}
+
+func TestNATSPublisher_Publish_NoStream(t *testing.T) {
+	nc := helperJetStream(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := helperNATSPublisher(t, nc, "goldens.{type}").EnsureStream(ctx); err != nil {
+		t.Fatalf("EnsureStream() unexpected error: %v", err)
+	}
+	publisher := helperNATSPublisher(t, nc, "other.{type}")
+	if err := publisher.Publish(ctx, helperEvent(t, domain.EventGoldenDeleted)); err == nil {
+		t.Fatal("expected error when no stream captures the subject")
+	}
+}
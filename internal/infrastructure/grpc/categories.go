@@ -0,0 +1,143 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"markitos-it-svc-goldens/internal/domain"
+	pb "markitos-it-svc-goldens/proto/goldens/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var errCategoriesDisabled = status.Error(codes.Unimplemented, "the category tree requires the postgres storage backend")
+
+func (s *GoldenServer) ListCategoryTree(ctx context.Context, req *pb.ListCategoryTreeRequest) (*pb.ListCategoryTreeResponse, error) {
+	log.Println("ListCategoryTree called")
+	if s.categories == nil {
+		return nil, errCategoriesDisabled
+	}
+
+	categories, err := s.categories.ListCategories(ctx)
+	if err != nil {
+		log.Printf("Error listing category tree: %v", err)
+		return nil, categoryStatus(err, "failed to list categories")
+	}
+
+	resp := &pb.ListCategoryTreeResponse{}
+	for _, category := range categories {
+		resp.Categories = append(resp.Categories, categoryToProto(&category))
+	}
+	return resp, nil
+}
+
+func (s *GoldenServer) GetCategory(ctx context.Context, req *pb.GetCategoryRequest) (*pb.GetCategoryResponse, error) {
+	log.Printf("GetCategory called with id: %s", req.Id)
+	if s.categories == nil {
+		return nil, errCategoriesDisabled
+	}
+
+	category, err := s.categories.GetCategory(ctx, req.Id)
+	if err != nil {
+		log.Printf("Error getting category %s: %v", req.Id, err)
+		return nil, categoryStatus(err, "failed to get category")
+	}
+
+	return &pb.GetCategoryResponse{
+		Category: categoryToProto(category),
+	}, nil
+}
+
+func (s *GoldenServer) CreateCategory(ctx context.Context, req *pb.CreateCategoryRequest) (*pb.CreateCategoryResponse, error) {
+	if s.categories == nil {
+		return nil, errCategoriesDisabled
+	}
+	if req.Category == nil {
+		return nil, status.Error(codes.InvalidArgument, "category is required")
+	}
+	log.Printf("CreateCategory called with id: %q", req.Category.Id)
+
+	category := categoryFromProto(req.Category)
+	if err := s.categories.CreateCategory(ctx, category); err != nil {
+		log.Printf("Error creating category: %v", err)
+		return nil, categoryStatus(err, "failed to create category")
+	}
+
+	return &pb.CreateCategoryResponse{
+		Category: categoryToProto(category),
+	}, nil
+}
+
+func (s *GoldenServer) UpdateCategory(ctx context.Context, req *pb.UpdateCategoryRequest) (*pb.UpdateCategoryResponse, error) {
+	if s.categories == nil {
+		return nil, errCategoriesDisabled
+	}
+	if req.Category == nil {
+		return nil, status.Error(codes.InvalidArgument, "category is required")
+	}
+	log.Printf("UpdateCategory called with id: %s", req.Category.Id)
+
+	category := categoryFromProto(req.Category)
+	if err := s.categories.UpdateCategory(ctx, category); err != nil {
+		log.Printf("Error updating category %s: %v", req.Category.Id, err)
+		return nil, categoryStatus(err, "failed to update category")
+	}
+
+	return &pb.UpdateCategoryResponse{
+		Category: categoryToProto(category),
+	}, nil
+}
+
+func (s *GoldenServer) DeleteCategory(ctx context.Context, req *pb.DeleteCategoryRequest) (*pb.DeleteCategoryResponse, error) {
+	log.Printf("DeleteCategory called with id: %s", req.Id)
+	if s.categories == nil {
+		return nil, errCategoriesDisabled
+	}
+
+	if err := s.categories.DeleteCategory(ctx, req.Id); err != nil {
+		log.Printf("Error deleting category %s: %v", req.Id, err)
+		return nil, categoryStatus(err, "failed to delete category")
+	}
+
+	return &pb.DeleteCategoryResponse{}, nil
+}
+
+func categoryStatus(err error, msg string) error {
+	switch {
+	case errors.Is(err, domain.ErrInvalidCategory):
+		return status.Errorf(codes.InvalidArgument, "%s: %v", msg, err)
+	case errors.Is(err, domain.ErrCategoryNotFound):
+		return status.Errorf(codes.NotFound, "%s: %v", msg, err)
+	case errors.Is(err, domain.ErrCategoryExists):
+		return status.Errorf(codes.AlreadyExists, "%s: %v", msg, err)
+	case errors.Is(err, domain.ErrCategoryInUse):
+		return status.Errorf(codes.FailedPrecondition, "%s: %v", msg, err)
+	default:
+		return errorStatus(err, msg)
+	}
+}
+
+func categoryFromProto(c *pb.Category) *domain.Category {
+	return &domain.Category{
+		ID:          c.Id,
+		ParentID:    c.ParentId,
+		DisplayName: c.DisplayName,
+		Description: c.Description,
+		Position:    int(c.Position),
+	}
+}
+
+func categoryToProto(c *domain.Category) *pb.Category {
+	return &pb.Category{
+		Id:          c.ID,
+		ParentId:    c.ParentID,
+		DisplayName: c.DisplayName,
+		Description: c.Description,
+		Position:    int32(c.Position),
+		CreatedAt:   timestamppb.New(c.CreatedAt),
+		UpdatedAt:   timestamppb.New(c.UpdatedAt),
+	}
+}
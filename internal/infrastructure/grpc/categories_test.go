@@ -0,0 +1,170 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"markitos-it-svc-goldens/internal/application/services"
+	"markitos-it-svc-goldens/internal/domain"
+	pb "markitos-it-svc-goldens/proto/goldens/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type stubCategoryStore struct {
+	categories []domain.Category
+}
+
+func (s *stubCategoryStore) ListCategories(ctx context.Context) ([]domain.Category, error) {
+	return s.categories, nil
+}
+
+func (s *stubCategoryStore) GetCategory(ctx context.Context, id string) (*domain.Category, error) {
+	for _, c := range s.categories {
+		if c.ID == id {
+			return &c, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", domain.ErrCategoryNotFound, id)
+}
+
+func (s *stubCategoryStore) CreateCategory(ctx context.Context, category *domain.Category) error {
+	if _, err := s.GetCategory(ctx, category.ID); err == nil {
+		return fmt.Errorf("%w: %s", domain.ErrCategoryExists, category.ID)
+	}
+	s.categories = append(s.categories, *category)
+	return nil
+}
+
+func (s *stubCategoryStore) UpdateCategory(ctx context.Context, category *domain.Category) error {
+	return nil
+}
+
+func (s *stubCategoryStore) DeleteCategory(ctx context.Context, id string) error {
+	return fmt.Errorf("%w: %s", domain.ErrCategoryInUse, id)
+}
+
+func (s *stubCategoryStore) ResolveCategory(ctx context.Context, value string) (string, error) {
+	for _, c := range s.categories {
+		if domain.CategoryKey(c.ID) == domain.CategoryKey(value) {
+			return c.ID, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", domain.ErrCategoryNotFound, value)
+}
+
+func (s *stubCategoryStore) CategorySubtree(ctx context.Context, id string) ([]string, error) {
+	ids := []string{id}
+	for _, c := range s.categories {
+		if c.ParentID == id {
+			ids = append(ids, c.ID)
+		}
+	}
+	return ids, nil
+}
+
+func helperCategoryServer(repo *stubRepo) *GoldenServer {
+	store := &stubCategoryStore{categories: []domain.Category{
+		{ID: "ci-cd", ParentID: "devops", DisplayName: "CI/CD"},
+		{ID: "devops", DisplayName: "DevOps"},
+	}}
+	docService := services.NewGoldenService(repo, services.WithCategories(store))
+	return NewGoldenServer(docService, WithCategories(services.NewCategoryService(store)))
+}
+
+func TestGoldenServer_CreateCategory_ListsTree(t *testing.T) {
+	s := helperCategoryServer(&stubRepo{})
+	ctx := helperEditorContext()
+
+	created, err := s.CreateCategory(ctx, &pb.CreateCategoryRequest{
+		Category: &pb.Category{ParentId: "devops", DisplayName: "GitOps", Position: 2},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.Category.Id != "gitops" || created.Category.CreatedAt == nil {
+		t.Fatalf("expected derived id and creation time, got %+v", created.Category)
+	}
+
+	listed, err := s.ListCategoryTree(ctx, &pb.ListCategoryTreeRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var ids []string
+	for _, c := range listed.Categories {
+		ids = append(ids, c.Id)
+	}
+	if fmt.Sprint(ids) != "[devops ci-cd gitops]" {
+		t.Fatalf("expected parents before children, got %v", ids)
+	}
+}
+
+func TestGoldenServer_GetAllGoldens_FiltersSubtree(t *testing.T) {
+	repo := &stubRepo{docs: []domain.Golden{
+		{ID: "pipelines", Category: "ci-cd"},
+		{ID: "keptn", Category: "devops"},
+		{ID: "youtube", Category: "apis"},
+	}}
+	s := helperCategoryServer(repo)
+
+	resp, err := s.GetAllGoldens(context.Background(), &pb.GetAllGoldensRequest{Category: "DevOps"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.Goldens) != 2 || resp.Goldens[0].Id != "pipelines" || resp.Goldens[1].Id != "keptn" {
+		t.Fatalf("expected the goldens of devops and ci-cd, got %+v", resp.Goldens)
+	}
+}
+
+func TestGoldenServer_CategoryErrorCodes(t *testing.T) {
+	ctx := helperEditorContext()
+	disabled := NewGoldenServer(services.NewGoldenService(&stubRepo{}))
+
+	tests := []struct {
+		name string
+		call func() error
+		want codes.Code
+	}{
+		{name: "invalid-id", want: codes.InvalidArgument, call: func() error {
+			_, err := helperCategoryServer(&stubRepo{}).CreateCategory(ctx, &pb.CreateCategoryRequest{Category: &pb.Category{Id: "Dev Ops", DisplayName: "Dev Ops"}})
+			return err
+		}},
+		{name: "missing-category", want: codes.InvalidArgument, call: func() error {
+			_, err := helperCategoryServer(&stubRepo{}).UpdateCategory(ctx, &pb.UpdateCategoryRequest{})
+			return err
+		}},
+		{name: "duplicate", want: codes.AlreadyExists, call: func() error {
+			_, err := helperCategoryServer(&stubRepo{}).CreateCategory(ctx, &pb.CreateCategoryRequest{Category: &pb.Category{DisplayName: "DevOps"}})
+			return err
+		}},
+		{name: "get-missing", want: codes.NotFound, call: func() error {
+			_, err := helperCategoryServer(&stubRepo{}).GetCategory(ctx, &pb.GetCategoryRequest{Id: "missing"})
+			return err
+		}},
+		{name: "delete-in-use", want: codes.FailedPrecondition, call: func() error {
+			_, err := helperCategoryServer(&stubRepo{}).DeleteCategory(ctx, &pb.DeleteCategoryRequest{Id: "devops"})
+			return err
+		}},
+		{name: "golden-unknown-category", want: codes.InvalidArgument, call: func() error {
+			_, err := helperCategoryServer(&stubRepo{}).CreateGolden(ctx, &pb.CreateGoldenRequest{Golden: &pb.Golden{Title: "Soup", Category: "Cooking"}})
+			return err
+		}},
+		{name: "list-unknown-category", want: codes.InvalidArgument, call: func() error {
+			_, err := helperCategoryServer(&stubRepo{}).GetAllGoldens(ctx, &pb.GetAllGoldensRequest{Category: "Cooking"})
+			return err
+		}},
+		{name: "disabled", want: codes.Unimplemented, call: func() error {
+			_, err := disabled.ListCategoryTree(ctx, &pb.ListCategoryTreeRequest{})
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := status.Code(tt.call()); got != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
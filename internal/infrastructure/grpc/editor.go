@@ -0,0 +1,79 @@
+package grpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+
+	"markitos-it-svc-goldens/internal/domain"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// EditorAuth grants editor rights (see domain.WithEditor) to the calls that
+// send one of its tokens as "authorization: Bearer <token>" metadata, naming
+// them after the token with domain.WithPrincipal. Other calls are served as
+// plain readers.
+type EditorAuth struct {
+	tokens [][]byte
+	open   bool
+}
+
+// NewEditorAuth accepts tokens; with none nobody is an editor.
+func NewEditorAuth(tokens []string) *EditorAuth {
+	a := &EditorAuth{}
+	for _, token := range tokens {
+		if token = strings.TrimSpace(token); token != "" {
+			a.tokens = append(a.tokens, []byte(token))
+		}
+	}
+	return a
+}
+
+// NewOpenEditorAuth makes every caller an editor, which exposes drafts and
+// writes to everyone. It is meant for local development only.
+func NewOpenEditorAuth() *EditorAuth {
+	return &EditorAuth{open: true}
+}
+
+// Open reports whether every caller is an editor.
+func (a *EditorAuth) Open() bool {
+	return a.open
+}
+
+func (a *EditorAuth) Unary(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	return handler(a.authorize(ctx), req)
+}
+
+func (a *EditorAuth) Stream(srv any, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &trackedStream{ServerStream: stream, ctx: a.authorize(stream.Context())})
+}
+
+func (a *EditorAuth) authorize(ctx context.Context) context.Context {
+	if a.Open() {
+		return domain.WithEditor(ctx)
+	}
+
+	for _, value := range metadata.ValueFromIncomingContext(ctx, "authorization") {
+		scheme, token, ok := strings.Cut(value, " ")
+		if !ok || !strings.EqualFold(scheme, "bearer") {
+			continue
+		}
+		for _, want := range a.tokens {
+			if subtle.ConstantTimeCompare([]byte(strings.TrimSpace(token)), want) == 1 {
+				return domain.WithPrincipal(domain.WithEditor(ctx), tokenPrincipal(want))
+			}
+		}
+	}
+	return ctx
+}
+
+// tokenPrincipal identifies the holder of token by its SHA-256, so that the
+// token itself never ends up in stored keys or logs.
+func tokenPrincipal(token []byte) string {
+	sum := sha256.Sum256(token)
+	return "token:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,39 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"markitos-it-svc-goldens/internal/domain"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorStatus converts a service error into a gRPC status, so that clients
+// can tell missing goldens and retryable outages from server bugs.
+func errorStatus(err error, msg string) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		code = codes.NotFound
+	case errors.Is(err, domain.ErrAlreadyExists):
+		code = codes.AlreadyExists
+	case errors.Is(err, domain.ErrUnknownCategory), errors.Is(err, domain.ErrInvalidStatus),
+		errors.Is(err, domain.ErrInvalidSchedule):
+		code = codes.InvalidArgument
+	case errors.Is(err, domain.ErrInvalidTransition), errors.Is(err, domain.ErrReadOnly):
+		code = codes.FailedPrecondition
+	case errors.Is(err, domain.ErrEditorRequired):
+		code = codes.PermissionDenied
+	// The caller's own cancellation or deadline wins over an outage it
+	// happened to hit, so that clients do not retry it.
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	case errors.Is(err, domain.ErrUnavailable):
+		code = codes.Unavailable
+	}
+	return status.Errorf(code, "%s: %v", msg, err)
+}
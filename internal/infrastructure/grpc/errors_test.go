@@ -0,0 +1,37 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"markitos-it-svc-goldens/internal/domain"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestErrorStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want codes.Code
+	}{
+		{name: "not-found", err: fmt.Errorf("%w: id", domain.ErrNotFound), want: codes.NotFound},
+		{name: "already-exists", err: fmt.Errorf("%w: id", domain.ErrAlreadyExists), want: codes.AlreadyExists},
+		{name: "unavailable", err: fmt.Errorf("failed to query goldens: %w: %w", domain.ErrUnavailable, errors.New("EOF")), want: codes.Unavailable},
+		{name: "canceled", err: context.Canceled, want: codes.Canceled},
+		{name: "deadline", err: fmt.Errorf("query: %w", context.DeadlineExceeded), want: codes.DeadlineExceeded},
+		{name: "deadline-during-outage", err: fmt.Errorf("failed to query goldens: %w: %w", domain.ErrUnavailable, context.DeadlineExceeded), want: codes.DeadlineExceeded},
+		{name: "read-only", err: fmt.Errorf("%w: filesystem repository", domain.ErrReadOnly), want: codes.FailedPrecondition},
+		{name: "other", err: errors.New("syntax error"), want: codes.Internal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := status.Code(errorStatus(tt.err, "failed")); got != tt.want {
+				t.Fatalf("errorStatus() code = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package grpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"markitos-it-svc-goldens/internal/domain"
+	legacypb "markitos-it-svc-goldens/proto"
+	pb "markitos-it-svc-goldens/proto/goldens/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+const (
+	// IdempotencyKeyHeader is the metadata key clients set on write RPCs.
+	IdempotencyKeyHeader = "idempotency-key"
+	// IdempotentReplayHeader is sent back when the response is a replay.
+	IdempotentReplayHeader = "idempotent-replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+// idempotentMethods are the unary write RPCs that honour IdempotencyKeyHeader.
+var idempotentMethods = map[string]bool{
+	pb.GoldenService_CreateGolden_FullMethodName:              true,
+	pb.GoldenService_UpdateGolden_FullMethodName:              true,
+	pb.GoldenService_DeleteGolden_FullMethodName:              true,
+	pb.GoldenService_TransitionGolden_FullMethodName:          true,
+	pb.GoldenService_CreateCategory_FullMethodName:            true,
+	pb.GoldenService_UpdateCategory_FullMethodName:            true,
+	pb.GoldenService_DeleteCategory_FullMethodName:            true,
+	pb.GoldenService_RegisterTag_FullMethodName:               true,
+	pb.GoldenService_UnregisterTag_FullMethodName:             true,
+	pb.GoldenService_RenameTag_FullMethodName:                 true,
+	pb.GoldenService_MergeTags_FullMethodName:                 true,
+	pb.GoldenService_CreateWebhookSubscription_FullMethodName: true,
+	pb.GoldenService_DeleteWebhookSubscription_FullMethodName: true,
+
+	legacypb.GoldenService_CreateGolden_FullMethodName:              true,
+	legacypb.GoldenService_UpdateGolden_FullMethodName:              true,
+	legacypb.GoldenService_DeleteGolden_FullMethodName:              true,
+	legacypb.GoldenService_CreateWebhookSubscription_FullMethodName: true,
+	legacypb.GoldenService_DeleteWebhookSubscription_FullMethodName: true,
+}
+
+type IdempotencyConfig struct {
+	// TTL is how long a response is replayed for its key.
+	TTL time.Duration
+	// Lease bounds how long a request may hold its key before a retry can
+	// take over, in case the replica running it dies.
+	Lease time.Duration
+}
+
+func DefaultIdempotencyConfig() IdempotencyConfig {
+	return IdempotencyConfig{TTL: 24 * time.Hour, Lease: time.Minute}
+}
+
+// UnaryIdempotency replays the stored response of a write retried with the
+// same idempotency key. Reusing a key for a different request fails with
+// FailedPrecondition, and a retry racing the original request with Aborted.
+// Failed requests are not remembered, so they can be retried with their key.
+//
+// Keys are scoped to the caller (see domain.Principal): the same key sent by
+// another editor is a different request, and readers, who cannot write, are
+// passed straight to the handler. It must run after EditorAuth.
+func UnaryIdempotency(store domain.IdempotencyStore, config IdempotencyConfig) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !idempotentMethods[info.FullMethod] || !domain.IsEditor(ctx) {
+			return handler(ctx, req)
+		}
+		keys := metadata.ValueFromIncomingContext(ctx, IdempotencyKeyHeader)
+		if len(keys) == 0 || keys[0] == "" {
+			return handler(ctx, req)
+		}
+		if len(keys[0]) > maxIdempotencyKeyLength {
+			return nil, status.Errorf(codes.InvalidArgument, "%s must be at most %d characters", IdempotencyKeyHeader, maxIdempotencyKeyLength)
+		}
+		key := scopedIdempotencyKey(ctx, keys[0])
+
+		hash, err := requestHash(req)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to hash request: %v", err)
+		}
+
+		record, err := store.Reserve(ctx, key, info.FullMethod, hash, config.Lease)
+		if err != nil {
+			return nil, idempotencyStatus(err, "failed to reserve idempotency key")
+		}
+		if record != nil {
+			return replay(ctx, record, hash, keys[0])
+		}
+
+		// The outcome must be recorded even if the client gave up meanwhile.
+		storeCtx := context.WithoutCancel(ctx)
+		resp, err := handler(ctx, req)
+		if err != nil {
+			if releaseErr := store.Release(storeCtx, key, info.FullMethod); releaseErr != nil {
+				log.Printf("⚠️  Failed to release idempotency key %s: %v", key, releaseErr)
+			}
+			return nil, err
+		}
+
+		if stored, err := marshalResponse(resp); err != nil {
+			log.Printf("⚠️  Failed to encode idempotent response for key %s: %v", key, err)
+		} else if err := store.Complete(storeCtx, key, info.FullMethod, stored, config.TTL); err != nil {
+			log.Printf("⚠️  Failed to store idempotent response for key %s: %v", key, err)
+		}
+		return resp, nil
+	}
+}
+
+// scopedIdempotencyKey is the key stored for a client key, prefixed with the
+// principal of the caller.
+func scopedIdempotencyKey(ctx context.Context, key string) string {
+	return domain.Principal(ctx) + "/" + key
+}
+
+// replay answers with the response stored in record, naming the key the
+// client sent in errors.
+func replay(ctx context.Context, record *domain.IdempotencyRecord, hash, key string) (any, error) {
+	if record.RequestHash != hash {
+		return nil, idempotencyStatus(domain.ErrIdempotencyMismatch, "idempotency key "+key)
+	}
+	if record.Response == nil {
+		return nil, idempotencyStatus(domain.ErrIdempotencyInProgress, "idempotency key "+key)
+	}
+
+	var stored anypb.Any
+	if err := proto.Unmarshal(record.Response, &stored); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to decode stored response: %v", err)
+	}
+	resp, err := stored.UnmarshalNew()
+	if errors.Is(err, protoregistry.NotFound) {
+		// Responses stored before the move to goldens.v1 name the unversioned
+		// messages, which have the same fields.
+		stored.TypeUrl = strings.Replace(stored.TypeUrl, "/goldens.", "/goldens.v1.", 1)
+		resp, err = stored.UnmarshalNew()
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to decode stored response: %v", err)
+	}
+
+	grpc.SetHeader(ctx, metadata.Pairs(IdempotentReplayHeader, "true"))
+	return resp, nil
+}
+
+// requestHash fingerprints a request so that a reused key can be told apart
+// from a genuine retry.
+func requestHash(req any) (string, error) {
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return "", errors.New("request is not a protobuf message")
+	}
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// marshalResponse keeps the message type next to the payload, so the replay
+// does not depend on the method.
+func marshalResponse(resp any) ([]byte, error) {
+	msg, ok := resp.(proto.Message)
+	if !ok {
+		return nil, errors.New("response is not a protobuf message")
+	}
+	wrapped, err := anypb.New(msg)
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(wrapped)
+}
+
+func idempotencyStatus(err error, msg string) error {
+	switch {
+	case errors.Is(err, domain.ErrIdempotencyMismatch):
+		return status.Errorf(codes.FailedPrecondition, "%s: %v", msg, err)
+	case errors.Is(err, domain.ErrIdempotencyInProgress):
+		return status.Errorf(codes.Aborted, "%s: %v", msg, err)
+	}
+	return errorStatus(err, msg)
+}
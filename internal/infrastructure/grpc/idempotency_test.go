@@ -0,0 +1,184 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"markitos-it-svc-goldens/internal/domain"
+	pb "markitos-it-svc-goldens/proto/goldens/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// memoryIdempotencyStore keeps records in memory and ignores expiry.
+type memoryIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]*domain.IdempotencyRecord
+}
+
+func newMemoryIdempotencyStore() *memoryIdempotencyStore {
+	return &memoryIdempotencyStore{records: make(map[string]*domain.IdempotencyRecord)}
+}
+
+func (s *memoryIdempotencyStore) Reserve(ctx context.Context, key, method, requestHash string, lease time.Duration) (*domain.IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if record, ok := s.records[method+key]; ok {
+		copied := *record
+		return &copied, nil
+	}
+	s.records[method+key] = &domain.IdempotencyRecord{Key: key, Method: method, RequestHash: requestHash}
+	return nil, nil
+}
+
+func (s *memoryIdempotencyStore) Complete(ctx context.Context, key, method string, response []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[method+key].Response = response
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Release(ctx context.Context, key, method string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, method+key)
+	return nil
+}
+
+func (s *memoryIdempotencyStore) PurgeExpired(ctx context.Context) (int64, error) { return 0, nil }
+
+// helperIdempotentCall is a call of the editor named principal sending key.
+func helperIdempotentCall(principal, key string) context.Context {
+	ctx := domain.WithPrincipal(domain.WithEditor(context.Background()), principal)
+	if key == "" {
+		return ctx
+	}
+	return metadata.NewIncomingContext(ctx, metadata.Pairs(IdempotencyKeyHeader, key))
+}
+
+func TestUnaryIdempotency(t *testing.T) {
+	createInfo := &grpc.UnaryServerInfo{FullMethod: pb.GoldenService_CreateGolden_FullMethodName}
+	readInfo := &grpc.UnaryServerInfo{FullMethod: pb.GoldenService_GetGoldenById_FullMethodName}
+	req := &pb.CreateGoldenRequest{Golden: &pb.Golden{Id: "a", Title: "A"}}
+	other := &pb.CreateGoldenRequest{Golden: &pb.Golden{Id: "b", Title: "B"}}
+
+	type call struct {
+		caller   string
+		key      string
+		info     *grpc.UnaryServerInfo
+		req      proto.Message
+		fail     bool
+		wantCode codes.Code
+	}
+	tests := []struct {
+		name      string
+		calls     []call
+		wantCalls int
+	}{
+		{
+			name:      "no-key",
+			calls:     []call{{info: createInfo, req: req}, {info: createInfo, req: req}},
+			wantCalls: 2,
+		},
+		{
+			name:      "retry-replays",
+			calls:     []call{{key: "k", info: createInfo, req: req}, {key: "k", info: createInfo, req: req}},
+			wantCalls: 1,
+		},
+		{
+			name:      "reused-key",
+			calls:     []call{{key: "k", info: createInfo, req: req}, {key: "k", info: createInfo, req: other, wantCode: codes.FailedPrecondition}},
+			wantCalls: 1,
+		},
+		{
+			name:      "failure-is-forgotten",
+			calls:     []call{{key: "k", info: createInfo, req: req, fail: true, wantCode: codes.Internal}, {key: "k", info: createInfo, req: req}},
+			wantCalls: 2,
+		},
+		{
+			name:      "scoped-to-caller",
+			calls:     []call{{caller: "a", key: "k", info: createInfo, req: req}, {caller: "b", key: "k", info: createInfo, req: other}, {caller: "b", key: "k", info: createInfo, req: other}},
+			wantCalls: 2,
+		},
+		{
+			name:      "readers-ignore-key",
+			calls:     []call{{caller: "reader", key: "k", info: createInfo, req: req}, {caller: "reader", key: "k", info: createInfo, req: req}},
+			wantCalls: 2,
+		},
+		{
+			name:      "reads-ignore-key",
+			calls:     []call{{key: "k", info: readInfo, req: req}, {key: "k", info: readInfo, req: req}},
+			wantCalls: 2,
+		},
+		{
+			name:      "key-too-long",
+			calls:     []call{{key: strings.Repeat("k", maxIdempotencyKeyLength+1), info: createInfo, req: req, wantCode: codes.InvalidArgument}},
+			wantCalls: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interceptor := UnaryIdempotency(newMemoryIdempotencyStore(), DefaultIdempotencyConfig())
+			handlerCalls := 0
+
+			// Only retries of the same editor are replays.
+			first := make(map[string]any)
+			for i, c := range tt.calls {
+				ctx := helperIdempotentCall(c.caller, c.key)
+				if c.caller == "reader" {
+					ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(IdempotencyKeyHeader, c.key))
+				}
+				resp, err := interceptor(ctx, c.req, c.info, func(ctx context.Context, req any) (any, error) {
+					handlerCalls++
+					if c.fail {
+						return nil, status.Error(codes.Internal, "boom")
+					}
+					return &pb.CreateGoldenResponse{Golden: &pb.Golden{Id: "a", Title: "A", ContentHash: time.Now().String()}}, nil
+				})
+				if got := status.Code(err); got != c.wantCode {
+					t.Fatalf("call %d: code = %v, want %v", i, got, c.wantCode)
+				}
+				if err != nil {
+					continue
+				}
+				if first[c.caller] == nil {
+					first[c.caller] = resp
+				} else if c.key != "" && c.info == createInfo && c.caller != "reader" && !proto.Equal(first[c.caller].(proto.Message), resp.(proto.Message)) {
+					t.Fatalf("call %d: replayed %v, want %v", i, resp, first[c.caller])
+				}
+			}
+			if handlerCalls != tt.wantCalls {
+				t.Fatalf("handler calls = %d, want %d", handlerCalls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestUnaryIdempotency_InProgress(t *testing.T) {
+	store := newMemoryIdempotencyStore()
+	info := &grpc.UnaryServerInfo{FullMethod: pb.GoldenService_DeleteGolden_FullMethodName}
+	req := &pb.DeleteGoldenRequest{Id: "a"}
+	hash, err := requestHash(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := helperIdempotentCall("a", "k")
+	if _, err := store.Reserve(context.Background(), scopedIdempotencyKey(ctx, "k"), info.FullMethod, hash, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = UnaryIdempotency(store, DefaultIdempotencyConfig())(ctx, req, info, func(ctx context.Context, req any) (any, error) {
+		return nil, errors.New("handler must not run")
+	})
+	if got := status.Code(err); got != codes.Aborted {
+		t.Fatalf("code = %v, want %v", got, codes.Aborted)
+	}
+}
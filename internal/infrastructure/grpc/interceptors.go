@@ -0,0 +1,29 @@
+package grpc
+
+import (
+	"context"
+
+	"markitos-it-svc-goldens/internal/domain"
+
+	"google.golang.org/grpc"
+)
+
+// UnaryWriteTracking scopes domain.WithWriteTracking to each call, so reads
+// made after a write in the same call see it even with a read replica.
+func UnaryWriteTracking(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	return handler(domain.WithWriteTracking(ctx), req)
+}
+
+// StreamWriteTracking is UnaryWriteTracking for streaming calls.
+func StreamWriteTracking(srv any, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &trackedStream{ServerStream: stream, ctx: domain.WithWriteTracking(stream.Context())})
+}
+
+type trackedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *trackedStream) Context() context.Context {
+	return s.ctx
+}
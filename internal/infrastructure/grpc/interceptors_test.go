@@ -0,0 +1,122 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"markitos-it-svc-goldens/internal/domain"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestUnaryWriteTracking(t *testing.T) {
+	var wrote bool
+	_, err := UnaryWriteTracking(context.Background(), nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req any) (any, error) {
+		domain.MarkWrite(ctx)
+		wrote = domain.HasWritten(ctx)
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("UnaryWriteTracking() error = %v", err)
+	}
+	if !wrote {
+		t.Fatal("expected the handler context to track writes")
+	}
+}
+
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s contextStream) Context() context.Context { return s.ctx }
+
+func TestStreamWriteTracking(t *testing.T) {
+	var wrote bool
+	err := StreamWriteTracking(nil, contextStream{ctx: context.Background()}, &grpc.StreamServerInfo{}, func(srv any, stream grpc.ServerStream) error {
+		domain.MarkWrite(stream.Context())
+		wrote = domain.HasWritten(stream.Context())
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamWriteTracking() error = %v", err)
+	}
+	if !wrote {
+		t.Fatal("expected the stream context to track writes")
+	}
+}
+
+func TestEditorAuth(t *testing.T) {
+	auth := NewEditorAuth([]string{" editor-token ", ""})
+	tests := []struct {
+		name   string
+		header []string
+		want   bool
+	}{
+		{name: "no-header"},
+		{name: "valid-token", header: []string{"Bearer editor-token"}, want: true},
+		{name: "scheme-is-case-insensitive", header: []string{"bearer editor-token"}, want: true},
+		{name: "wrong-token", header: []string{"Bearer reader-token"}},
+		{name: "not-bearer", header: []string{"Basic editor-token"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.header != nil {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", tt.header[0]))
+			}
+			var editor bool
+			_, err := auth.Unary(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req any) (any, error) {
+				editor = domain.IsEditor(ctx)
+				return nil, nil
+			})
+			if err != nil || editor != tt.want {
+				t.Fatalf("Unary() editor = %v, %v; want %v", editor, err, tt.want)
+			}
+		})
+	}
+
+	for _, tt := range []struct {
+		name string
+		auth *EditorAuth
+		want bool
+	}{
+		{name: "no-tokens", auth: NewEditorAuth(nil)},
+		{name: "open", auth: NewOpenEditorAuth(), want: true},
+	} {
+		var editor bool
+		err := tt.auth.Stream(nil, contextStream{ctx: context.Background()}, &grpc.StreamServerInfo{}, func(srv any, stream grpc.ServerStream) error {
+			editor = domain.IsEditor(stream.Context())
+			return nil
+		})
+		if err != nil || editor != tt.want {
+			t.Fatalf("Stream() %s: editor = %v, %v; want %v", tt.name, editor, err, tt.want)
+		}
+	}
+}
+
+func TestEditorAuth_Principal(t *testing.T) {
+	auth := NewEditorAuth([]string{"token-a", "token-b"})
+	principal := func(token string) string {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+		var got string
+		auth.Unary(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req any) (any, error) {
+			got = domain.Principal(ctx)
+			return nil, nil
+		})
+		return got
+	}
+
+	a, b := principal("token-a"), principal("token-b")
+	if a == "" || b == "" || a == b {
+		t.Fatalf("principals = %q, %q; want one per token", a, b)
+	}
+	if strings.Contains(a, "token-a") {
+		t.Fatalf("principal %q reveals the token", a)
+	}
+	if got := principal("token-c"); got != "" {
+		t.Fatalf("principal of a reader = %q, want none", got)
+	}
+}
@@ -0,0 +1,68 @@
+package grpc
+
+import (
+	"context"
+
+	legacypb "markitos-it-svc-goldens/proto"
+	pb "markitos-it-svc-goldens/proto/goldens/v1"
+)
+
+// LegacyGoldenServer serves the unversioned goldens.GoldenService for clients
+// built before goldens.v1. Both services share their messages, so every RPC is
+// handed to the goldens.v1 server as is.
+type LegacyGoldenServer struct {
+	legacypb.UnimplementedGoldenServiceServer
+	server pb.GoldenServiceServer
+}
+
+func NewLegacyGoldenServer(server pb.GoldenServiceServer) *LegacyGoldenServer {
+	return &LegacyGoldenServer{server: server}
+}
+
+func (s *LegacyGoldenServer) GetAllGoldens(ctx context.Context, req *pb.GetAllGoldensRequest) (*pb.GetAllGoldensResponse, error) {
+	return s.server.GetAllGoldens(ctx, req)
+}
+
+func (s *LegacyGoldenServer) GetGoldenById(ctx context.Context, req *pb.GetGoldenByIdRequest) (*pb.GetGoldenByIdResponse, error) {
+	return s.server.GetGoldenById(ctx, req)
+}
+
+func (s *LegacyGoldenServer) GetGoldenBySlug(ctx context.Context, req *pb.GetGoldenBySlugRequest) (*pb.GetGoldenBySlugResponse, error) {
+	return s.server.GetGoldenBySlug(ctx, req)
+}
+
+func (s *LegacyGoldenServer) CreateGolden(ctx context.Context, req *pb.CreateGoldenRequest) (*pb.CreateGoldenResponse, error) {
+	return s.server.CreateGolden(ctx, req)
+}
+
+func (s *LegacyGoldenServer) UpdateGolden(ctx context.Context, req *pb.UpdateGoldenRequest) (*pb.UpdateGoldenResponse, error) {
+	return s.server.UpdateGolden(ctx, req)
+}
+
+func (s *LegacyGoldenServer) DeleteGolden(ctx context.Context, req *pb.DeleteGoldenRequest) (*pb.DeleteGoldenResponse, error) {
+	return s.server.DeleteGolden(ctx, req)
+}
+
+func (s *LegacyGoldenServer) ImportGoldens(stream legacypb.GoldenService_ImportGoldensServer) error {
+	return s.server.ImportGoldens(stream)
+}
+
+func (s *LegacyGoldenServer) ExportGoldens(req *pb.ExportGoldensRequest, stream legacypb.GoldenService_ExportGoldensServer) error {
+	return s.server.ExportGoldens(req, stream)
+}
+
+func (s *LegacyGoldenServer) CreateWebhookSubscription(ctx context.Context, req *pb.CreateWebhookSubscriptionRequest) (*pb.CreateWebhookSubscriptionResponse, error) {
+	return s.server.CreateWebhookSubscription(ctx, req)
+}
+
+func (s *LegacyGoldenServer) ListWebhookSubscriptions(ctx context.Context, req *pb.ListWebhookSubscriptionsRequest) (*pb.ListWebhookSubscriptionsResponse, error) {
+	return s.server.ListWebhookSubscriptions(ctx, req)
+}
+
+func (s *LegacyGoldenServer) DeleteWebhookSubscription(ctx context.Context, req *pb.DeleteWebhookSubscriptionRequest) (*pb.DeleteWebhookSubscriptionResponse, error) {
+	return s.server.DeleteWebhookSubscription(ctx, req)
+}
+
+func (s *LegacyGoldenServer) ListWebhookDeliveries(ctx context.Context, req *pb.ListWebhookDeliveriesRequest) (*pb.ListWebhookDeliveriesResponse, error) {
+	return s.server.ListWebhookDeliveries(ctx, req)
+}
@@ -0,0 +1,88 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"markitos-it-svc-goldens/internal/application/services"
+	"markitos-it-svc-goldens/internal/domain"
+	legacypb "markitos-it-svc-goldens/proto"
+	pb "markitos-it-svc-goldens/proto/goldens/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func TestLegacyGoldenServer_ServesV1(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	server := grpc.NewServer()
+	goldenServer := NewGoldenServer(services.NewGoldenService(&stubRepo{doc: &domain.Golden{ID: "id-1", Title: "Keptn"}}))
+	pb.RegisterGoldenServiceServer(server, goldenServer)
+	legacypb.RegisterGoldenServiceServer(server, NewLegacyGoldenServer(goldenServer))
+	go server.Serve(listener)
+	defer server.Stop()
+
+	conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("grpc.NewClient() error = %v", err)
+	}
+	defer conn.Close()
+
+	req := &pb.GetGoldenByIdRequest{Id: "id-1"}
+	v1, err := pb.NewGoldenServiceClient(conn).GetGoldenById(context.Background(), req)
+	if err != nil {
+		t.Fatalf("goldens.v1 GetGoldenById() error = %v", err)
+	}
+	legacy, err := legacypb.NewGoldenServiceClient(conn).GetGoldenById(context.Background(), req)
+	if err != nil {
+		t.Fatalf("goldens GetGoldenById() error = %v", err)
+	}
+	if !proto.Equal(v1, legacy) || legacy.Golden.GetTitle() != "Keptn" {
+		t.Fatalf("legacy response = %v, want %v", legacy, v1)
+	}
+}
+
+func TestUnaryIdempotency_ReplaysUnversionedResponse(t *testing.T) {
+	store := newMemoryIdempotencyStore()
+	info := &grpc.UnaryServerInfo{FullMethod: legacypb.GoldenService_CreateGolden_FullMethodName}
+	req := &pb.CreateGoldenRequest{Golden: &pb.Golden{Id: "a", Title: "A"}}
+	hash, err := requestHash(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A response stored before the move to goldens.v1.
+	want := &pb.CreateGoldenResponse{Golden: &pb.Golden{Id: "a", Title: "A"}}
+	value, err := proto.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, err := proto.Marshal(&anypb.Any{TypeUrl: "type.googleapis.com/goldens.CreateGoldenResponse", Value: value})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := helperIdempotentCall("a", "k")
+	if _, err := store.Reserve(context.Background(), scopedIdempotencyKey(ctx, "k"), info.FullMethod, hash, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Complete(context.Background(), scopedIdempotencyKey(ctx, "k"), info.FullMethod, stored, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := UnaryIdempotency(store, DefaultIdempotencyConfig())(ctx, req, info, func(ctx context.Context, req any) (any, error) {
+		t.Fatal("handler must not run")
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("replay error = %v", err)
+	}
+	if !proto.Equal(resp.(proto.Message), want) {
+		t.Fatalf("replayed %v, want %v", resp, want)
+	}
+}
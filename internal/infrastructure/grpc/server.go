@@ -1,41 +1,98 @@
 package grpc
 
 import (
+	"bufio"
 	"context"
+	"io"
 	"log"
+	"time"
 
 	"markitos-it-svc-goldens/internal/application/services"
 	"markitos-it-svc-goldens/internal/domain"
-	pb "markitos-it-svc-goldens/proto"
+	"markitos-it-svc-goldens/internal/infrastructure/archive"
+	pb "markitos-it-svc-goldens/proto/goldens/v1"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// DefaultBatchGetLimit is the most ids a BatchGetGoldens call may ask for
+// unless WithBatchGetLimit says otherwise.
+const DefaultBatchGetLimit = 100
+
 type GoldenServer struct {
 	pb.UnimplementedGoldenServiceServer
-	service *services.GoldenService
+	service       *services.GoldenService
+	webhooks      *services.WebhookService
+	categories    *services.CategoryService
+	tags          *services.TagService
+	batchGetLimit int
+}
+
+type ServerOption func(*GoldenServer)
+
+// WithBatchGetLimit bounds the ids of a BatchGetGoldens call.
+func WithBatchGetLimit(n int) ServerOption {
+	return func(s *GoldenServer) {
+		s.batchGetLimit = n
+	}
 }
 
-func NewGoldenServer(service *services.GoldenService) *GoldenServer {
-	return &GoldenServer{
-		service: service,
+// WithWebhooks enables the webhook subscription RPCs.
+func WithWebhooks(webhooks *services.WebhookService) ServerOption {
+	return func(s *GoldenServer) {
+		s.webhooks = webhooks
 	}
 }
 
+// WithCategories enables the category tree RPCs.
+func WithCategories(categories *services.CategoryService) ServerOption {
+	return func(s *GoldenServer) {
+		s.categories = categories
+	}
+}
+
+// WithTags enables the tag registry RPCs.
+func WithTags(tags *services.TagService) ServerOption {
+	return func(s *GoldenServer) {
+		s.tags = tags
+	}
+}
+
+func NewGoldenServer(service *services.GoldenService, opts ...ServerOption) *GoldenServer {
+	s := &GoldenServer{
+		service:       service,
+		batchGetLimit: DefaultBatchGetLimit,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
 func (s *GoldenServer) GetAllGoldens(ctx context.Context, req *pb.GetAllGoldensRequest) (*pb.GetAllGoldensResponse, error) {
-	log.Println("GetAllGoldens called")
+	log.Printf("GetAllGoldens called with category: %q, status: %s", req.Category, req.Status)
 
-	docs, err := s.service.GetAllGoldens(ctx)
+	view := viewFromProto(req.View)
+	var docs []domain.Golden
+	var err error
+	if req.Category != "" {
+		docs, err = s.service.GetGoldensByCategory(ctx, req.Category, view)
+	} else {
+		docs, err = s.service.GetAllGoldens(ctx, view)
+	}
 	if err != nil {
 		log.Printf("Error getting all goldens: %v", err)
-		return nil, status.Errorf(codes.Internal, "failed to get goldens: %v", err)
+		return nil, errorStatus(err, "failed to get goldens")
 	}
 
 	pbDocs := make([]*pb.Golden, 0, len(docs))
 	for _, doc := range docs {
-		pbDocs = append(pbDocs, goldenToProto(&doc))
+		if req.Status != pb.GoldenStatus_GOLDEN_STATUS_UNSPECIFIED && statusToProto(doc.Status) != req.Status {
+			continue
+		}
+		pbDocs = append(pbDocs, goldenToProto(&doc, view))
 	}
 
 	return &pb.GetAllGoldensResponse{
@@ -46,20 +103,281 @@ func (s *GoldenServer) GetAllGoldens(ctx context.Context, req *pb.GetAllGoldensR
 func (s *GoldenServer) GetGoldenById(ctx context.Context, req *pb.GetGoldenByIdRequest) (*pb.GetGoldenByIdResponse, error) {
 	log.Printf("GetGoldenById called with id: %s", req.Id)
 
-	doc, err := s.service.GetGoldenByID(ctx, req.Id)
+	view := viewFromProto(req.View)
+	doc, err := s.service.GetGoldenByID(ctx, req.Id, view)
 	if err != nil {
 		log.Printf("Error getting golden by id %s: %v", req.Id, err)
-		return nil, status.Errorf(codes.NotFound, "golden not found: %v", err)
+		return nil, errorStatus(err, "failed to get golden")
 	}
 
 	return &pb.GetGoldenByIdResponse{
-		Golden: goldenToProto(doc),
+		Golden: goldenToProto(doc, view),
+	}, nil
+}
+
+func (s *GoldenServer) GetGoldenBySlug(ctx context.Context, req *pb.GetGoldenBySlugRequest) (*pb.GetGoldenBySlugResponse, error) {
+	log.Printf("GetGoldenBySlug called with slug: %s", req.Slug)
+	if req.Slug == "" {
+		return nil, status.Error(codes.InvalidArgument, "slug is required")
+	}
+
+	view := viewFromProto(req.View)
+	doc, err := s.service.GetGoldenBySlug(ctx, req.Slug, view)
+	if err != nil {
+		log.Printf("Error getting golden by slug %s: %v", req.Slug, err)
+		return nil, errorStatus(err, "failed to get golden")
+	}
+
+	return &pb.GetGoldenBySlugResponse{
+		Golden: goldenToProto(doc, view),
+	}, nil
+}
+
+func (s *GoldenServer) BatchGetGoldens(ctx context.Context, req *pb.BatchGetGoldensRequest) (*pb.BatchGetGoldensResponse, error) {
+	log.Printf("BatchGetGoldens called with %d ids", len(req.Ids))
+	if len(req.Ids) > s.batchGetLimit {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d ids can be fetched at once, got %d", s.batchGetLimit, len(req.Ids))
+	}
+
+	view := viewFromProto(req.View)
+	docs, missing, err := s.service.BatchGetGoldens(ctx, req.Ids, view)
+	if err != nil {
+		log.Printf("Error batch getting goldens: %v", err)
+		return nil, errorStatus(err, "failed to get goldens")
+	}
+
+	pbDocs := make([]*pb.Golden, 0, len(docs))
+	for _, doc := range docs {
+		pbDocs = append(pbDocs, goldenToProto(&doc, view))
+	}
+
+	return &pb.BatchGetGoldensResponse{
+		Goldens:    pbDocs,
+		MissingIds: missing,
+	}, nil
+}
+
+func (s *GoldenServer) ListCategories(ctx context.Context, req *pb.ListCategoriesRequest) (*pb.ListCategoriesResponse, error) {
+	log.Printf("ListCategories called with tag: %q", req.Tag)
+
+	facets, err := s.service.ListCategories(ctx, req.Tag)
+	if err != nil {
+		log.Printf("Error listing categories: %v", err)
+		return nil, errorStatus(err, "failed to list categories")
+	}
+
+	return &pb.ListCategoriesResponse{
+		Categories: facetsToProto(facets),
+	}, nil
+}
+
+func (s *GoldenServer) ListTags(ctx context.Context, req *pb.ListTagsRequest) (*pb.ListTagsResponse, error) {
+	log.Printf("ListTags called with category: %q", req.Category)
+
+	facets, err := s.service.ListTags(ctx, req.Category)
+	if err != nil {
+		log.Printf("Error listing tags: %v", err)
+		return nil, errorStatus(err, "failed to list tags")
+	}
+
+	return &pb.ListTagsResponse{
+		Tags: facetsToProto(facets),
+	}, nil
+}
+
+func (s *GoldenServer) CreateGolden(ctx context.Context, req *pb.CreateGoldenRequest) (*pb.CreateGoldenResponse, error) {
+	doc := goldenFromProto(req.Golden)
+	if doc.ID == "" {
+		doc.ID = domain.NewID()
+	}
+	log.Printf("CreateGolden called with id: %s", doc.ID)
+
+	if err := doc.Validate(); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid golden: %v", err)
+	}
+	doc.UpdatedAt = time.Now().UTC()
+
+	if err := s.service.CreateGolden(ctx, &doc); err != nil {
+		log.Printf("Error creating golden %s: %v", doc.ID, err)
+		return nil, errorStatus(err, "failed to create golden")
+	}
+
+	return &pb.CreateGoldenResponse{
+		Golden: goldenToProto(&doc, domain.GoldenViewFull),
+	}, nil
+}
+
+func (s *GoldenServer) UpdateGolden(ctx context.Context, req *pb.UpdateGoldenRequest) (*pb.UpdateGoldenResponse, error) {
+	doc := goldenFromProto(req.Golden)
+	log.Printf("UpdateGolden called with id: %s", doc.ID)
+
+	if err := doc.Validate(); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid golden: %v", err)
+	}
+	doc.UpdatedAt = time.Now().UTC()
+
+	var opts []services.UpdateOption
+	if req.ClearPublishAt {
+		opts = append(opts, services.WithClearPublishAt())
+	}
+	if req.ClearExpireAt {
+		opts = append(opts, services.WithClearExpireAt())
+	}
+	if err := s.service.UpdateGolden(ctx, &doc, opts...); err != nil {
+		log.Printf("Error updating golden %s: %v", doc.ID, err)
+		return nil, errorStatus(err, "failed to update golden")
+	}
+
+	return &pb.UpdateGoldenResponse{
+		Golden: goldenToProto(&doc, domain.GoldenViewFull),
 	}, nil
 }
 
-func goldenToProto(doc *domain.Golden) *pb.Golden {
+func (s *GoldenServer) DeleteGolden(ctx context.Context, req *pb.DeleteGoldenRequest) (*pb.DeleteGoldenResponse, error) {
+	log.Printf("DeleteGolden called with id: %s", req.Id)
+
+	if err := s.service.DeleteGolden(ctx, req.Id); err != nil {
+		log.Printf("Error deleting golden %s: %v", req.Id, err)
+		return nil, errorStatus(err, "failed to delete golden")
+	}
+
+	return &pb.DeleteGoldenResponse{}, nil
+}
+
+func (s *GoldenServer) ImportGoldens(stream pb.GoldenService_ImportGoldensServer) error {
+	log.Println("ImportGoldens called")
+
+	ctx := stream.Context()
+	resp := &pb.ImportGoldensResponse{}
+	var batch []domain.Golden
+	var opts []services.ImportOption
+	first := true
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		results, err := s.service.ImportGoldens(ctx, batch, resp.DryRun, opts...)
+		if err != nil {
+			log.Printf("Error importing goldens: %v", err)
+			return errorStatus(err, "failed to import goldens")
+		}
+		for _, result := range results {
+			resp.Results = append(resp.Results, importResultToProto(result))
+			switch result.Status {
+			case domain.ImportStatusCreated:
+				resp.Created++
+			case domain.ImportStatusUpdated:
+				resp.Updated++
+			default:
+				resp.Failed++
+			}
+		}
+
+		batch = nil
+		return nil
+	}
+
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if first {
+			resp.DryRun = req.DryRun
+			if req.ResetStatus {
+				opts = append(opts, services.WithResetStatus())
+			}
+			first = false
+		}
+		batch = append(batch, importedGoldenFromProto(req.Golden))
+		if len(batch) == services.ImportBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := flush(); err != nil {
+		return err
+	}
+
+	log.Printf("ImportGoldens finished: created=%d updated=%d failed=%d dry_run=%t", resp.Created, resp.Updated, resp.Failed, resp.DryRun)
+	return stream.SendAndClose(resp)
+}
+
+func (s *GoldenServer) ExportGoldens(req *pb.ExportGoldensRequest, stream pb.GoldenService_ExportGoldensServer) error {
+	log.Println("ExportGoldens called")
+
+	docs, err := s.service.GetAllGoldens(stream.Context(), domain.GoldenViewFull)
+	if err != nil {
+		log.Printf("Error getting goldens for export: %v", err)
+		return errorStatus(err, "failed to get goldens")
+	}
+
+	w := bufio.NewWriterSize(exportChunkWriter{stream: stream}, exportChunkSize)
+	if err := archive.Write(w, docs); err != nil {
+		log.Printf("Error writing export archive: %v", err)
+		return status.Errorf(codes.Internal, "failed to export goldens: %v", err)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	log.Printf("ExportGoldens finished: %d goldens", len(docs))
+	return nil
+}
+
+const exportChunkSize = 32 << 10
+
+type exportChunkWriter struct {
+	stream pb.GoldenService_ExportGoldensServer
+}
+
+func (w exportChunkWriter) Write(p []byte) (int, error) {
+	data := make([]byte, len(p))
+	copy(data, p)
+	if err := w.stream.Send(&pb.ExportGoldensResponse{Data: data}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func importResultToProto(result domain.ImportResult) *pb.ImportResult {
+	res := &pb.ImportResult{Id: result.ID}
+	switch result.Status {
+	case domain.ImportStatusCreated:
+		res.Status = pb.ImportStatus_IMPORT_STATUS_CREATED
+	case domain.ImportStatusUpdated:
+		res.Status = pb.ImportStatus_IMPORT_STATUS_UPDATED
+	default:
+		res.Status = pb.ImportStatus_IMPORT_STATUS_FAILED
+	}
+	if result.Err != nil {
+		res.Error = result.Err.Error()
+	}
+	return res
+}
+
+func viewFromProto(view pb.GoldenView) domain.GoldenView {
+	if view == pb.GoldenView_GOLDEN_VIEW_BASIC {
+		return domain.GoldenViewBasic
+	}
+	return domain.GoldenViewFull
+}
+
+func goldenToProto(doc *domain.Golden, view domain.GoldenView) *pb.Golden {
+	projected := doc.WithView(view)
+	doc = &projected
+
 	return &pb.Golden{
 		Id:          doc.ID,
+		Slug:        doc.Slug,
 		Title:       doc.Title,
 		Description: doc.Description,
 		Category:    doc.Category,
@@ -67,5 +385,69 @@ func goldenToProto(doc *domain.Golden) *pb.Golden {
 		UpdatedAt:   timestamppb.New(doc.UpdatedAt),
 		ContentB64:  doc.ContentB64,
 		CoverImage:  doc.CoverImage,
+		ContentSize: doc.ContentSize,
+		ContentHash: doc.ContentHash,
+		Status:      statusToProto(doc.Status),
+		SubmittedAt: optionalTimestamp(doc.SubmittedAt),
+		PublishedAt: optionalTimestamp(doc.PublishedAt),
+		ArchivedAt:  optionalTimestamp(doc.ArchivedAt),
+		PublishAt:   optionalTimestamp(doc.PublishAt),
+		ExpireAt:    optionalTimestamp(doc.ExpireAt),
+	}
+}
+
+func goldenFromProto(doc *pb.Golden) domain.Golden {
+	if doc == nil {
+		return domain.Golden{}
+	}
+
+	golden := domain.Golden{
+		ID:          doc.Id,
+		Slug:        doc.Slug,
+		Title:       doc.Title,
+		Description: doc.Description,
+		Category:    doc.Category,
+		Tags:        doc.Tags,
+		ContentB64:  doc.ContentB64,
+		CoverImage:  doc.CoverImage,
+	}
+	if doc.UpdatedAt != nil {
+		golden.UpdatedAt = doc.UpdatedAt.AsTime()
+	}
+	if doc.PublishAt != nil {
+		golden.PublishAt = doc.PublishAt.AsTime()
+	}
+	if doc.ExpireAt != nil {
+		golden.ExpireAt = doc.ExpireAt.AsTime()
+	}
+	return golden
+}
+
+// importedGoldenFromProto also reads the status fields, which only imports
+// write.
+func importedGoldenFromProto(doc *pb.Golden) domain.Golden {
+	golden := goldenFromProto(doc)
+	if doc == nil {
+		return golden
+	}
+
+	golden.Status = statusFromProto(doc.Status)
+	if doc.SubmittedAt != nil {
+		golden.SubmittedAt = doc.SubmittedAt.AsTime()
+	}
+	if doc.PublishedAt != nil {
+		golden.PublishedAt = doc.PublishedAt.AsTime()
+	}
+	if doc.ArchivedAt != nil {
+		golden.ArchivedAt = doc.ArchivedAt.AsTime()
+	}
+	return golden
+}
+
+func facetsToProto(facets []domain.FacetCount) []*pb.FacetCount {
+	pbFacets := make([]*pb.FacetCount, 0, len(facets))
+	for _, facet := range facets {
+		pbFacets = append(pbFacets, &pb.FacetCount{Value: facet.Value, Count: int32(facet.Count)})
 	}
+	return pbFacets
 }
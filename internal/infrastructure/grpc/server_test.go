@@ -1,16 +1,24 @@
 package grpc
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
+	"io"
 	"markitos-it-svc-goldens/internal/application/services"
 	"markitos-it-svc-goldens/internal/domain"
-	pb "markitos-it-svc-goldens/proto"
+	"markitos-it-svc-goldens/internal/infrastructure/archive"
+	pb "markitos-it-svc-goldens/proto/goldens/v1"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type stubRepo struct {
@@ -19,26 +27,160 @@ type stubRepo struct {
 	err  error
 }
 
-func (r *stubRepo) GetAll(ctx context.Context) ([]domain.Golden, error) {
+func (r *stubRepo) GetAll(ctx context.Context, view domain.GoldenView) ([]domain.Golden, error) {
 	if r.err != nil {
 		return nil, r.err
 	}
-	return r.docs, nil
+	return published(r.docs...), nil
 }
 
-func (r *stubRepo) GetByID(ctx context.Context, id string) (*domain.Golden, error) {
+func (r *stubRepo) GetByID(ctx context.Context, id string, view domain.GoldenView) (*domain.Golden, error) {
 	if r.err != nil {
 		return nil, r.err
 	}
 	if r.doc != nil {
-		return r.doc, nil
+		return &published(*r.doc)[0], nil
 	}
-	return &domain.Golden{ID: id, UpdatedAt: time.Unix(0, 0).UTC()}, nil
+	return &domain.Golden{ID: id, UpdatedAt: time.Unix(0, 0).UTC(), Status: domain.StatusPublished}, nil
+}
+
+func (r *stubRepo) GetByIDs(ctx context.Context, ids []string, view domain.GoldenView) ([]domain.Golden, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	var docs []domain.Golden
+	for _, id := range ids {
+		if id != "missing" {
+			docs = append(docs, domain.Golden{ID: id, UpdatedAt: time.Unix(0, 0).UTC(), Status: domain.StatusPublished})
+		}
+	}
+	return docs, nil
+}
+
+func (r *stubRepo) GetByCategories(ctx context.Context, categories []string, view domain.GoldenView) ([]domain.Golden, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	var docs []domain.Golden
+	for _, doc := range r.docs {
+		if slices.Contains(categories, doc.Category) {
+			docs = append(docs, doc)
+		}
+	}
+	return published(docs...), nil
+}
+
+func (r *stubRepo) GetBySlug(ctx context.Context, slug string, view domain.GoldenView) (*domain.Golden, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	if r.doc != nil {
+		return &published(*r.doc)[0], nil
+	}
+	return &domain.Golden{ID: "id-" + slug, Slug: slug, UpdatedAt: time.Unix(0, 0).UTC(), Status: domain.StatusPublished}, nil
+}
+
+func (r *stubRepo) SlugsInUse(ctx context.Context, base string) ([]string, error) { return nil, r.err }
+
+func (r *stubRepo) CountCategories(ctx context.Context, tag string) ([]domain.FacetCount, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	return []domain.FacetCount{{Value: "DevOps" + tag, Count: 2}}, nil
+}
+
+func (r *stubRepo) CountTags(ctx context.Context, categories []string) ([]domain.FacetCount, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	return []domain.FacetCount{{Value: "k8s" + strings.Join(categories, ","), Count: 3}}, nil
 }
 
 func (r *stubRepo) Create(ctx context.Context, doc *domain.Golden) error { return nil }
 func (r *stubRepo) Update(ctx context.Context, doc *domain.Golden) error { return nil }
 func (r *stubRepo) Delete(ctx context.Context, id string) error          { return nil }
+func (r *stubRepo) Upsert(ctx context.Context, docs []domain.Golden, dryRun bool) ([]domain.ImportResult, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	results := make([]domain.ImportResult, 0, len(docs))
+	for i, doc := range docs {
+		status := domain.ImportStatusCreated
+		if i%2 == 1 {
+			status = domain.ImportStatusUpdated
+		}
+		results = append(results, domain.ImportResult{ID: doc.ID, Status: status})
+	}
+	return results, nil
+}
+func (r *stubRepo) AppendEvents(ctx context.Context, events ...domain.Event) error { return nil }
+func (r *stubRepo) AppendTransition(ctx context.Context, transition domain.GoldenTransition) error {
+	return nil
+}
+func (r *stubRepo) ListTransitions(ctx context.Context, id string) ([]domain.GoldenTransition, error) {
+	return nil, r.err
+}
+func (r *stubRepo) RunInTx(ctx context.Context, fn func(tx domain.Repository) error, opts ...domain.TxOption) error {
+	return fn(r)
+}
+
+// published returns copies of docs, with the goldens that have no status
+// marked as published so that tests only set one to hide the golden.
+func published(docs ...domain.Golden) []domain.Golden {
+	out := make([]domain.Golden, 0, len(docs))
+	for _, doc := range docs {
+		if doc.Status == "" {
+			doc.Status = domain.StatusPublished
+		}
+		out = append(out, doc)
+	}
+	return out
+}
+
+// stubExportStream collects the chunks sent by ExportGoldens.
+type stubExportStream struct {
+	grpc.ServerStream
+	data bytes.Buffer
+	sent int
+}
+
+func (s *stubExportStream) Context() context.Context { return context.Background() }
+
+func (s *stubExportStream) Send(resp *pb.ExportGoldensResponse) error {
+	s.sent++
+	_, err := s.data.Write(resp.Data)
+	return err
+}
+
+// stubImportStream feeds reqs to ImportGoldens, as an editor, and captures
+// the response.
+type stubImportStream struct {
+	grpc.ServerStream
+	reqs []*pb.ImportGoldensRequest
+	resp *pb.ImportGoldensResponse
+}
+
+func (s *stubImportStream) Context() context.Context { return helperEditorContext() }
+
+func (s *stubImportStream) Recv() (*pb.ImportGoldensRequest, error) {
+	if len(s.reqs) == 0 {
+		return nil, io.EOF
+	}
+	req := s.reqs[0]
+	s.reqs = s.reqs[1:]
+	return req, nil
+}
+
+func (s *stubImportStream) SendAndClose(resp *pb.ImportGoldensResponse) error {
+	s.resp = resp
+	return nil
+}
+
+// helperEditorContext is the context EditorAuth gives callers with editor
+// rights, which every write requires.
+func helperEditorContext() context.Context {
+	return domain.WithEditor(context.Background())
+}
 
 func TestNewGoldenServer(t *testing.T) {
 	svc := services.NewGoldenService(&stubRepo{})
@@ -126,7 +268,7 @@ func TestGoldenServer_GetGoldenById_Success(t *testing.T) {
 }
 
 func TestGoldenServer_GetGoldenById_Error(t *testing.T) {
-	s := NewGoldenServer(services.NewGoldenService(&stubRepo{err: errors.New("not found")}))
+	s := NewGoldenServer(services.NewGoldenService(&stubRepo{err: fmt.Errorf("%w: missing", domain.ErrNotFound)}))
 
 	got, err := s.GetGoldenById(context.Background(), &pb.GetGoldenByIdRequest{Id: "missing"})
 	if err == nil {
@@ -153,6 +295,7 @@ func TestGoldenServer_GetGoldenById_AllFieldsMapped(t *testing.T) {
 		UpdatedAt:   now,
 		ContentB64:  "Y29udGVudA==",
 		CoverImage:  "https://example.com/cover.png",
+		ExpireAt:    now.AddDate(100, 0, 0),
 	}
 	s := NewGoldenServer(services.NewGoldenService(&stubRepo{doc: doc}))
 
@@ -191,4 +334,302 @@ func TestGoldenServer_GetGoldenById_AllFieldsMapped(t *testing.T) {
 	if g.CoverImage != doc.CoverImage {
 		t.Errorf("CoverImage: want %q, got %q", doc.CoverImage, g.CoverImage)
 	}
+	if g.PublishAt != nil || !g.ExpireAt.AsTime().Equal(doc.ExpireAt) {
+		t.Errorf("PublishAt/ExpireAt: want unset/%v, got %v/%v", doc.ExpireAt, g.PublishAt, g.ExpireAt)
+	}
+	if got := goldenFromProto(g); !got.ExpireAt.Equal(doc.ExpireAt) || !got.PublishAt.IsZero() {
+		t.Errorf("goldenFromProto() ExpireAt/PublishAt = %v/%v, want %v/zero", got.ExpireAt, got.PublishAt, doc.ExpireAt)
+	}
+}
+
+func TestImportedGoldenFromProto_ReadsStatus(t *testing.T) {
+	at := time.Date(2026, 3, 6, 12, 0, 0, 0, time.UTC)
+	g := &pb.Golden{Id: "id-1", Status: pb.GoldenStatus_GOLDEN_STATUS_PUBLISHED, PublishedAt: timestamppb.New(at)}
+
+	if got := goldenFromProto(g); got.Status != "" || !got.PublishedAt.IsZero() {
+		t.Errorf("goldenFromProto() = %s published at %v, want no status", got.Status, got.PublishedAt)
+	}
+	if got := importedGoldenFromProto(g); got.Status != domain.StatusPublished || !got.PublishedAt.Equal(at) {
+		t.Errorf("importedGoldenFromProto() = %s published at %v, want published at %v", got.Status, got.PublishedAt, at)
+	}
+}
+
+func TestGoldenServer_GetAllGoldens_BasicViewOmitsContent(t *testing.T) {
+	repo := &stubRepo{
+		docs: []domain.Golden{
+			{ID: "id-1", Title: "title-1", ContentB64: "Y29udGVudA==", UpdatedAt: time.Unix(0, 0).UTC()},
+		},
+	}
+	s := NewGoldenServer(services.NewGoldenService(repo))
+
+	got, err := s.GetAllGoldens(context.Background(), &pb.GetAllGoldensRequest{View: pb.GoldenView_GOLDEN_VIEW_BASIC})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if len(got.Goldens) != 1 {
+		t.Fatalf("unexpected response: %+v", got)
+	}
+
+	wantSize, wantHash := domain.ContentDigest("Y29udGVudA==")
+	g := got.Goldens[0]
+	if g.ContentB64 != "" {
+		t.Errorf("ContentB64: want empty, got %q", g.ContentB64)
+	}
+	if g.ContentSize != wantSize {
+		t.Errorf("ContentSize: want %d, got %d", wantSize, g.ContentSize)
+	}
+	if g.ContentHash != wantHash {
+		t.Errorf("ContentHash: want %q, got %q", wantHash, g.ContentHash)
+	}
+}
+
+func TestGoldenServer_GetGoldenById_UnspecifiedViewReturnsContent(t *testing.T) {
+	doc := &domain.Golden{ID: "id-1", ContentB64: "YQ==", UpdatedAt: time.Unix(0, 0).UTC()}
+	s := NewGoldenServer(services.NewGoldenService(&stubRepo{doc: doc}))
+
+	got, err := s.GetGoldenById(context.Background(), &pb.GetGoldenByIdRequest{Id: doc.ID})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if got.Golden.ContentB64 != doc.ContentB64 {
+		t.Errorf("ContentB64: want %q, got %q", doc.ContentB64, got.Golden.ContentB64)
+	}
+	if got.Golden.ContentHash == "" {
+		t.Error("ContentHash: want non-empty hash")
+	}
+}
+
+func TestGoldenServer_ImportGoldens_ReportsCounts(t *testing.T) {
+	s := NewGoldenServer(services.NewGoldenService(&stubRepo{}))
+	stream := &stubImportStream{
+		reqs: []*pb.ImportGoldensRequest{
+			{Golden: &pb.Golden{Id: "id-1", Title: "t1"}, DryRun: true},
+			{Golden: &pb.Golden{Id: "id-2", Title: "t2"}},
+			{Golden: &pb.Golden{Id: "", Title: "no id"}},
+		},
+	}
+
+	if err := s.ImportGoldens(stream); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	got := stream.resp
+	if got == nil {
+		t.Fatal("expected response")
+	}
+	if !got.DryRun {
+		t.Error("expected dry_run taken from the first message")
+	}
+	if got.Created != 1 || got.Updated != 1 || got.Failed != 1 {
+		t.Errorf("unexpected counts created=%d updated=%d failed=%d", got.Created, got.Updated, got.Failed)
+	}
+	if len(got.Results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(got.Results))
+	}
+	if got.Results[2].Status != pb.ImportStatus_IMPORT_STATUS_FAILED || got.Results[2].Error == "" {
+		t.Errorf("expected failed result with error, got %+v", got.Results[2])
+	}
+}
+
+func TestGoldenServer_ImportGoldens_RepositoryErrorMarksFailed(t *testing.T) {
+	s := NewGoldenServer(services.NewGoldenService(&stubRepo{err: errors.New("db down")}))
+	stream := &stubImportStream{
+		reqs: []*pb.ImportGoldensRequest{{Golden: &pb.Golden{Id: "id-1", Title: "t1"}}},
+	}
+
+	if err := s.ImportGoldens(stream); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if stream.resp.Failed != 1 {
+		t.Fatalf("expected 1 failed, got %+v", stream.resp)
+	}
+}
+
+func TestGoldenServer_ExportGoldens_StreamsArchive(t *testing.T) {
+	now := time.Date(2026, 3, 6, 12, 0, 0, 0, time.UTC)
+	repo := &stubRepo{
+		docs: []domain.Golden{
+			{ID: "id-1", Title: "title-1", Tags: []string{"a"}, UpdatedAt: now, ContentB64: "Y29udGVudA=="},
+			{ID: "id-2", Title: "title-2", Tags: []string{"b"}, UpdatedAt: now, ContentB64: "YQ=="},
+		},
+	}
+	s := NewGoldenServer(services.NewGoldenService(repo))
+	stream := &stubExportStream{}
+
+	if err := s.ExportGoldens(&pb.ExportGoldensRequest{}, stream); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if stream.sent == 0 {
+		t.Fatal("expected at least one chunk")
+	}
+
+	var ids []string
+	if err := archive.Read(&stream.data, func(doc domain.Golden) error {
+		ids = append(ids, doc.ID)
+		return nil
+	}); err != nil {
+		t.Fatalf("exported archive unreadable: %v", err)
+	}
+	if len(ids) != 2 || ids[0] != "id-1" || ids[1] != "id-2" {
+		t.Fatalf("unexpected exported ids %v", ids)
+	}
+}
+
+func TestGoldenServer_ExportGoldens_Error(t *testing.T) {
+	s := NewGoldenServer(services.NewGoldenService(&stubRepo{err: errors.New("db down")}))
+
+	err := s.ExportGoldens(&pb.ExportGoldensRequest{}, &stubExportStream{})
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("expected Internal, got %v", status.Code(err))
+	}
+}
+
+func TestGoldenServer_BatchGetGoldens(t *testing.T) {
+	s := NewGoldenServer(services.NewGoldenService(&stubRepo{}), WithBatchGetLimit(3))
+
+	got, err := s.BatchGetGoldens(context.Background(), &pb.BatchGetGoldensRequest{Ids: []string{"b", "missing", "a"}})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if len(got.Goldens) != 2 || got.Goldens[0].Id != "b" || got.Goldens[1].Id != "a" {
+		t.Fatalf("unexpected goldens: %+v", got.Goldens)
+	}
+	if len(got.MissingIds) != 1 || got.MissingIds[0] != "missing" {
+		t.Fatalf("unexpected missing ids: %v", got.MissingIds)
+	}
+
+	_, err = s.BatchGetGoldens(context.Background(), &pb.BatchGetGoldensRequest{Ids: []string{"a", "b", "c", "d"}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument over the limit, got %v", err)
+	}
+}
+
+func TestGoldenServer_ListFacets(t *testing.T) {
+	s := NewGoldenServer(services.NewGoldenService(&stubRepo{}))
+
+	categories, err := s.ListCategories(context.Background(), &pb.ListCategoriesRequest{Tag: "-k8s"})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if len(categories.Categories) != 1 || categories.Categories[0].Value != "DevOps-k8s" || categories.Categories[0].Count != 2 {
+		t.Fatalf("unexpected categories: %+v", categories.Categories)
+	}
+
+	tags, err := s.ListTags(context.Background(), &pb.ListTagsRequest{Category: "-devops"})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if len(tags.Tags) != 1 || tags.Tags[0].Value != "k8s-devops" || tags.Tags[0].Count != 3 {
+		t.Fatalf("unexpected tags: %+v", tags.Tags)
+	}
+
+	failing := NewGoldenServer(services.NewGoldenService(&stubRepo{err: domain.ErrUnavailable}))
+	if _, err := failing.ListTags(context.Background(), &pb.ListTagsRequest{}); status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected Unavailable, got %v", err)
+	}
+}
+
+func TestGoldenServer_CreateGolden(t *testing.T) {
+	server := NewGoldenServer(services.NewGoldenService(&stubRepo{}))
+
+	resp, err := server.CreateGolden(helperEditorContext(), &pb.CreateGoldenRequest{
+		Golden: &pb.Golden{Id: "new", Title: "New", ContentB64: "aGVsbG8="},
+	})
+	if err != nil {
+		t.Fatalf("CreateGolden() error = %v", err)
+	}
+	if resp.Golden.Id != "new" || resp.Golden.ContentHash == "" {
+		t.Errorf("unexpected golden %+v", resp.Golden)
+	}
+	if resp.Golden.UpdatedAt.AsTime().IsZero() {
+		t.Error("expected updated_at to be set by the server")
+	}
+}
+
+func TestGoldenServer_WriteErrorCodes(t *testing.T) {
+	ctx := helperEditorContext()
+	missing := &stubRepo{err: fmt.Errorf("%w: gone", domain.ErrNotFound)}
+
+	tests := []struct {
+		name string
+		call func() error
+		want codes.Code
+	}{
+		{
+			name: "create-invalid",
+			call: func() error {
+				_, err := NewGoldenServer(services.NewGoldenService(&stubRepo{})).CreateGolden(ctx, &pb.CreateGoldenRequest{Golden: &pb.Golden{Id: "x"}})
+				return err
+			},
+			want: codes.InvalidArgument,
+		},
+		{
+			name: "update-invalid",
+			call: func() error {
+				_, err := NewGoldenServer(services.NewGoldenService(&stubRepo{})).UpdateGolden(ctx, &pb.UpdateGoldenRequest{})
+				return err
+			},
+			want: codes.InvalidArgument,
+		},
+		{
+			name: "delete-missing",
+			call: func() error {
+				_, err := NewGoldenServer(services.NewGoldenService(missing)).DeleteGolden(ctx, &pb.DeleteGoldenRequest{Id: "gone"})
+				return err
+			},
+			want: codes.NotFound,
+		},
+		{
+			name: "delete-reader",
+			call: func() error {
+				_, err := NewGoldenServer(services.NewGoldenService(&stubRepo{})).DeleteGolden(context.Background(), &pb.DeleteGoldenRequest{Id: "g"})
+				return err
+			},
+			want: codes.PermissionDenied,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := status.Code(tt.call()); got != tt.want {
+				t.Fatalf("code = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGoldenServer_CreateGolden_GeneratesID(t *testing.T) {
+	server := NewGoldenServer(services.NewGoldenService(&stubRepo{}))
+
+	resp, err := server.CreateGolden(helperEditorContext(), &pb.CreateGoldenRequest{
+		Golden: &pb.Golden{Title: "Día de Muertos"},
+	})
+	if err != nil {
+		t.Fatalf("CreateGolden() error = %v", err)
+	}
+	if len(resp.Golden.Id) != 36 || resp.Golden.Slug != "dia-de-muertos" {
+		t.Errorf("unexpected id/slug %q/%q", resp.Golden.Id, resp.Golden.Slug)
+	}
+}
+
+func TestGoldenServer_GetGoldenBySlug(t *testing.T) {
+	server := NewGoldenServer(services.NewGoldenService(&stubRepo{}))
+
+	resp, err := server.GetGoldenBySlug(context.Background(), &pb.GetGoldenBySlugRequest{Slug: "keptn"})
+	if err != nil {
+		t.Fatalf("GetGoldenBySlug() error = %v", err)
+	}
+	if resp.Golden.Id != "id-keptn" || resp.Golden.Slug != "keptn" {
+		t.Errorf("unexpected golden %+v", resp.Golden)
+	}
+
+	_, err = server.GetGoldenBySlug(context.Background(), &pb.GetGoldenBySlugRequest{})
+	if got := status.Code(err); got != codes.InvalidArgument {
+		t.Errorf("empty slug code = %v, want %v", got, codes.InvalidArgument)
+	}
+
+	missing := NewGoldenServer(services.NewGoldenService(&stubRepo{err: fmt.Errorf("%w: slug gone", domain.ErrNotFound)}))
+	_, err = missing.GetGoldenBySlug(context.Background(), &pb.GetGoldenBySlugRequest{Slug: "gone"})
+	if got := status.Code(err); got != codes.NotFound {
+		t.Errorf("missing slug code = %v, want %v", got, codes.NotFound)
+	}
 }
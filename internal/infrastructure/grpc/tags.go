@@ -0,0 +1,127 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"markitos-it-svc-goldens/internal/domain"
+	pb "markitos-it-svc-goldens/proto/goldens/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var errTagsDisabled = status.Error(codes.Unimplemented, "the tag registry requires the postgres storage backend")
+
+func (s *GoldenServer) ListRegisteredTags(ctx context.Context, req *pb.ListRegisteredTagsRequest) (*pb.ListRegisteredTagsResponse, error) {
+	log.Println("ListRegisteredTags called")
+	if s.tags == nil {
+		return nil, errTagsDisabled
+	}
+
+	tags, err := s.tags.ListRegisteredTags(ctx)
+	if err != nil {
+		log.Printf("Error listing registered tags: %v", err)
+		return nil, tagStatus(err, "failed to list registered tags")
+	}
+
+	resp := &pb.ListRegisteredTagsResponse{}
+	for _, tag := range tags {
+		resp.Tags = append(resp.Tags, registeredTagToProto(&tag))
+	}
+	return resp, nil
+}
+
+func (s *GoldenServer) RegisterTag(ctx context.Context, req *pb.RegisterTagRequest) (*pb.RegisterTagResponse, error) {
+	if s.tags == nil {
+		return nil, errTagsDisabled
+	}
+	if req.Tag == nil {
+		return nil, status.Error(codes.InvalidArgument, "tag is required")
+	}
+	log.Printf("RegisterTag called with name: %q", req.Tag.Name)
+
+	tag := &domain.RegisteredTag{
+		Name:     req.Tag.Name,
+		Synonyms: req.Tag.Synonyms,
+	}
+	if err := s.tags.RegisterTag(ctx, tag); err != nil {
+		log.Printf("Error registering tag %q: %v", req.Tag.Name, err)
+		return nil, tagStatus(err, "failed to register tag")
+	}
+
+	return &pb.RegisterTagResponse{
+		Tag: registeredTagToProto(tag),
+	}, nil
+}
+
+func (s *GoldenServer) UnregisterTag(ctx context.Context, req *pb.UnregisterTagRequest) (*pb.UnregisterTagResponse, error) {
+	log.Printf("UnregisterTag called with name: %s", req.Name)
+	if s.tags == nil {
+		return nil, errTagsDisabled
+	}
+
+	if err := s.tags.UnregisterTag(ctx, req.Name); err != nil {
+		log.Printf("Error unregistering tag %s: %v", req.Name, err)
+		return nil, tagStatus(err, "failed to unregister tag")
+	}
+
+	return &pb.UnregisterTagResponse{}, nil
+}
+
+func (s *GoldenServer) RenameTag(ctx context.Context, req *pb.RenameTagRequest) (*pb.RenameTagResponse, error) {
+	log.Printf("RenameTag called from %q to %q", req.From, req.To)
+	if s.tags == nil {
+		return nil, errTagsDisabled
+	}
+
+	updated, err := s.tags.RenameTag(ctx, req.From, req.To)
+	if err != nil {
+		log.Printf("Error renaming tag %q: %v", req.From, err)
+		return nil, tagStatus(err, "failed to rename tag")
+	}
+
+	return &pb.RenameTagResponse{
+		UpdatedGoldens: int32(updated),
+	}, nil
+}
+
+func (s *GoldenServer) MergeTags(ctx context.Context, req *pb.MergeTagsRequest) (*pb.MergeTagsResponse, error) {
+	log.Printf("MergeTags called with %d sources into %q", len(req.Sources), req.Target)
+	if s.tags == nil {
+		return nil, errTagsDisabled
+	}
+
+	updated, err := s.tags.MergeTags(ctx, req.Sources, req.Target)
+	if err != nil {
+		log.Printf("Error merging tags into %q: %v", req.Target, err)
+		return nil, tagStatus(err, "failed to merge tags")
+	}
+
+	return &pb.MergeTagsResponse{
+		UpdatedGoldens: int32(updated),
+	}, nil
+}
+
+func tagStatus(err error, msg string) error {
+	switch {
+	case errors.Is(err, domain.ErrInvalidTag):
+		return status.Errorf(codes.InvalidArgument, "%s: %v", msg, err)
+	case errors.Is(err, domain.ErrTagNotFound):
+		return status.Errorf(codes.NotFound, "%s: %v", msg, err)
+	case errors.Is(err, domain.ErrTagExists):
+		return status.Errorf(codes.AlreadyExists, "%s: %v", msg, err)
+	default:
+		return errorStatus(err, msg)
+	}
+}
+
+func registeredTagToProto(t *domain.RegisteredTag) *pb.RegisteredTag {
+	return &pb.RegisteredTag{
+		Name:      t.Name,
+		Synonyms:  t.Synonyms,
+		CreatedAt: timestamppb.New(t.CreatedAt),
+	}
+}
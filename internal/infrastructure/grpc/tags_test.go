@@ -0,0 +1,140 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"markitos-it-svc-goldens/internal/application/services"
+	"markitos-it-svc-goldens/internal/domain"
+	pb "markitos-it-svc-goldens/proto/goldens/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// stubTagStore merges tags across the goldens of a stubRepo.
+type stubTagStore struct {
+	repo *stubRepo
+	tags []domain.RegisteredTag
+}
+
+func (s *stubTagStore) ListRegisteredTags(ctx context.Context) ([]domain.RegisteredTag, error) {
+	return s.tags, nil
+}
+
+func (s *stubTagStore) RegisterTag(ctx context.Context, tag *domain.RegisteredTag) error {
+	s.tags = append(s.tags, *tag)
+	return nil
+}
+
+func (s *stubTagStore) UnregisterTag(ctx context.Context, name string) error {
+	return fmt.Errorf("%w: %s", domain.ErrTagNotFound, name)
+}
+
+func (s *stubTagStore) RenameTag(ctx context.Context, from, to string) (int, error) {
+	return 0, fmt.Errorf("%w: %s", domain.ErrTagExists, to)
+}
+
+func (s *stubTagStore) MergeTags(ctx context.Context, sources []string, target string) (int, error) {
+	updated := 0
+	for i, doc := range s.repo.docs {
+		var tags []string
+		for _, tag := range doc.Tags {
+			if slices.Contains(sources, tag) {
+				tag = target
+			}
+			if !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+		if !slices.Equal(tags, doc.Tags) {
+			s.repo.docs[i].Tags = tags
+			updated++
+		}
+	}
+	return updated, nil
+}
+
+func helperTagServer(repo *stubRepo) *GoldenServer {
+	store := &stubTagStore{repo: repo, tags: []domain.RegisteredTag{
+		{Name: "kubernetes", Synonyms: []string{"k8s"}},
+	}}
+	docService := services.NewGoldenService(repo, services.WithTagRegistry(store))
+	return NewGoldenServer(docService, WithTags(services.NewTagService(store)))
+}
+
+func TestGoldenServer_CreateGolden_CanonicalizesTags(t *testing.T) {
+	s := helperTagServer(&stubRepo{})
+
+	resp, err := s.CreateGolden(helperEditorContext(), &pb.CreateGoldenRequest{
+		Golden: &pb.Golden{Title: "Clusters", Tags: []string{"K8s", "Kubernetes", "CI/CD"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fmt.Sprint(resp.Golden.Tags) != "[kubernetes ci-cd]" {
+		t.Fatalf("expected canonical tags, got %v", resp.Golden.Tags)
+	}
+}
+
+func TestGoldenServer_MergeTags_RewritesGoldens(t *testing.T) {
+	repo := &stubRepo{docs: []domain.Golden{
+		{ID: "pipelines", Tags: []string{"CI/CD", "cicd", "go"}},
+		{ID: "keptn", Tags: []string{"ci-cd"}},
+		{ID: "youtube", Tags: []string{"api"}},
+	}}
+	s := helperTagServer(repo)
+
+	resp, err := s.MergeTags(helperEditorContext(), &pb.MergeTagsRequest{Sources: []string{"CI/CD", "cicd"}, Target: "CI-CD"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.UpdatedGoldens != 1 || fmt.Sprint(repo.docs[0].Tags) != "[ci-cd go]" {
+		t.Fatalf("expected one golden merged into ci-cd, got %d: %+v", resp.UpdatedGoldens, repo.docs)
+	}
+}
+
+func TestGoldenServer_TagErrorCodes(t *testing.T) {
+	ctx := helperEditorContext()
+	disabled := NewGoldenServer(services.NewGoldenService(&stubRepo{}))
+
+	tests := []struct {
+		name string
+		call func() error
+		want codes.Code
+	}{
+		{name: "missing-tag", want: codes.InvalidArgument, call: func() error {
+			_, err := helperTagServer(&stubRepo{}).RegisterTag(ctx, &pb.RegisterTagRequest{})
+			return err
+		}},
+		{name: "synonym-spells-name", want: codes.InvalidArgument, call: func() error {
+			_, err := helperTagServer(&stubRepo{}).RegisterTag(ctx, &pb.RegisterTagRequest{Tag: &pb.RegisteredTag{Name: "ci-cd", Synonyms: []string{"CICD"}}})
+			return err
+		}},
+		{name: "unregister-missing", want: codes.NotFound, call: func() error {
+			_, err := helperTagServer(&stubRepo{}).UnregisterTag(ctx, &pb.UnregisterTagRequest{Name: "missing"})
+			return err
+		}},
+		{name: "rename-onto-used", want: codes.AlreadyExists, call: func() error {
+			_, err := helperTagServer(&stubRepo{}).RenameTag(ctx, &pb.RenameTagRequest{From: "k8s", To: "kubernetes"})
+			return err
+		}},
+		{name: "merge-without-sources", want: codes.InvalidArgument, call: func() error {
+			_, err := helperTagServer(&stubRepo{}).MergeTags(ctx, &pb.MergeTagsRequest{Target: "ci-cd"})
+			return err
+		}},
+		{name: "disabled", want: codes.Unimplemented, call: func() error {
+			_, err := disabled.ListRegisteredTags(ctx, &pb.ListRegisteredTagsRequest{})
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := status.Code(tt.call()); got != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
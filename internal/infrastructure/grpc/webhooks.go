@@ -0,0 +1,131 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"markitos-it-svc-goldens/internal/domain"
+	pb "markitos-it-svc-goldens/proto/goldens/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var errWebhooksDisabled = status.Error(codes.Unimplemented, "webhooks require the postgres storage backend")
+
+func (s *GoldenServer) CreateWebhookSubscription(ctx context.Context, req *pb.CreateWebhookSubscriptionRequest) (*pb.CreateWebhookSubscriptionResponse, error) {
+	log.Printf("CreateWebhookSubscription called with url: %s", req.Url)
+	if s.webhooks == nil {
+		return nil, errWebhooksDisabled
+	}
+
+	sub := &domain.WebhookSubscription{
+		URL:        req.Url,
+		Secret:     req.Secret,
+		Categories: req.Categories,
+	}
+	for _, eventType := range req.EventTypes {
+		sub.EventTypes = append(sub.EventTypes, domain.EventType(eventType))
+	}
+
+	if err := s.webhooks.CreateSubscription(ctx, sub); err != nil {
+		log.Printf("Error creating webhook subscription: %v", err)
+		return nil, webhookStatus(err, "failed to create webhook subscription")
+	}
+
+	return &pb.CreateWebhookSubscriptionResponse{
+		Subscription: webhookSubscriptionToProto(sub, true),
+	}, nil
+}
+
+func (s *GoldenServer) ListWebhookSubscriptions(ctx context.Context, req *pb.ListWebhookSubscriptionsRequest) (*pb.ListWebhookSubscriptionsResponse, error) {
+	log.Println("ListWebhookSubscriptions called")
+	if s.webhooks == nil {
+		return nil, errWebhooksDisabled
+	}
+
+	subs, err := s.webhooks.ListSubscriptions(ctx)
+	if err != nil {
+		log.Printf("Error listing webhook subscriptions: %v", err)
+		return nil, webhookStatus(err, "failed to list webhook subscriptions")
+	}
+
+	resp := &pb.ListWebhookSubscriptionsResponse{}
+	for _, sub := range subs {
+		resp.Subscriptions = append(resp.Subscriptions, webhookSubscriptionToProto(&sub, false))
+	}
+	return resp, nil
+}
+
+func (s *GoldenServer) DeleteWebhookSubscription(ctx context.Context, req *pb.DeleteWebhookSubscriptionRequest) (*pb.DeleteWebhookSubscriptionResponse, error) {
+	log.Printf("DeleteWebhookSubscription called with id: %s", req.Id)
+	if s.webhooks == nil {
+		return nil, errWebhooksDisabled
+	}
+
+	if err := s.webhooks.DeleteSubscription(ctx, req.Id); err != nil {
+		log.Printf("Error deleting webhook subscription %s: %v", req.Id, err)
+		return nil, webhookStatus(err, "failed to delete webhook subscription")
+	}
+
+	return &pb.DeleteWebhookSubscriptionResponse{}, nil
+}
+
+func (s *GoldenServer) ListWebhookDeliveries(ctx context.Context, req *pb.ListWebhookDeliveriesRequest) (*pb.ListWebhookDeliveriesResponse, error) {
+	log.Printf("ListWebhookDeliveries called for subscription: %q", req.SubscriptionId)
+	if s.webhooks == nil {
+		return nil, errWebhooksDisabled
+	}
+
+	deliveries, err := s.webhooks.ListDeliveries(ctx, req.SubscriptionId, int(req.Limit))
+	if err != nil {
+		log.Printf("Error listing webhook deliveries: %v", err)
+		return nil, webhookStatus(err, "failed to list webhook deliveries")
+	}
+
+	resp := &pb.ListWebhookDeliveriesResponse{}
+	for _, d := range deliveries {
+		resp.Deliveries = append(resp.Deliveries, &pb.WebhookDelivery{
+			Id:             d.ID,
+			SubscriptionId: d.SubscriptionID,
+			EventId:        d.EventID,
+			EventType:      string(d.EventType),
+			Attempt:        int32(d.Attempt),
+			StatusCode:     int32(d.StatusCode),
+			Error:          d.Error,
+			DurationMs:     d.Duration.Milliseconds(),
+			Succeeded:      d.Succeeded,
+			CreatedAt:      timestamppb.New(d.CreatedAt),
+		})
+	}
+	return resp, nil
+}
+
+func webhookStatus(err error, msg string) error {
+	switch {
+	case errors.Is(err, domain.ErrInvalidWebhook):
+		return status.Errorf(codes.InvalidArgument, "%s: %v", msg, err)
+	case errors.Is(err, domain.ErrWebhookNotFound):
+		return status.Errorf(codes.NotFound, "%s: %v", msg, err)
+	default:
+		return errorStatus(err, msg)
+	}
+}
+
+func webhookSubscriptionToProto(sub *domain.WebhookSubscription, withSecret bool) *pb.WebhookSubscription {
+	res := &pb.WebhookSubscription{
+		Id:         sub.ID,
+		Url:        sub.URL,
+		Categories: sub.Categories,
+		CreatedAt:  timestamppb.New(sub.CreatedAt),
+	}
+	for _, eventType := range sub.EventTypes {
+		res.EventTypes = append(res.EventTypes, string(eventType))
+	}
+	if withSecret {
+		res.Secret = sub.Secret
+	}
+	return res
+}
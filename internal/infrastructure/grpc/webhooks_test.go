@@ -0,0 +1,129 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"markitos-it-svc-goldens/internal/application/services"
+	"markitos-it-svc-goldens/internal/domain"
+	pb "markitos-it-svc-goldens/proto/goldens/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type stubWebhookStore struct {
+	subs []domain.WebhookSubscription
+}
+
+func (s *stubWebhookStore) CreateSubscription(ctx context.Context, sub *domain.WebhookSubscription) error {
+	s.subs = append(s.subs, *sub)
+	return nil
+}
+
+func (s *stubWebhookStore) ListSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	return s.subs, nil
+}
+
+func (s *stubWebhookStore) DeleteSubscription(ctx context.Context, id string) error {
+	return fmt.Errorf("%w: %s", domain.ErrWebhookNotFound, id)
+}
+
+func (s *stubWebhookStore) RecordDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	return nil
+}
+
+func (s *stubWebhookStore) ListDeliveries(ctx context.Context, subscriptionID string, limit int) ([]domain.WebhookDelivery, error) {
+	return []domain.WebhookDelivery{{ID: 1, SubscriptionID: subscriptionID, Attempt: 1, StatusCode: 200, Succeeded: true}}, nil
+}
+
+func (s *stubWebhookStore) ListEventDeliveries(ctx context.Context, eventID string) ([]domain.WebhookDelivery, error) {
+	return nil, nil
+}
+
+func helperWebhookServer() *GoldenServer {
+	webhooks := services.NewWebhookService(&stubWebhookStore{}, nil)
+	return NewGoldenServer(services.NewGoldenService(&stubRepo{}), WithWebhooks(webhooks))
+}
+
+func TestGoldenServer_CreateWebhookSubscription_ReturnsSecretOnce(t *testing.T) {
+	s := helperWebhookServer()
+	ctx := helperEditorContext()
+
+	created, err := s.CreateWebhookSubscription(ctx, &pb.CreateWebhookSubscriptionRequest{
+		Url:        "https://hooks.example.com/goldens",
+		EventTypes: []string{string(domain.EventGoldenCreated)},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.Subscription.Id == "" || created.Subscription.Secret == "" {
+		t.Fatalf("expected generated id and secret, got %+v", created.Subscription)
+	}
+
+	listed, err := s.ListWebhookSubscriptions(ctx, &pb.ListWebhookSubscriptionsRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(listed.Subscriptions) != 1 || listed.Subscriptions[0].Secret != "" {
+		t.Fatalf("expected one subscription without secret, got %+v", listed.Subscriptions)
+	}
+}
+
+func TestGoldenServer_WebhookErrorCodes(t *testing.T) {
+	ctx := helperEditorContext()
+	disabled := NewGoldenServer(services.NewGoldenService(&stubRepo{}))
+
+	tests := []struct {
+		name string
+		call func() error
+		want codes.Code
+	}{
+		{name: "invalid-url", want: codes.InvalidArgument, call: func() error {
+			_, err := helperWebhookServer().CreateWebhookSubscription(ctx, &pb.CreateWebhookSubscriptionRequest{Url: "ftp://x"})
+			return err
+		}},
+		{name: "private-url", want: codes.InvalidArgument, call: func() error {
+			_, err := helperWebhookServer().CreateWebhookSubscription(ctx, &pb.CreateWebhookSubscriptionRequest{Url: "https://169.254.169.254/latest"})
+			return err
+		}},
+		{name: "create-reader", want: codes.PermissionDenied, call: func() error {
+			_, err := helperWebhookServer().CreateWebhookSubscription(context.Background(), &pb.CreateWebhookSubscriptionRequest{Url: "https://hooks.example.com"})
+			return err
+		}},
+		{name: "list-reader", want: codes.PermissionDenied, call: func() error {
+			_, err := helperWebhookServer().ListWebhookSubscriptions(context.Background(), &pb.ListWebhookSubscriptionsRequest{})
+			return err
+		}},
+		{name: "unknown-event-type", want: codes.InvalidArgument, call: func() error {
+			_, err := helperWebhookServer().CreateWebhookSubscription(ctx, &pb.CreateWebhookSubscriptionRequest{Url: "https://x", EventTypes: []string{"Nope"}})
+			return err
+		}},
+		{name: "delete-missing", want: codes.NotFound, call: func() error {
+			_, err := helperWebhookServer().DeleteWebhookSubscription(ctx, &pb.DeleteWebhookSubscriptionRequest{Id: "missing"})
+			return err
+		}},
+		{name: "disabled", want: codes.Unimplemented, call: func() error {
+			_, err := disabled.ListWebhookDeliveries(ctx, &pb.ListWebhookDeliveriesRequest{})
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := status.Code(tt.call()); got != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGoldenServer_ListWebhookDeliveries(t *testing.T) {
+	resp, err := helperWebhookServer().ListWebhookDeliveries(helperEditorContext(), &pb.ListWebhookDeliveriesRequest{SubscriptionId: "sub"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.Deliveries) != 1 || resp.Deliveries[0].SubscriptionId != "sub" || !resp.Deliveries[0].Succeeded {
+		t.Fatalf("unexpected deliveries: %+v", resp.Deliveries)
+	}
+}
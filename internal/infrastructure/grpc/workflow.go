@@ -0,0 +1,86 @@
+package grpc
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"markitos-it-svc-goldens/internal/domain"
+	pb "markitos-it-svc-goldens/proto/goldens/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (s *GoldenServer) TransitionGolden(ctx context.Context, req *pb.TransitionGoldenRequest) (*pb.TransitionGoldenResponse, error) {
+	log.Printf("TransitionGolden called with id: %s, status: %s", req.Id, req.Status)
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	doc, err := s.service.TransitionGolden(ctx, req.Id, statusFromProto(req.Status), req.Reason)
+	if err != nil {
+		log.Printf("Error transitioning golden %s: %v", req.Id, err)
+		return nil, errorStatus(err, "failed to transition golden")
+	}
+
+	return &pb.TransitionGoldenResponse{
+		Golden: goldenToProto(doc, domain.GoldenViewFull),
+	}, nil
+}
+
+func (s *GoldenServer) ListGoldenTransitions(ctx context.Context, req *pb.ListGoldenTransitionsRequest) (*pb.ListGoldenTransitionsResponse, error) {
+	log.Printf("ListGoldenTransitions called with id: %s", req.Id)
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	transitions, err := s.service.ListGoldenTransitions(ctx, req.Id)
+	if err != nil {
+		log.Printf("Error listing transitions of golden %s: %v", req.Id, err)
+		return nil, errorStatus(err, "failed to list golden transitions")
+	}
+
+	resp := &pb.ListGoldenTransitionsResponse{}
+	for _, t := range transitions {
+		resp.Transitions = append(resp.Transitions, &pb.GoldenTransition{
+			From:      statusToProto(t.From),
+			To:        statusToProto(t.To),
+			Reason:    t.Reason,
+			CreatedAt: timestamppb.New(t.At),
+		})
+	}
+	return resp, nil
+}
+
+var goldenStatuses = map[domain.GoldenStatus]pb.GoldenStatus{
+	domain.StatusDraft:     pb.GoldenStatus_GOLDEN_STATUS_DRAFT,
+	domain.StatusInReview:  pb.GoldenStatus_GOLDEN_STATUS_IN_REVIEW,
+	domain.StatusScheduled: pb.GoldenStatus_GOLDEN_STATUS_SCHEDULED,
+	domain.StatusPublished: pb.GoldenStatus_GOLDEN_STATUS_PUBLISHED,
+	domain.StatusArchived:  pb.GoldenStatus_GOLDEN_STATUS_ARCHIVED,
+}
+
+func statusToProto(s domain.GoldenStatus) pb.GoldenStatus {
+	return goldenStatuses[s]
+}
+
+// statusFromProto returns "" for GOLDEN_STATUS_UNSPECIFIED, which
+// domain.Golden.Transition rejects as an invalid status.
+func statusFromProto(s pb.GoldenStatus) domain.GoldenStatus {
+	for status, value := range goldenStatuses {
+		if value == s {
+			return status
+		}
+	}
+	return ""
+}
+
+// optionalTimestamp leaves zero times unset.
+func optionalTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
@@ -0,0 +1,86 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"markitos-it-svc-goldens/internal/application/services"
+	"markitos-it-svc-goldens/internal/domain"
+	pb "markitos-it-svc-goldens/proto/goldens/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGoldenServer_GetAllGoldens_StatusFilter(t *testing.T) {
+	s := NewGoldenServer(services.NewGoldenService(&stubRepo{docs: []domain.Golden{
+		{ID: "draft", Status: domain.StatusDraft},
+		{ID: "published", Status: domain.StatusPublished},
+	}}))
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		status pb.GoldenStatus
+		want   []string
+	}{
+		{name: "reader", ctx: context.Background(), want: []string{"published"}},
+		{name: "reader-asking-for-drafts", ctx: context.Background(), status: pb.GoldenStatus_GOLDEN_STATUS_DRAFT},
+		{name: "editor", ctx: domain.WithEditor(context.Background()), want: []string{"draft", "published"}},
+		{name: "editor-drafts", ctx: domain.WithEditor(context.Background()), status: pb.GoldenStatus_GOLDEN_STATUS_DRAFT, want: []string{"draft"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := s.GetAllGoldens(tt.ctx, &pb.GetAllGoldensRequest{Status: tt.status})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var ids []string
+			for _, doc := range resp.Goldens {
+				ids = append(ids, doc.Id)
+			}
+			if len(ids) != len(tt.want) || (len(ids) > 0 && ids[0] != tt.want[0]) {
+				t.Fatalf("GetAllGoldens() = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
+
+func TestGoldenServer_TransitionGolden(t *testing.T) {
+	repo := &stubRepo{doc: &domain.Golden{ID: "g", Status: domain.StatusInReview, SubmittedAt: time.Unix(60, 0).UTC()}}
+	s := NewGoldenServer(services.NewGoldenService(repo))
+	editor := domain.WithEditor(context.Background())
+
+	resp, err := s.TransitionGolden(editor, &pb.TransitionGoldenRequest{Id: "g", Status: pb.GoldenStatus_GOLDEN_STATUS_PUBLISHED, Reason: "ok"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Golden.Status != pb.GoldenStatus_GOLDEN_STATUS_PUBLISHED || resp.Golden.PublishedAt == nil || resp.Golden.ArchivedAt != nil {
+		t.Fatalf("TransitionGolden() = %+v, want a published golden", resp.Golden)
+	}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		req  *pb.TransitionGoldenRequest
+		want codes.Code
+	}{
+		{name: "missing-id", ctx: editor, req: &pb.TransitionGoldenRequest{Status: pb.GoldenStatus_GOLDEN_STATUS_DRAFT}, want: codes.InvalidArgument},
+		{name: "unspecified-status", ctx: editor, req: &pb.TransitionGoldenRequest{Id: "g"}, want: codes.InvalidArgument},
+		{name: "not-allowed", ctx: editor, req: &pb.TransitionGoldenRequest{Id: "g", Status: pb.GoldenStatus_GOLDEN_STATUS_ARCHIVED}, want: codes.FailedPrecondition},
+		{name: "reader", ctx: context.Background(), req: &pb.TransitionGoldenRequest{Id: "g", Status: pb.GoldenStatus_GOLDEN_STATUS_DRAFT}, want: codes.PermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.TransitionGolden(tt.ctx, tt.req)
+			if got := status.Code(err); got != tt.want {
+				t.Fatalf("expected %v, got %v (%v)", tt.want, got, err)
+			}
+		})
+	}
+
+	if _, err := s.ListGoldenTransitions(context.Background(), &pb.ListGoldenTransitionsRequest{Id: "g"}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("ListGoldenTransitions() for readers: expected PermissionDenied, got %v", err)
+	}
+}
@@ -0,0 +1,238 @@
+package grpcweb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"markitos-it-svc-goldens/internal/infrastructure/rest"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// connectEndStreamFlag marks the JSON message closing a Connect stream.
+const connectEndStreamFlag = 0x02
+
+func init() {
+	// Connect clients may send JSON, which reaches the gRPC server as
+	// application/grpc+json.
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+// jsonCodec is the protojson codec used by Connect for "json" messages.
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string {
+	return "json"
+}
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("json codec: %T is not a proto.Message", v)
+	}
+	return protojson.Marshal(msg)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("json codec: %T is not a proto.Message", v)
+	}
+	return protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, msg)
+}
+
+type connectError struct {
+	Code    string `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type connectEndStream struct {
+	Error    *connectError       `json:"error,omitempty"`
+	Metadata map[string][]string `json:"metadata,omitempty"`
+}
+
+// serveConnectUnary handles a unary Connect call, whose body is the bare
+// request message and whose response is the bare response message or a
+// JSON error.
+func (h *Handler) serveConnectUnary(w http.ResponseWriter, r *http.Request, contentType string) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeConnectError(w, status.New(codes.Unimplemented, "Connect unary calls require POST"))
+		return
+	}
+	if err := checkConnectRequest(r, "Content-Encoding"); err != nil {
+		writeConnectError(w, status.Convert(err))
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxUnaryBytes))
+	if err != nil {
+		writeConnectError(w, status.Newf(codes.ResourceExhausted, "failed to read request: %v", err))
+		return
+	}
+	frame := make([]byte, 5, 5+len(data))
+	binary.BigEndian.PutUint32(frame[1:5], uint32(len(data)))
+	frame = append(frame, data...)
+
+	codec := strings.TrimPrefix(contentType, "application/")
+	bw := &bufferedWriter{header: http.Header{}}
+	h.forward(bw, withGRPCTimeout(r), grpcSubtype("+"+codec), bytes.NewReader(frame))
+
+	if bw.code != 0 && bw.code != http.StatusOK {
+		writeConnectError(w, status.Newf(codes.Internal, "gRPC transport rejected the request with HTTP %d", bw.code))
+		return
+	}
+	st, trailers := grpcStatus(bw.header)
+	copyMetadata(w.Header(), bw.header)
+	for key, values := range trailers {
+		for _, value := range values {
+			w.Header().Add("Trailer-"+key, value)
+		}
+	}
+	if st.Code() != codes.OK {
+		writeConnectError(w, st)
+		return
+	}
+
+	body := bw.body.Bytes()
+	if len(body) < 5 || int(binary.BigEndian.Uint32(body[1:5])) != len(body)-5 {
+		writeConnectError(w, status.New(codes.Internal, "unexpected gRPC response framing"))
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(body[5:])
+}
+
+// serveConnectStream handles application/connect+codec calls, framed as
+// native gRPC messages and closed by an end-of-stream JSON message.
+func (h *Handler) serveConnectStream(w http.ResponseWriter, r *http.Request, contentType string) {
+	sw := newStreamWriter(w, contentType, false)
+	if r.Method != http.MethodPost {
+		sw.writeEndStream(status.New(codes.Unimplemented, "Connect streams require POST"), nil)
+		return
+	}
+	if err := checkConnectRequest(r, "Connect-Content-Encoding"); err != nil {
+		sw.writeEndStream(status.Convert(err), nil)
+		return
+	}
+
+	codec := strings.TrimPrefix(contentType, "application/connect")
+	h.forward(sw, withGRPCTimeout(r), grpcSubtype(codec), r.Body)
+
+	st, trailers := sw.status()
+	sw.writeEndStream(st, trailers)
+}
+
+// checkConnectRequest rejects compressed requests, which are not supported.
+func checkConnectRequest(r *http.Request, encodingHeader string) error {
+	if enc := r.Header.Get(encodingHeader); enc != "" && enc != "identity" {
+		return status.Errorf(codes.Unimplemented, "unsupported %s %q", encodingHeader, enc)
+	}
+	if timeout := r.Header.Get("Connect-Timeout-Ms"); timeout != "" {
+		if _, err := strconv.ParseUint(timeout, 10, 64); err != nil || len(timeout) > 10 {
+			return status.Errorf(codes.InvalidArgument, "invalid Connect-Timeout-Ms %q", timeout)
+		}
+	}
+	return nil
+}
+
+// withGRPCTimeout carries Connect-Timeout-Ms over as grpc-timeout.
+func withGRPCTimeout(r *http.Request) *http.Request {
+	timeout := r.Header.Get("Connect-Timeout-Ms")
+	if timeout == "" {
+		return r
+	}
+	r = r.Clone(r.Context())
+	r.Header.Set("Grpc-Timeout", timeout+"m")
+	return r
+}
+
+func (s *streamWriter) writeEndStream(st *status.Status, trailers metadata.MD) {
+	end := connectEndStream{Metadata: trailers}
+	if st.Code() != codes.OK {
+		end.Error = &connectError{Code: connectCode(st.Code()), Message: st.Message()}
+	}
+	data, err := json.Marshal(end)
+	if err == nil {
+		err = s.writeFrame(connectEndStreamFlag, data)
+	}
+	if err != nil {
+		log.Printf("Error writing Connect end of stream: %v", err)
+	}
+}
+
+func writeConnectError(w http.ResponseWriter, st *status.Status) {
+	data, err := json.Marshal(connectError{Code: connectCode(st.Code()), Message: st.Message()})
+	if err != nil {
+		data = []byte(`{"code":"internal"}`)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(rest.HTTPStatus(st.Code()))
+	w.Write(data)
+}
+
+// connectCode spells code as Connect does, e.g. "not_found".
+func connectCode(code codes.Code) string {
+	if code == codes.OK {
+		return "ok"
+	}
+	var b strings.Builder
+	for i, r := range code.String() {
+		if r >= 'A' && r <= 'Z' {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func copyMetadata(dst, src http.Header) {
+	for key, values := range src {
+		if isResponseMetadata(key) {
+			dst[key] = values
+		}
+	}
+}
+
+// bufferedWriter keeps a unary gRPC response until it is complete.
+type bufferedWriter struct {
+	header http.Header
+	code   int
+	body   bytes.Buffer
+}
+
+func (b *bufferedWriter) Header() http.Header {
+	return b.header
+}
+
+func (b *bufferedWriter) WriteHeader(code int) {
+	if b.code == 0 {
+		b.code = code
+	}
+}
+
+func (b *bufferedWriter) Write(p []byte) (int, error) {
+	b.WriteHeader(http.StatusOK)
+	if b.code != http.StatusOK {
+		return len(p), nil
+	}
+	return b.body.Write(p)
+}
+
+func (b *bufferedWriter) Flush() {}
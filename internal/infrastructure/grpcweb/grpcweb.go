@@ -0,0 +1,61 @@
+package grpcweb
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// base64Encoding is used by grpc-web-text bodies.
+var base64Encoding = base64.StdEncoding
+
+// grpcWebTrailerFlag marks the frame carrying the trailers at the end of a
+// gRPC-Web response.
+const grpcWebTrailerFlag = 0x80
+
+// serveGRPCWeb handles application/grpc-web[-text][+codec], whose messages
+// are framed as in native gRPC but whose trailers travel in the body.
+func (h *Handler) serveGRPCWeb(w http.ResponseWriter, r *http.Request, contentType string) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "gRPC-Web requires POST", http.StatusMethodNotAllowed)
+		return
+	}
+
+	suffix := strings.TrimPrefix(contentType, "application/grpc-web")
+	suffix, text := strings.CutPrefix(suffix, "-text")
+
+	var body io.Reader = r.Body
+	if text {
+		body = base64.NewDecoder(base64Encoding, r.Body)
+	}
+
+	sw := newStreamWriter(w, contentType, text)
+	h.forward(sw, r, grpcSubtype(suffix), body)
+
+	st, trailers := sw.status()
+	block := fmt.Sprintf("grpc-status: %d\r\n", st.Code())
+	if msg := sw.header.Get("Grpc-Message"); msg != "" {
+		block += "grpc-message: " + msg + "\r\n"
+	} else if st.Message() != "" {
+		block += "grpc-message: " + st.Message() + "\r\n"
+	}
+	keys := make([]string, 0, len(trailers))
+	for key := range trailers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		for _, value := range trailers[key] {
+			block += key + ": " + value + "\r\n"
+		}
+	}
+
+	if err := sw.writeFrame(grpcWebTrailerFlag, []byte(block)); err != nil {
+		log.Printf("Error writing gRPC-Web trailers: %v", err)
+	}
+}
@@ -0,0 +1,219 @@
+// Package grpcweb serves the services of a grpc.Server to browsers over the
+// gRPC-Web and Connect protocols, next to native gRPC on the same port.
+//
+// Both protocols are translated to native gRPC requests and served through
+// grpc.Server.ServeHTTP, so interceptors and status codes behave as they do
+// for native clients.
+package grpcweb
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"markitos-it-svc-goldens/internal/infrastructure/rest"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// maxUnaryBytes matches the default gRPC receive limit.
+const maxUnaryBytes = 4 << 20
+
+// Handler routes each request by its Content-Type.
+type Handler struct {
+	server *grpc.Server
+}
+
+func NewHandler(server *grpc.Server) *Handler {
+	return &Handler{server: server}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	contentType, _, _ := strings.Cut(r.Header.Get("Content-Type"), ";")
+	contentType = strings.TrimSpace(strings.ToLower(contentType))
+
+	switch {
+	case strings.HasPrefix(contentType, "application/grpc-web"):
+		h.serveGRPCWeb(w, r, contentType)
+	case strings.HasPrefix(contentType, "application/grpc"):
+		h.server.ServeHTTP(w, r)
+	case strings.HasPrefix(contentType, "application/connect+"):
+		h.serveConnectStream(w, r, contentType)
+	case contentType == "application/proto" || contentType == "application/json":
+		h.serveConnectUnary(w, r, contentType)
+	default:
+		http.Error(w, fmt.Sprintf("unsupported content type %q", contentType), http.StatusUnsupportedMediaType)
+	}
+}
+
+// forward serves r as a native gRPC request with the given content type and
+// body, writing the gRPC response to w.
+func (h *Handler) forward(w http.ResponseWriter, r *http.Request, contentType string, body io.Reader) {
+	req := r.Clone(r.Context())
+	req.Proto, req.ProtoMajor, req.ProtoMinor = "HTTP/2.0", 2, 0
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Del("Content-Length")
+	req.ContentLength = -1
+	req.Body = io.NopCloser(body)
+
+	h.server.ServeHTTP(w, req)
+}
+
+// grpcSubtype turns the codec suffix of a browser content type into the
+// matching native one, e.g. "+json" into "application/grpc+json".
+func grpcSubtype(suffix string) string {
+	if suffix == "" {
+		return "application/grpc"
+	}
+	return "application/grpc" + suffix
+}
+
+// grpcStatus reads the status and trailers that grpc.Server.ServeHTTP set in
+// header once the call has finished.
+func grpcStatus(header http.Header) (*status.Status, metadata.MD) {
+	trailers := metadata.MD{}
+	for key, values := range header {
+		if name, ok := strings.CutPrefix(key, http.TrailerPrefix); ok {
+			trailers.Append(strings.ToLower(name), values...)
+		}
+	}
+
+	value := header.Get("Grpc-Status")
+	if value == "" {
+		return status.New(codes.Unknown, "response without grpc-status"), trailers
+	}
+	code, err := strconv.Atoi(value)
+	if err != nil {
+		return status.Newf(codes.Unknown, "invalid grpc-status %q", value), trailers
+	}
+	return status.New(codes.Code(code), decodeGRPCMessage(header.Get("Grpc-Message"))), trailers
+}
+
+// decodeGRPCMessage undoes the percent-encoding of grpc-message.
+func decodeGRPCMessage(msg string) string {
+	var b strings.Builder
+	for i := 0; i < len(msg); i++ {
+		if msg[i] == '%' && i+2 < len(msg) {
+			if n, err := strconv.ParseUint(msg[i+1:i+3], 16, 8); err == nil {
+				b.WriteByte(byte(n))
+				i += 2
+				continue
+			}
+		}
+		b.WriteByte(msg[i])
+	}
+	return b.String()
+}
+
+// isResponseMetadata reports whether key of a gRPC response header carries
+// metadata set by the handler rather than transport details.
+func isResponseMetadata(key string) bool {
+	switch key {
+	case "Trailer", "Content-Type", "Content-Length", "Date",
+		"Grpc-Status", "Grpc-Message", "Grpc-Status-Details-Bin", "Grpc-Encoding":
+		return false
+	}
+	return !strings.HasPrefix(key, http.TrailerPrefix)
+}
+
+// streamWriter relays a gRPC response body to a browser as it is written,
+// since gRPC-Web and Connect streams use the same length-prefixed messages.
+type streamWriter struct {
+	w           http.ResponseWriter
+	header      http.Header
+	contentType string
+	// text base64-encodes the body, for grpc-web-text.
+	text        bool
+	code        int
+	wroteHeader bool
+}
+
+func newStreamWriter(w http.ResponseWriter, contentType string, text bool) *streamWriter {
+	return &streamWriter{w: w, header: http.Header{}, contentType: contentType, text: text}
+}
+
+func (s *streamWriter) Header() http.Header {
+	return s.header
+}
+
+func (s *streamWriter) WriteHeader(code int) {
+	if s.wroteHeader {
+		return
+	}
+	s.wroteHeader = true
+	s.code = code
+
+	header := s.w.Header()
+	for key, values := range s.header {
+		if isResponseMetadata(key) {
+			header[key] = values
+		}
+	}
+	header.Set("Content-Type", s.contentType)
+	s.w.WriteHeader(http.StatusOK)
+}
+
+// Write drops the plain text errors the gRPC transport writes when it
+// rejects a request, which end up in the final status instead.
+func (s *streamWriter) Write(p []byte) (int, error) {
+	s.WriteHeader(http.StatusOK)
+	if s.code != http.StatusOK {
+		return len(p), nil
+	}
+	if err := s.write(p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (s *streamWriter) Flush() {
+	s.WriteHeader(http.StatusOK)
+	http.NewResponseController(s.w).Flush()
+}
+
+func (s *streamWriter) write(p []byte) error {
+	if s.text {
+		p = []byte(base64Encoding.EncodeToString(p))
+	}
+	_, err := s.w.Write(p)
+	return err
+}
+
+// status returns the outcome of the call once grpc.Server.ServeHTTP returned.
+func (s *streamWriter) status() (*status.Status, metadata.MD) {
+	if s.code != http.StatusOK {
+		return status.Newf(codes.Internal, "gRPC transport rejected the request with HTTP %d", s.code), metadata.MD{}
+	}
+	return grpcStatus(s.header)
+}
+
+// writeFrame writes a length-prefixed message with the given flags.
+func (s *streamWriter) writeFrame(flags byte, data []byte) error {
+	s.WriteHeader(http.StatusOK)
+	frame := make([]byte, 5+len(data))
+	frame[0] = flags
+	binary.BigEndian.PutUint32(frame[1:5], uint32(len(data)))
+	copy(frame[5:], data)
+	if err := s.write(frame); err != nil {
+		return err
+	}
+	http.NewResponseController(s.w).Flush()
+	return nil
+}
+
+// CORSConfig adds the headers browsers send and read in gRPC-Web and Connect
+// calls to config.
+func CORSConfig(config rest.CORSConfig) rest.CORSConfig {
+	config.AllowedHeaders = append(slices.Clone(config.AllowedHeaders),
+		"X-Grpc-Web", "X-User-Agent", "Grpc-Timeout", "Connect-Protocol-Version", "Connect-Timeout-Ms")
+	config.ExposedHeaders = append(slices.Clone(config.ExposedHeaders),
+		"Grpc-Status", "Grpc-Message", "Grpc-Status-Details-Bin")
+	return config
+}
@@ -0,0 +1,254 @@
+package grpcweb
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	pb "markitos-it-svc-goldens/proto/goldens/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+type stubServer struct {
+	pb.UnimplementedGoldenServiceServer
+}
+
+func (stubServer) GetGoldenById(ctx context.Context, req *pb.GetGoldenByIdRequest) (*pb.GetGoldenByIdResponse, error) {
+	if req.Id == "missing" {
+		return nil, status.Error(codes.NotFound, "golden not found: missing")
+	}
+	grpc.SetHeader(ctx, metadata.Pairs("x-served-by", "stub"))
+	grpc.SetTrailer(ctx, metadata.Pairs("x-trailer", "done"))
+	return &pb.GetGoldenByIdResponse{Golden: &pb.Golden{Id: req.Id, Title: "Keptn"}}, nil
+}
+
+func (stubServer) ExportGoldens(_ *pb.ExportGoldensRequest, stream pb.GoldenService_ExportGoldensServer) error {
+	for _, chunk := range []string{"first", "second"} {
+		if err := stream.Send(&pb.ExportGoldensResponse{Data: []byte(chunk)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func helperHandler(t *testing.T) *Handler {
+	t.Helper()
+	server := grpc.NewServer()
+	pb.RegisterGoldenServiceServer(server, stubServer{})
+	t.Cleanup(server.Stop)
+	return NewHandler(server)
+}
+
+func helperFrame(t *testing.T, flags byte, msg []byte) []byte {
+	t.Helper()
+	frame := make([]byte, 5, 5+len(msg))
+	frame[0] = flags
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(msg)))
+	return append(frame, msg...)
+}
+
+func helperMarshal(t *testing.T, msg proto.Message) []byte {
+	t.Helper()
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		t.Fatalf("proto.Marshal() error = %v", err)
+	}
+	return data
+}
+
+type frame struct {
+	flags byte
+	data  []byte
+}
+
+func helperFrames(t *testing.T, body []byte) []frame {
+	t.Helper()
+	var frames []frame
+	for len(body) > 0 {
+		if len(body) < 5 {
+			t.Fatalf("truncated frame header %x", body)
+		}
+		n := binary.BigEndian.Uint32(body[1:5])
+		if len(body) < 5+int(n) {
+			t.Fatalf("truncated frame of %d bytes", n)
+		}
+		frames = append(frames, frame{flags: body[0], data: body[5 : 5+n]})
+		body = body[5+n:]
+	}
+	return frames
+}
+
+func helperPost(t *testing.T, h http.Handler, path, contentType string, body []byte) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandler_GRPCWeb(t *testing.T) {
+	h := helperHandler(t)
+	body := helperFrame(t, 0, helperMarshal(t, &pb.GetGoldenByIdRequest{Id: "keptn"}))
+	rec := helperPost(t, h, pb.GoldenService_GetGoldenById_FullMethodName, "application/grpc-web+proto", body)
+
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/grpc-web+proto" {
+		t.Fatalf("status = %d, headers = %v", rec.Code, rec.Header())
+	}
+	if rec.Header().Get("X-Served-By") != "stub" {
+		t.Fatalf("missing response metadata in %v", rec.Header())
+	}
+
+	frames := helperFrames(t, rec.Body.Bytes())
+	if len(frames) != 2 || frames[0].flags != 0 || frames[1].flags != grpcWebTrailerFlag {
+		t.Fatalf("frames = %+v", frames)
+	}
+	var resp pb.GetGoldenByIdResponse
+	if err := proto.Unmarshal(frames[0].data, &resp); err != nil || resp.Golden.GetTitle() != "Keptn" {
+		t.Fatalf("response = %v, %v", &resp, err)
+	}
+	trailers := string(frames[1].data)
+	if !strings.Contains(trailers, "grpc-status: 0\r\n") || !strings.Contains(trailers, "x-trailer: done\r\n") {
+		t.Fatalf("trailers = %q", trailers)
+	}
+}
+
+func TestHandler_GRPCWebTextError(t *testing.T) {
+	h := helperHandler(t)
+	body := helperFrame(t, 0, helperMarshal(t, &pb.GetGoldenByIdRequest{Id: "missing"}))
+	rec := helperPost(t, h, pb.GoldenService_GetGoldenById_FullMethodName, "application/grpc-web-text", []byte(base64.StdEncoding.EncodeToString(body)))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(rec.Body.String())
+	if err != nil {
+		t.Fatalf("invalid base64 body %q: %v", rec.Body, err)
+	}
+	frames := helperFrames(t, decoded)
+	if len(frames) != 1 || frames[0].flags != grpcWebTrailerFlag {
+		t.Fatalf("frames = %+v", frames)
+	}
+	trailers := string(frames[0].data)
+	if !strings.Contains(trailers, "grpc-status: 5\r\n") || !strings.Contains(trailers, "grpc-message: golden not found: missing\r\n") {
+		t.Fatalf("trailers = %q", trailers)
+	}
+}
+
+func TestHandler_ConnectUnary(t *testing.T) {
+	h := helperHandler(t)
+	rec := helperPost(t, h, pb.GoldenService_GetGoldenById_FullMethodName, "application/proto", helperMarshal(t, &pb.GetGoldenByIdRequest{Id: "keptn"}))
+
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/proto" {
+		t.Fatalf("status = %d, headers = %v", rec.Code, rec.Header())
+	}
+	if rec.Header().Get("Trailer-X-Trailer") != "done" {
+		t.Fatalf("missing trailer in %v", rec.Header())
+	}
+	var resp pb.GetGoldenByIdResponse
+	if err := proto.Unmarshal(rec.Body.Bytes(), &resp); err != nil || resp.Golden.GetId() != "keptn" {
+		t.Fatalf("response = %v, %v", &resp, err)
+	}
+}
+
+func TestHandler_ConnectUnaryJSON(t *testing.T) {
+	h := helperHandler(t)
+	rec := helperPost(t, h, pb.GoldenService_GetGoldenById_FullMethodName, "application/json", []byte(`{"id": "keptn"}`))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+	var resp struct {
+		Golden struct {
+			ID    string `json:"id"`
+			Title string `json:"title"`
+		} `json:"golden"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || resp.Golden.Title != "Keptn" {
+		t.Fatalf("response %s: %v", rec.Body, err)
+	}
+
+	rec = helperPost(t, h, pb.GoldenService_GetGoldenById_FullMethodName, "application/json", []byte(`{"id": "missing"}`))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+	var connectErr connectError
+	if err := json.Unmarshal(rec.Body.Bytes(), &connectErr); err != nil || connectErr.Code != "not_found" || connectErr.Message != "golden not found: missing" {
+		t.Fatalf("error = %s, %v", rec.Body, err)
+	}
+}
+
+func TestHandler_ConnectServerStream(t *testing.T) {
+	h := helperHandler(t)
+	body := helperFrame(t, 0, helperMarshal(t, &pb.ExportGoldensRequest{}))
+	rec := helperPost(t, h, pb.GoldenService_ExportGoldens_FullMethodName, "application/connect+proto", body)
+
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/connect+proto" {
+		t.Fatalf("status = %d, headers = %v", rec.Code, rec.Header())
+	}
+	frames := helperFrames(t, rec.Body.Bytes())
+	if len(frames) != 3 || frames[2].flags != connectEndStreamFlag {
+		t.Fatalf("frames = %+v", frames)
+	}
+	for i, want := range []string{"first", "second"} {
+		var chunk pb.ExportGoldensResponse
+		if err := proto.Unmarshal(frames[i].data, &chunk); err != nil || string(chunk.Data) != want {
+			t.Fatalf("chunk %d = %q, %v", i, chunk.Data, err)
+		}
+	}
+	if string(frames[2].data) != "{}" {
+		t.Fatalf("end of stream = %s", frames[2].data)
+	}
+}
+
+func TestHandler_UnsupportedContentType(t *testing.T) {
+	rec := helperPost(t, helperHandler(t), "/", "text/plain", nil)
+	if rec.Code != http.StatusUnsupportedMediaType {
+		t.Fatalf("status = %d", rec.Code)
+	}
+}
+
+func TestHandler_NativeGRPCOverH2C(t *testing.T) {
+	srv := httptest.NewUnstartedServer(helperHandler(t))
+	srv.Config.Protocols = new(http.Protocols)
+	srv.Config.Protocols.SetHTTP1(true)
+	srv.Config.Protocols.SetUnencryptedHTTP2(true)
+	srv.Start()
+	defer srv.Close()
+
+	conn, err := grpc.NewClient(strings.TrimPrefix(srv.URL, "http://"), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("grpc.NewClient() error = %v", err)
+	}
+	defer conn.Close()
+
+	resp, err := pb.NewGoldenServiceClient(conn).GetGoldenById(context.Background(), &pb.GetGoldenByIdRequest{Id: "keptn"})
+	if err != nil || resp.Golden.GetTitle() != "Keptn" {
+		t.Fatalf("GetGoldenById() = %v, %v", resp, err)
+	}
+}
+
+func TestConnectCode(t *testing.T) {
+	for code, want := range map[codes.Code]string{
+		codes.NotFound:         "not_found",
+		codes.DeadlineExceeded: "deadline_exceeded",
+		codes.Canceled:         "canceled",
+		codes.Unavailable:      "unavailable",
+	} {
+		if got := connectCode(code); got != want {
+			t.Errorf("connectCode(%v) = %q, want %q", code, got, want)
+		}
+	}
+}
@@ -0,0 +1,304 @@
+// Package markdown converts goldens to and from markdown documents with a
+// YAML front matter header. Only the YAML subset written by Encode is
+// understood: scalar keys and string lists, in flow or block style.
+package markdown
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"markitos-it-svc-goldens/internal/domain"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const delimiter = "---"
+
+var ErrMissingFrontMatter = errors.New("missing front matter")
+
+// FileName returns the file name used to store a golden, escaping any path
+// separators in its id.
+func FileName(id string) string {
+	return url.PathEscape(id) + ".md"
+}
+
+// Encode renders doc as markdown. The body is the decoded ContentB64.
+func Encode(doc domain.Golden) ([]byte, error) {
+	body, err := base64.StdEncoding.DecodeString(doc.ContentB64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode content of golden %s: %w", doc.ID, err)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(delimiter + "\n")
+	writeScalar(&buf, "id", doc.ID)
+	if doc.Slug != "" {
+		writeScalar(&buf, "slug", doc.Slug)
+	}
+	writeScalar(&buf, "title", doc.Title)
+	writeScalar(&buf, "description", doc.Description)
+	writeScalar(&buf, "category", doc.Category)
+	if len(doc.Tags) == 0 {
+		buf.WriteString("tags: []\n")
+	} else {
+		buf.WriteString("tags:\n")
+		for _, tag := range doc.Tags {
+			buf.WriteString("  - " + strconv.Quote(tag) + "\n")
+		}
+	}
+	writeScalar(&buf, "cover_image", doc.CoverImage)
+	writeTime(&buf, "updated_at", doc.UpdatedAt)
+	if doc.Status != "" {
+		writeScalar(&buf, "status", string(doc.Status))
+	}
+	writeTime(&buf, "submitted_at", doc.SubmittedAt)
+	writeTime(&buf, "published_at", doc.PublishedAt)
+	writeTime(&buf, "archived_at", doc.ArchivedAt)
+	writeTime(&buf, "publish_at", doc.PublishAt)
+	writeTime(&buf, "expire_at", doc.ExpireAt)
+	buf.WriteString(delimiter + "\n")
+	buf.Write(body)
+
+	return buf.Bytes(), nil
+}
+
+func writeScalar(buf *bytes.Buffer, key, value string) {
+	buf.WriteString(key + ": " + strconv.Quote(value) + "\n")
+}
+
+// writeTime leaves zero times out of the header.
+func writeTime(buf *bytes.Buffer, key string, t time.Time) {
+	if !t.IsZero() {
+		buf.WriteString(key + ": " + t.UTC().Format(time.RFC3339Nano) + "\n")
+	}
+}
+
+// Decode parses a markdown document produced by Encode (or written by hand
+// following the same layout). The body is returned base64 encoded.
+func Decode(data []byte) (domain.Golden, error) {
+	var doc domain.Golden
+
+	header, body, err := split(data)
+	if err != nil {
+		return doc, err
+	}
+
+	fields, err := parseFrontMatter(header)
+	if err != nil {
+		return doc, err
+	}
+
+	for key, value := range fields {
+		switch key {
+		case "id":
+			doc.ID, err = value.scalar(key)
+		case "slug":
+			doc.Slug, err = value.scalar(key)
+		case "title":
+			doc.Title, err = value.scalar(key)
+		case "description":
+			doc.Description, err = value.scalar(key)
+		case "category":
+			doc.Category, err = value.scalar(key)
+		case "cover_image":
+			doc.CoverImage, err = value.scalar(key)
+		case "tags":
+			doc.Tags = value.list
+			if value.isScalar && value.text != "" {
+				doc.Tags = []string{value.text}
+			}
+		case "status":
+			var raw string
+			raw, err = value.scalar(key)
+			doc.Status = domain.GoldenStatus(raw)
+		case "updated_at":
+			doc.UpdatedAt, err = value.time(key)
+		case "submitted_at":
+			doc.SubmittedAt, err = value.time(key)
+		case "published_at":
+			doc.PublishedAt, err = value.time(key)
+		case "archived_at":
+			doc.ArchivedAt, err = value.time(key)
+		case "publish_at":
+			doc.PublishAt, err = value.time(key)
+		case "expire_at":
+			doc.ExpireAt, err = value.time(key)
+		}
+		if err != nil {
+			return domain.Golden{}, fmt.Errorf("invalid front matter key %q: %w", key, err)
+		}
+	}
+
+	doc.ContentB64 = base64.StdEncoding.EncodeToString(body)
+	return doc, nil
+}
+
+func split(data []byte) ([]string, []byte, error) {
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+	reader := bufio.NewReader(bytes.NewReader(data))
+
+	first, err := reader.ReadString('\n')
+	if strings.TrimRight(first, "\r\n") != delimiter {
+		return nil, nil, ErrMissingFrontMatter
+	}
+	if err != nil {
+		return nil, nil, ErrMissingFrontMatter
+	}
+
+	offset := len(first)
+	var header []string
+	for {
+		line, err := reader.ReadString('\n')
+		offset += len(line)
+		trimmed := strings.TrimRight(line, "\r\n")
+		if trimmed == delimiter {
+			return header, data[offset:], nil
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: unterminated header", ErrMissingFrontMatter)
+		}
+		header = append(header, trimmed)
+	}
+}
+
+type value struct {
+	text     string
+	list     []string
+	isScalar bool
+}
+
+func (v value) scalar(key string) (string, error) {
+	if !v.isScalar {
+		return "", fmt.Errorf("expected a scalar for %s", key)
+	}
+	return v.text, nil
+}
+
+// time parses an RFC 3339 timestamp; an empty value is the zero time.
+func (v value) time(key string) (time.Time, error) {
+	raw, err := v.scalar(key)
+	if err != nil || raw == "" {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC3339Nano, raw)
+}
+
+func parseFrontMatter(lines []string) (map[string]value, error) {
+	fields := make(map[string]value)
+	current := ""
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "- ") || trimmed == "-" {
+			if current == "" {
+				return nil, fmt.Errorf("line %d: list item without key", i+2)
+			}
+			item, err := unquote(strings.TrimSpace(strings.TrimPrefix(trimmed, "-")))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+2, err)
+			}
+			field := fields[current]
+			field.list = append(field.list, item)
+			field.isScalar = false
+			fields[current] = field
+			continue
+		}
+
+		key, raw, found := strings.Cut(trimmed, ":")
+		if !found {
+			return nil, fmt.Errorf("line %d: expected key: value", i+2)
+		}
+		key = strings.TrimSpace(key)
+		raw = strings.TrimSpace(raw)
+		current = key
+
+		switch {
+		case raw == "":
+			fields[key] = value{isScalar: true}
+		case strings.HasPrefix(raw, "["):
+			items, err := parseFlowList(raw)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+2, err)
+			}
+			fields[key] = value{list: items}
+		default:
+			text, err := unquote(raw)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+2, err)
+			}
+			fields[key] = value{text: text, isScalar: true}
+		}
+	}
+
+	return fields, nil
+}
+
+func parseFlowList(raw string) ([]string, error) {
+	if !strings.HasSuffix(raw, "]") {
+		return nil, errors.New("unterminated flow list")
+	}
+	inner := strings.TrimSpace(raw[1 : len(raw)-1])
+	if inner == "" {
+		return []string{}, nil
+	}
+
+	var items []string
+	var current strings.Builder
+	var quote byte
+	for i := 0; i < len(inner); i++ {
+		c := inner[i]
+		switch {
+		case quote != 0:
+			current.WriteByte(c)
+			if c == '\\' && quote == '"' && i+1 < len(inner) {
+				i++
+				current.WriteByte(inner[i])
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+			current.WriteByte(c)
+		case c == ',':
+			item, err := unquote(strings.TrimSpace(current.String()))
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+			current.Reset()
+		default:
+			current.WriteByte(c)
+		}
+	}
+
+	item, err := unquote(strings.TrimSpace(current.String()))
+	if err != nil {
+		return nil, err
+	}
+	return append(items, item), nil
+}
+
+func unquote(raw string) (string, error) {
+	switch {
+	case strings.HasPrefix(raw, `"`):
+		return strconv.Unquote(raw)
+	case strings.HasPrefix(raw, "'"):
+		if len(raw) < 2 || !strings.HasSuffix(raw, "'") {
+			return "", fmt.Errorf("unterminated string %s", raw)
+		}
+		return strings.ReplaceAll(raw[1:len(raw)-1], "''", "'"), nil
+	default:
+		if i := strings.Index(raw, " #"); i >= 0 {
+			raw = strings.TrimSpace(raw[:i])
+		}
+		return raw, nil
+	}
+}
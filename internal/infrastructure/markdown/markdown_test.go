@@ -0,0 +1,131 @@
+package markdown
+
+import (
+	"encoding/base64"
+	"errors"
+	"markitos-it-svc-goldens/internal/domain"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func helperRandomGolden(t *testing.T) domain.Golden {
+	t.Helper()
+
+	prefix := domain.HelperRandomAlphaPrefix(t, 8)
+	return domain.Golden{
+		ID:          prefix + "-golden-id",
+		Slug:        prefix + "-slug",
+		Title:       prefix + ": \"quoted\" title",
+		Description: prefix + "-line one\nline two",
+		Category:    prefix + "-category",
+		Tags:        []string{prefix + "-go", prefix + ", grpc"},
+		UpdatedAt:   time.Date(2026, 3, 6, 12, 0, 0, 0, time.UTC),
+		ContentB64:  base64.StdEncoding.EncodeToString([]byte("# " + prefix + "\n\n---\nbody\n")),
+		CoverImage:  "https://example.com/" + prefix + "/cover.png",
+		Status:      domain.StatusArchived,
+		SubmittedAt: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC),
+		PublishedAt: time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC),
+		ArchivedAt:  time.Date(2026, 3, 6, 12, 0, 0, 0, time.UTC),
+		ExpireAt:    time.Date(2026, 3, 6, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestEncodeDecode_RoundTrip(t *testing.T) {
+	doc := helperRandomGolden(t)
+
+	data, err := Encode(doc)
+	if err != nil {
+		t.Fatalf("Encode() unexpected error: %v", err)
+	}
+	got, err := Decode(data)
+	if err != nil {
+		t.Fatalf("Decode() unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, doc) {
+		t.Fatalf("round trip mismatch:\nwant %+v\ngot  %+v", doc, got)
+	}
+}
+
+func TestEncode_InvalidContent(t *testing.T) {
+	doc := helperRandomGolden(t)
+	doc.ContentB64 = "%%%"
+
+	if _, err := Encode(doc); err == nil {
+		t.Fatal("Encode() expected error for invalid base64 content")
+	}
+}
+
+func TestDecode_HandWrittenFrontMatter(t *testing.T) {
+	data := "---\n" +
+		"# written by hand\n" +
+		"id: hand-written\n" +
+		"title: 'It''s a title'\n" +
+		"category: DevOps # trailing comment\n" +
+		"tags: [keptn, \"ci-cd\", 'k8s']\n" +
+		"unknown: ignored\n" +
+		"---\n" +
+		"# Body\n"
+
+	got, err := Decode([]byte(data))
+	if err != nil {
+		t.Fatalf("Decode() unexpected error: %v", err)
+	}
+
+	if got.ID != "hand-written" {
+		t.Errorf("ID: want hand-written, got %q", got.ID)
+	}
+	if got.Title != "It's a title" {
+		t.Errorf("Title: want %q, got %q", "It's a title", got.Title)
+	}
+	if got.Category != "DevOps" {
+		t.Errorf("Category: want DevOps, got %q", got.Category)
+	}
+	if want := []string{"keptn", "ci-cd", "k8s"}; !reflect.DeepEqual(got.Tags, want) {
+		t.Errorf("Tags: want %v, got %v", want, got.Tags)
+	}
+	if want := base64.StdEncoding.EncodeToString([]byte("# Body\n")); got.ContentB64 != want {
+		t.Errorf("ContentB64: want %q, got %q", want, got.ContentB64)
+	}
+}
+
+func TestDecode_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		wantMissing bool
+	}{
+		{name: "no-front-matter", data: "# Just markdown\n", wantMissing: true},
+		{name: "unterminated-header", data: "---\nid: x\n", wantMissing: true},
+		{name: "list-item-without-key", data: "---\n- orphan\n---\n"},
+		{name: "line-without-colon", data: "---\nnot yaml\n---\n"},
+		{name: "list-for-scalar-key", data: "---\ntitle: [a, b]\n---\n"},
+		{name: "invalid-updated-at", data: "---\nupdated_at: yesterday\n---\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode([]byte(tt.data))
+			if err == nil {
+				t.Fatal("Decode() expected error")
+			}
+			if errors.Is(err, ErrMissingFrontMatter) != tt.wantMissing {
+				t.Fatalf("Decode() error = %v, want ErrMissingFrontMatter %v", err, tt.wantMissing)
+			}
+		})
+	}
+}
+
+func TestEncode_EmptyTagsAsFlowList(t *testing.T) {
+	doc := helperRandomGolden(t)
+	doc.Tags = nil
+
+	data, err := Encode(doc)
+	if err != nil {
+		t.Fatalf("Encode() unexpected error: %v", err)
+	}
+	if !strings.Contains(string(data), "\ntags: []\n") {
+		t.Fatalf("expected empty flow list for tags, got:\n%s", data)
+	}
+}
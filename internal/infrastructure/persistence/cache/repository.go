@@ -0,0 +1,431 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"markitos-it-svc-goldens/internal/domain"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type Config struct {
+	TTL      time.Duration
+	MaxBytes int64
+	// LoadTimeout bounds a read shared by concurrent misses, which outlives
+	// the callers that give up on it; zero means DefaultLoadTimeout.
+	LoadTimeout time.Duration
+}
+
+const DefaultLoadTimeout = 10 * time.Second
+
+type Stats struct {
+	Hits      int64
+	Misses    int64
+	Evictions int64
+	Entries   int
+	Bytes     int64
+}
+
+type entry struct {
+	key       string
+	value     any
+	size      int64
+	expiresAt time.Time
+}
+
+// GoldenRepository is a read-through domain.Repository decorator keeping
+// GetByID and GetAll results in an LRU bounded by TTL and total size.
+// Entries are shared by every caller, so they are read with editor rights
+// and hold goldens whatever their status; the service filters them.
+type GoldenRepository struct {
+	next   domain.Repository
+	config Config
+	now    func() time.Time
+
+	mu         sync.Mutex
+	lru        *list.List
+	items      map[string]*list.Element
+	bytes      int64
+	generation uint64
+
+	group     flightGroup
+	hits      atomic.Int64
+	misses    atomic.Int64
+	evictions atomic.Int64
+}
+
+func NewGoldenRepository(next domain.Repository, config Config) *GoldenRepository {
+	return &GoldenRepository{
+		next:   next,
+		config: config,
+		now:    time.Now,
+		lru:    list.New(),
+		items:  make(map[string]*list.Element),
+	}
+}
+
+func (r *GoldenRepository) Stats() Stats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return Stats{
+		Hits:      r.hits.Load(),
+		Misses:    r.misses.Load(),
+		Evictions: r.evictions.Load(),
+		Entries:   r.lru.Len(),
+		Bytes:     r.bytes,
+	}
+}
+
+func (r *GoldenRepository) GetAll(ctx context.Context, view domain.GoldenView) ([]domain.Golden, error) {
+	value, err := r.load(ctx, listKey(view), func(ctx context.Context) (any, int64, error) {
+		docs, err := r.next.GetAll(domain.WithEditor(ctx), view)
+		if err != nil {
+			return nil, 0, err
+		}
+		var size int64
+		for i := range docs {
+			size += goldenSize(&docs[i])
+		}
+		return docs, size, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cloneGoldens(value.([]domain.Golden)), nil
+}
+
+func (r *GoldenRepository) GetByID(ctx context.Context, id string, view domain.GoldenView) (*domain.Golden, error) {
+	value, err := r.load(ctx, idKey(id, view), func(ctx context.Context) (any, int64, error) {
+		doc, err := r.next.GetByID(domain.WithEditor(ctx), id, view)
+		if err != nil {
+			return nil, 0, err
+		}
+		return doc, goldenSize(doc), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	doc := cloneGolden(*value.(*domain.Golden))
+	return &doc, nil
+}
+
+// GetByIDs serves the cached ids and reads the others with a single call to
+// the next repository, caching them as GetByID would.
+func (r *GoldenRepository) GetByIDs(ctx context.Context, ids []string, view domain.GoldenView) ([]domain.Golden, error) {
+	docs := make([]domain.Golden, 0, len(ids))
+	var uncached []string
+	for _, id := range ids {
+		if value, ok := r.get(idKey(id, view)); ok {
+			r.hits.Add(1)
+			docs = append(docs, cloneGolden(*value.(*domain.Golden)))
+			continue
+		}
+		r.misses.Add(1)
+		uncached = append(uncached, id)
+	}
+	if len(uncached) == 0 {
+		return docs, nil
+	}
+
+	r.mu.Lock()
+	generation := r.generation
+	r.mu.Unlock()
+
+	fetched, err := r.next.GetByIDs(domain.WithEditor(ctx), uncached, view)
+	if err != nil {
+		return nil, err
+	}
+	for i := range fetched {
+		doc := cloneGolden(fetched[i])
+		r.set(idKey(doc.ID, view), &doc, goldenSize(&doc), generation)
+		docs = append(docs, fetched[i])
+	}
+
+	return docs, nil
+}
+
+// GetByCategories is read through uncached, as every subtree would need its
+// own list entry.
+func (r *GoldenRepository) GetByCategories(ctx context.Context, categories []string, view domain.GoldenView) ([]domain.Golden, error) {
+	return r.next.GetByCategories(ctx, categories, view)
+}
+
+// GetBySlug is not cached: renames change which golden a slug resolves to,
+// while invalidations are keyed by id.
+func (r *GoldenRepository) GetBySlug(ctx context.Context, slug string, view domain.GoldenView) (*domain.Golden, error) {
+	return r.next.GetBySlug(ctx, slug, view)
+}
+
+func (r *GoldenRepository) SlugsInUse(ctx context.Context, base string) ([]string, error) {
+	return r.next.SlugsInUse(ctx, base)
+}
+
+// Facet counts are read through uncached.
+func (r *GoldenRepository) CountCategories(ctx context.Context, tag string) ([]domain.FacetCount, error) {
+	return r.next.CountCategories(ctx, tag)
+}
+
+func (r *GoldenRepository) CountTags(ctx context.Context, categories []string) ([]domain.FacetCount, error) {
+	return r.next.CountTags(ctx, categories)
+}
+
+func (r *GoldenRepository) Create(ctx context.Context, doc *domain.Golden) error {
+	defer r.Invalidate(doc.ID)
+	return r.next.Create(ctx, doc)
+}
+
+func (r *GoldenRepository) Update(ctx context.Context, doc *domain.Golden) error {
+	defer r.Invalidate(doc.ID)
+	return r.next.Update(ctx, doc)
+}
+
+func (r *GoldenRepository) Delete(ctx context.Context, id string) error {
+	defer r.Invalidate(id)
+	return r.next.Delete(ctx, id)
+}
+
+func (r *GoldenRepository) Upsert(ctx context.Context, docs []domain.Golden, dryRun bool) ([]domain.ImportResult, error) {
+	if !dryRun {
+		ids := make([]string, 0, len(docs))
+		for _, doc := range docs {
+			ids = append(ids, doc.ID)
+		}
+		defer r.Invalidate(ids...)
+	}
+	return r.next.Upsert(ctx, docs, dryRun)
+}
+
+func (r *GoldenRepository) AppendEvents(ctx context.Context, events ...domain.Event) error {
+	return r.next.AppendEvents(ctx, events...)
+}
+
+func (r *GoldenRepository) AppendTransition(ctx context.Context, transition domain.GoldenTransition) error {
+	return r.next.AppendTransition(ctx, transition)
+}
+
+func (r *GoldenRepository) ListTransitions(ctx context.Context, id string) ([]domain.GoldenTransition, error) {
+	return r.next.ListTransitions(ctx, id)
+}
+
+// RunInTx passes the next repository's transaction to fn uncached. The goldens
+// written through it are invalidated once the transaction has finished, since
+// other readers could re-cache the old rows until the commit.
+func (r *GoldenRepository) RunInTx(ctx context.Context, fn func(tx domain.Repository) error, opts ...domain.TxOption) error {
+	var written []string
+	defer func() {
+		if len(written) > 0 {
+			r.Invalidate(written...)
+		}
+	}()
+
+	return r.next.RunInTx(ctx, func(tx domain.Repository) error {
+		return fn(&txRepository{Repository: tx, written: &written})
+	}, opts...)
+}
+
+// txRepository records the ids written inside a transaction.
+type txRepository struct {
+	domain.Repository
+	written *[]string
+}
+
+func (t *txRepository) Create(ctx context.Context, doc *domain.Golden) error {
+	*t.written = append(*t.written, doc.ID)
+	return t.Repository.Create(ctx, doc)
+}
+
+func (t *txRepository) Update(ctx context.Context, doc *domain.Golden) error {
+	*t.written = append(*t.written, doc.ID)
+	return t.Repository.Update(ctx, doc)
+}
+
+func (t *txRepository) Delete(ctx context.Context, id string) error {
+	*t.written = append(*t.written, id)
+	return t.Repository.Delete(ctx, id)
+}
+
+func (t *txRepository) Upsert(ctx context.Context, docs []domain.Golden, dryRun bool) ([]domain.ImportResult, error) {
+	if !dryRun {
+		for _, doc := range docs {
+			*t.written = append(*t.written, doc.ID)
+		}
+	}
+	return t.Repository.Upsert(ctx, docs, dryRun)
+}
+
+func (t *txRepository) RunInTx(ctx context.Context, fn func(tx domain.Repository) error, opts ...domain.TxOption) error {
+	return t.Repository.RunInTx(ctx, func(tx domain.Repository) error {
+		return fn(&txRepository{Repository: tx, written: t.written})
+	}, opts...)
+}
+
+// Invalidate drops the cached entries of ids and every cached list.
+func (r *GoldenRepository) Invalidate(ids ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.generation++
+	for _, id := range ids {
+		for _, view := range []domain.GoldenView{domain.GoldenViewFull, domain.GoldenViewBasic} {
+			r.removeKey(idKey(id, view))
+		}
+	}
+	for _, view := range []domain.GoldenView{domain.GoldenViewFull, domain.GoldenViewBasic} {
+		r.removeKey(listKey(view))
+	}
+}
+
+// Flush drops every cached entry.
+func (r *GoldenRepository) Flush() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.generation++
+	r.lru.Init()
+	clear(r.items)
+	r.bytes = 0
+}
+
+// load returns the cached value for key or calls fetch once for all
+// concurrent callers. Results fetched while an invalidation happened are
+// returned but not stored, so a stale read never outlives the write.
+//
+// The shared fetch is not cancelled with the caller that started it, only by
+// LoadTimeout; each caller stops waiting when its own ctx is done.
+func (r *GoldenRepository) load(ctx context.Context, key string, fetch func(ctx context.Context) (any, int64, error)) (any, error) {
+	if value, ok := r.get(key); ok {
+		r.hits.Add(1)
+		return value, nil
+	}
+	r.misses.Add(1)
+
+	c := r.group.do(key, func() (any, error) {
+		// A load that finished just before this one may have filled the key.
+		if value, ok := r.get(key); ok {
+			return value, nil
+		}
+
+		r.mu.Lock()
+		generation := r.generation
+		r.mu.Unlock()
+
+		timeout := r.config.LoadTimeout
+		if timeout <= 0 {
+			timeout = DefaultLoadTimeout
+		}
+		fetchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+		defer cancel()
+
+		value, size, err := fetch(fetchCtx)
+		if err != nil {
+			return nil, err
+		}
+		r.set(key, value, size, generation)
+		return value, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-c.done:
+	}
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	return c.val, nil
+}
+
+func (r *GoldenRepository) get(key string) (any, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	element, ok := r.items[key]
+	if !ok {
+		return nil, false
+	}
+	e := element.Value.(*entry)
+	if !r.now().Before(e.expiresAt) {
+		r.remove(element)
+		return nil, false
+	}
+
+	r.lru.MoveToFront(element)
+	return e.value, true
+}
+
+func (r *GoldenRepository) set(key string, value any, size int64, generation uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if generation != r.generation || size > r.config.MaxBytes {
+		return
+	}
+
+	r.removeKey(key)
+	r.items[key] = r.lru.PushFront(&entry{
+		key:       key,
+		value:     value,
+		size:      size,
+		expiresAt: r.now().Add(r.config.TTL),
+	})
+	r.bytes += size
+
+	for r.bytes > r.config.MaxBytes {
+		r.remove(r.lru.Back())
+		r.evictions.Add(1)
+	}
+}
+
+func (r *GoldenRepository) removeKey(key string) {
+	if element, ok := r.items[key]; ok {
+		r.remove(element)
+	}
+}
+
+func (r *GoldenRepository) remove(element *list.Element) {
+	e := element.Value.(*entry)
+	r.lru.Remove(element)
+	delete(r.items, e.key)
+	r.bytes -= e.size
+}
+
+func idKey(id string, view domain.GoldenView) string {
+	return fmt.Sprintf("id:%d:%s", view, id)
+}
+
+func listKey(view domain.GoldenView) string {
+	return fmt.Sprintf("all:%d", view)
+}
+
+// goldenSize approximates the memory held by doc.
+func goldenSize(doc *domain.Golden) int64 {
+	size := int64(len(doc.ID) + len(doc.Title) + len(doc.Description) + len(doc.Category) +
+		len(doc.ContentB64) + len(doc.CoverImage) + len(doc.ContentHash))
+	for _, tag := range doc.Tags {
+		size += int64(len(tag))
+	}
+	return size
+}
+
+func cloneGolden(doc domain.Golden) domain.Golden {
+	doc.Tags = slices.Clone(doc.Tags)
+	return doc
+}
+
+func cloneGoldens(docs []domain.Golden) []domain.Golden {
+	if docs == nil {
+		return nil
+	}
+	out := make([]domain.Golden, len(docs))
+	for i, doc := range docs {
+		out[i] = cloneGolden(doc)
+	}
+	return out
+}
@@ -0,0 +1,477 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"markitos-it-svc-goldens/internal/domain"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingRepo counts reads and can block them to exercise coalescing.
+type countingRepo struct {
+	getAll   atomic.Int32
+	getByID  atomic.Int32
+	getByIDs atomic.Int32
+	release  chan struct{}
+	err      error
+	docs     map[string]domain.Golden
+}
+
+func newCountingRepo(docs ...domain.Golden) *countingRepo {
+	r := &countingRepo{docs: make(map[string]domain.Golden)}
+	for _, doc := range docs {
+		r.docs[doc.ID] = doc
+	}
+	return r
+}
+
+func (r *countingRepo) GetAll(ctx context.Context, view domain.GoldenView) ([]domain.Golden, error) {
+	r.getAll.Add(1)
+	if r.err != nil {
+		return nil, r.err
+	}
+	var docs []domain.Golden
+	for _, doc := range r.docs {
+		docs = append(docs, doc.WithView(view))
+	}
+	return docs, nil
+}
+
+func (r *countingRepo) GetByID(ctx context.Context, id string, view domain.GoldenView) (*domain.Golden, error) {
+	r.getByID.Add(1)
+	if r.release != nil {
+		select {
+		case <-r.release:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	doc, ok := r.docs[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", domain.ErrNotFound, id)
+	}
+	doc = doc.WithView(view)
+	return &doc, nil
+}
+
+func (r *countingRepo) GetByIDs(ctx context.Context, ids []string, view domain.GoldenView) ([]domain.Golden, error) {
+	r.getByIDs.Add(1)
+	if r.err != nil {
+		return nil, r.err
+	}
+	var docs []domain.Golden
+	for _, id := range ids {
+		if doc, ok := r.docs[id]; ok {
+			docs = append(docs, doc.WithView(view))
+		}
+	}
+	return docs, nil
+}
+
+func (r *countingRepo) GetByCategories(ctx context.Context, categories []string, view domain.GoldenView) ([]domain.Golden, error) {
+	return nil, r.err
+}
+
+func (r *countingRepo) GetBySlug(ctx context.Context, slug string, view domain.GoldenView) (*domain.Golden, error) {
+	for _, doc := range r.docs {
+		if doc.Slug == slug {
+			doc = doc.WithView(view)
+			return &doc, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: slug %s", domain.ErrNotFound, slug)
+}
+
+func (r *countingRepo) SlugsInUse(ctx context.Context, base string) ([]string, error) {
+	return nil, nil
+}
+
+func (r *countingRepo) CountCategories(ctx context.Context, tag string) ([]domain.FacetCount, error) {
+	return nil, nil
+}
+
+func (r *countingRepo) CountTags(ctx context.Context, categories []string) ([]domain.FacetCount, error) {
+	return nil, nil
+}
+
+func (r *countingRepo) Create(ctx context.Context, doc *domain.Golden) error {
+	r.docs[doc.ID] = *doc
+	return nil
+}
+
+func (r *countingRepo) Update(ctx context.Context, doc *domain.Golden) error {
+	r.docs[doc.ID] = *doc
+	return nil
+}
+
+func (r *countingRepo) Delete(ctx context.Context, id string) error {
+	delete(r.docs, id)
+	return nil
+}
+
+func (r *countingRepo) Upsert(ctx context.Context, docs []domain.Golden, dryRun bool) ([]domain.ImportResult, error) {
+	for _, doc := range docs {
+		if !dryRun {
+			r.docs[doc.ID] = doc
+		}
+	}
+	return nil, nil
+}
+
+func (r *countingRepo) AppendEvents(ctx context.Context, events ...domain.Event) error {
+	return nil
+}
+
+func (r *countingRepo) AppendTransition(ctx context.Context, transition domain.GoldenTransition) error {
+	return nil
+}
+
+func (r *countingRepo) ListTransitions(ctx context.Context, id string) ([]domain.GoldenTransition, error) {
+	return nil, nil
+}
+
+func (r *countingRepo) RunInTx(ctx context.Context, fn func(tx domain.Repository) error, opts ...domain.TxOption) error {
+	return fn(r)
+}
+
+func helperGolden(t *testing.T, contentLen int) domain.Golden {
+	t.Helper()
+
+	prefix := domain.HelperRandomAlphaPrefix(t, 8)
+	return domain.Golden{ID: prefix, Title: prefix + "-title", Tags: []string{"go"}, ContentB64: strings.Repeat("a", contentLen)}
+}
+
+func helperConfig() Config {
+	return Config{TTL: time.Minute, MaxBytes: 1 << 20}
+}
+
+func TestGoldenRepository_GetByID_CachesPerView(t *testing.T) {
+	doc := helperGolden(t, 16)
+	next := newCountingRepo(doc)
+	r := NewGoldenRepository(next, helperConfig())
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if _, err := r.GetByID(ctx, doc.ID, domain.GoldenViewFull); err != nil {
+			t.Fatalf("GetByID() unexpected error: %v", err)
+		}
+	}
+	basic, err := r.GetByID(ctx, doc.ID, domain.GoldenViewBasic)
+	if err != nil {
+		t.Fatalf("GetByID() unexpected error: %v", err)
+	}
+	if basic.ContentB64 != "" {
+		t.Fatalf("expected basic view from its own cache entry, got content %q", basic.ContentB64)
+	}
+
+	if got := next.getByID.Load(); got != 2 {
+		t.Fatalf("expected 2 backend reads, got %d", got)
+	}
+	stats := r.Stats()
+	if stats.Hits != 2 || stats.Misses != 2 || stats.Entries != 2 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
+
+func TestGoldenRepository_GetByIDs_SharesGetByIDEntries(t *testing.T) {
+	cached, uncached := helperGolden(t, 4), helperGolden(t, 4)
+	next := newCountingRepo(cached, uncached)
+	r := NewGoldenRepository(next, helperConfig())
+	ctx := context.Background()
+
+	if _, err := r.GetByID(ctx, cached.ID, domain.GoldenViewFull); err != nil {
+		t.Fatalf("GetByID() unexpected error: %v", err)
+	}
+	docs, err := r.GetByIDs(ctx, []string{cached.ID, uncached.ID, "missing"}, domain.GoldenViewFull)
+	if err != nil {
+		t.Fatalf("GetByIDs() unexpected error: %v", err)
+	}
+	if len(docs) != 2 {
+		t.Fatalf("expected 2 goldens, got %+v", docs)
+	}
+	if _, err := r.GetByID(ctx, uncached.ID, domain.GoldenViewFull); err != nil {
+		t.Fatalf("GetByID() unexpected error: %v", err)
+	}
+
+	if got := next.getByIDs.Load(); got != 1 {
+		t.Fatalf("expected 1 batch read, got %d", got)
+	}
+	if got := next.getByID.Load(); got != 1 {
+		t.Fatalf("expected the batch to fill the cache for GetByID, got %d reads", got)
+	}
+}
+
+func TestGoldenRepository_ReturnsCopies(t *testing.T) {
+	doc := helperGolden(t, 4)
+	r := NewGoldenRepository(newCountingRepo(doc), helperConfig())
+	ctx := context.Background()
+
+	got, _ := r.GetByID(ctx, doc.ID, domain.GoldenViewFull)
+	got.Title = "mutated"
+	got.Tags[0] = "mutated"
+
+	again, _ := r.GetByID(ctx, doc.ID, domain.GoldenViewFull)
+	if again.Title != doc.Title || again.Tags[0] != "go" {
+		t.Fatalf("cached golden was mutated through a returned value: %+v", again)
+	}
+}
+
+func TestGoldenRepository_DoesNotCacheErrors(t *testing.T) {
+	next := newCountingRepo()
+	next.err = errors.New("db down")
+	r := NewGoldenRepository(next, helperConfig())
+
+	for i := 0; i < 2; i++ {
+		if _, err := r.GetAll(context.Background(), domain.GoldenViewFull); err == nil {
+			t.Fatal("GetAll() expected error")
+		}
+	}
+	if got := next.getAll.Load(); got != 2 {
+		t.Fatalf("expected errors to reach the backend every time, got %d reads", got)
+	}
+}
+
+func TestGoldenRepository_ExpiresAfterTTL(t *testing.T) {
+	doc := helperGolden(t, 4)
+	next := newCountingRepo(doc)
+	r := NewGoldenRepository(next, helperConfig())
+	now := time.Date(2026, 3, 6, 12, 0, 0, 0, time.UTC)
+	r.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	r.GetAll(ctx, domain.GoldenViewBasic)
+	now = now.Add(30 * time.Second)
+	r.GetAll(ctx, domain.GoldenViewBasic)
+	now = now.Add(31 * time.Second)
+	r.GetAll(ctx, domain.GoldenViewBasic)
+
+	if got := next.getAll.Load(); got != 2 {
+		t.Fatalf("expected reload after TTL, got %d reads", got)
+	}
+}
+
+func TestGoldenRepository_EvictsLeastRecentlyUsedOverMaxBytes(t *testing.T) {
+	first := helperGolden(t, 400)
+	second := helperGolden(t, 400)
+	third := helperGolden(t, 400)
+	next := newCountingRepo(first, second, third)
+	r := NewGoldenRepository(next, Config{TTL: time.Minute, MaxBytes: 1000})
+	ctx := context.Background()
+
+	r.GetByID(ctx, first.ID, domain.GoldenViewFull)
+	r.GetByID(ctx, second.ID, domain.GoldenViewFull)
+	r.GetByID(ctx, first.ID, domain.GoldenViewFull)
+	r.GetByID(ctx, third.ID, domain.GoldenViewFull)
+
+	stats := r.Stats()
+	if stats.Evictions != 1 || stats.Entries != 2 || stats.Bytes > 1000 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+
+	before := next.getByID.Load()
+	r.GetByID(ctx, first.ID, domain.GoldenViewFull)
+	if next.getByID.Load() != before {
+		t.Fatal("expected recently used golden to survive eviction")
+	}
+	r.GetByID(ctx, second.ID, domain.GoldenViewFull)
+	if next.getByID.Load() != before+1 {
+		t.Fatal("expected least recently used golden to be evicted")
+	}
+}
+
+func TestGoldenRepository_SkipsEntriesLargerThanMaxBytes(t *testing.T) {
+	doc := helperGolden(t, 2000)
+	next := newCountingRepo(doc)
+	r := NewGoldenRepository(next, Config{TTL: time.Minute, MaxBytes: 1000})
+
+	r.GetByID(context.Background(), doc.ID, domain.GoldenViewFull)
+	if stats := r.Stats(); stats.Entries != 0 {
+		t.Fatalf("expected oversized golden not to be cached, got %+v", stats)
+	}
+}
+
+func TestGoldenRepository_WritesInvalidate(t *testing.T) {
+	doc := helperGolden(t, 4)
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		write func(r *GoldenRepository) error
+	}{
+		{name: "create", write: func(r *GoldenRepository) error { return r.Create(ctx, &domain.Golden{ID: "other"}) }},
+		{name: "update", write: func(r *GoldenRepository) error {
+			updated := doc
+			updated.Title = "updated"
+			return r.Update(ctx, &updated)
+		}},
+		{name: "delete", write: func(r *GoldenRepository) error { return r.Delete(ctx, doc.ID) }},
+		{name: "upsert", write: func(r *GoldenRepository) error {
+			_, err := r.Upsert(ctx, []domain.Golden{doc}, false)
+			return err
+		}},
+		{name: "update-in-tx", write: func(r *GoldenRepository) error {
+			return r.RunInTx(ctx, func(tx domain.Repository) error {
+				updated := doc
+				updated.Title = "updated"
+				return tx.Update(ctx, &updated)
+			})
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := newCountingRepo(doc)
+			r := NewGoldenRepository(next, helperConfig())
+			r.GetAll(ctx, domain.GoldenViewFull)
+			r.GetByID(ctx, doc.ID, domain.GoldenViewFull)
+
+			if err := tt.write(r); err != nil {
+				t.Fatalf("write unexpected error: %v", err)
+			}
+
+			r.GetAll(ctx, domain.GoldenViewFull)
+			if next.getAll.Load() != 2 {
+				t.Fatalf("expected list to be reloaded after %s", tt.name)
+			}
+		})
+	}
+}
+
+func TestGoldenRepository_DryRunUpsertKeepsCache(t *testing.T) {
+	doc := helperGolden(t, 4)
+	next := newCountingRepo(doc)
+	r := NewGoldenRepository(next, helperConfig())
+	ctx := context.Background()
+
+	r.GetAll(ctx, domain.GoldenViewFull)
+	r.Upsert(ctx, []domain.Golden{doc}, true)
+	r.GetAll(ctx, domain.GoldenViewFull)
+
+	if got := next.getAll.Load(); got != 1 {
+		t.Fatalf("expected dry run not to invalidate, got %d reads", got)
+	}
+}
+
+func TestGoldenRepository_Flush(t *testing.T) {
+	doc := helperGolden(t, 4)
+	r := NewGoldenRepository(newCountingRepo(doc), helperConfig())
+	r.GetByID(context.Background(), doc.ID, domain.GoldenViewFull)
+
+	r.Flush()
+	if stats := r.Stats(); stats.Entries != 0 || stats.Bytes != 0 {
+		t.Fatalf("expected empty cache after flush, got %+v", stats)
+	}
+}
+
+func TestGoldenRepository_CoalescesConcurrentMisses(t *testing.T) {
+	doc := helperGolden(t, 4)
+	next := newCountingRepo(doc)
+	next.release = make(chan struct{})
+	r := NewGoldenRepository(next, helperConfig())
+
+	const callers = 8
+	var wg sync.WaitGroup
+	wg.Add(callers)
+	for i := 0; i < callers; i++ {
+		go func() {
+			defer wg.Done()
+			if _, err := r.GetByID(context.Background(), doc.ID, domain.GoldenViewFull); err != nil {
+				t.Errorf("GetByID() unexpected error: %v", err)
+			}
+		}()
+	}
+
+	// Wait until every caller missed before letting the single load finish.
+	deadline := time.Now().Add(2 * time.Second)
+	for r.Stats().Misses < callers && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	close(next.release)
+	wg.Wait()
+
+	if got := next.getByID.Load(); got != 1 {
+		t.Fatalf("expected one backend read, got %d", got)
+	}
+}
+
+func TestGoldenRepository_CancelledCallerLeavesSharedLoad(t *testing.T) {
+	doc := helperGolden(t, 4)
+	next := newCountingRepo(doc)
+	next.release = make(chan struct{})
+	r := NewGoldenRepository(next, helperConfig())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error)
+	go func() {
+		_, err := r.GetByID(ctx, doc.ID, domain.GoldenViewFull)
+		first <- err
+	}()
+	for next.getByID.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	second := make(chan error)
+	go func() {
+		_, err := r.GetByID(context.Background(), doc.ID, domain.GoldenViewFull)
+		second <- err
+	}()
+	for r.Stats().Misses < 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Fatalf("GetByID() with a cancelled context: expected context.Canceled, got %v", err)
+	}
+	close(next.release)
+	if err := <-second; err != nil {
+		t.Fatalf("GetByID() sharing the load unexpected error: %v", err)
+	}
+	if got := next.getByID.Load(); got != 1 {
+		t.Fatalf("expected one backend read, got %d", got)
+	}
+}
+
+func TestGoldenRepository_SharedLoadTimesOut(t *testing.T) {
+	doc := helperGolden(t, 4)
+	next := newCountingRepo(doc)
+	next.release = make(chan struct{})
+	defer close(next.release)
+	config := helperConfig()
+	config.LoadTimeout = 10 * time.Millisecond
+	r := NewGoldenRepository(next, config)
+
+	if _, err := r.GetByID(context.Background(), doc.ID, domain.GoldenViewFull); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("GetByID() past LoadTimeout: expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestGoldenRepository_InvalidationDuringLoadIsNotCached(t *testing.T) {
+	doc := helperGolden(t, 4)
+	next := newCountingRepo(doc)
+	next.release = make(chan struct{})
+	r := NewGoldenRepository(next, helperConfig())
+
+	done := make(chan struct{})
+	go func() {
+		r.GetByID(context.Background(), doc.ID, domain.GoldenViewFull)
+		close(done)
+	}()
+	for next.getByID.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	r.Invalidate(doc.ID)
+	close(next.release)
+	<-done
+
+	if stats := r.Stats(); stats.Entries != 0 {
+		t.Fatalf("expected stale load to be discarded, got %+v", stats)
+	}
+}
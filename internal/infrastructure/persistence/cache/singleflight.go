@@ -0,0 +1,41 @@
+package cache
+
+import "sync"
+
+type call struct {
+	done chan struct{}
+	val  any
+	err  error
+}
+
+// flightGroup coalesces concurrent loads of the same key into one call.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*call
+}
+
+// do starts fn in its own goroutine unless a call for key is in flight, and
+// returns the call to wait on, so that each caller can stop waiting without
+// cancelling the others.
+func (g *flightGroup) do(key string, fn func() (any, error)) *call {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.calls == nil {
+		g.calls = make(map[string]*call)
+	}
+	if c, ok := g.calls[key]; ok {
+		return c
+	}
+
+	c := &call{done: make(chan struct{})}
+	g.calls[key] = c
+	go func() {
+		defer close(c.done)
+		c.val, c.err = fn()
+
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+	}()
+	return c
+}
@@ -0,0 +1,478 @@
+package filesystem
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log"
+	"markitos-it-svc-goldens/internal/domain"
+	"markitos-it-svc-goldens/internal/infrastructure/markdown"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrReadOnly = fmt.Errorf("%w: filesystem repository", domain.ErrReadOnly)
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+	id      string
+}
+
+// GoldenRepository serves a directory tree of markdown files with front
+// matter. The directory is indexed in memory and kept fresh by Watch.
+type GoldenRepository struct {
+	root     string
+	writable bool
+
+	mu    sync.RWMutex
+	docs  map[string]domain.Golden
+	paths map[string]string
+	files map[string]fileState
+}
+
+func NewGoldenRepository(root string, writable bool) *GoldenRepository {
+	return &GoldenRepository{
+		root:     root,
+		writable: writable,
+		docs:     make(map[string]domain.Golden),
+		paths:    make(map[string]string),
+		files:    make(map[string]fileState),
+	}
+}
+
+// Load scans the directory, re-reading only the files that changed since the
+// previous scan. When several files hold the same id, the first one in path
+// order wins.
+func (r *GoldenRepository) Load() error {
+	current := make(map[string]fs.FileInfo)
+	err := filepath.WalkDir(r.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.EqualFold(filepath.Ext(path), ".md") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		current[path] = info
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scan %s: %w", r.root, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for path, state := range r.files {
+		if _, ok := current[path]; !ok {
+			r.forget(path, state)
+		}
+	}
+
+	for path, info := range current {
+		state, known := r.files[path]
+		if known && (!state.modTime.Equal(info.ModTime()) || state.size != info.Size()) {
+			r.forget(path, state)
+		}
+	}
+
+	// Changed files are read in path order, after forgetting them all, so
+	// the files they shadowed are read again too.
+	paths := make([]string, 0, len(current))
+	for path := range current {
+		if _, known := r.files[path]; !known {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		info := current[path]
+		doc, err := readFile(path, info)
+		if err != nil {
+			log.Printf("⚠️  Skipping %s: %v", path, err)
+			continue
+		}
+		r.files[path] = fileState{modTime: info.ModTime(), size: info.Size(), id: doc.ID}
+		if other, ok := r.paths[doc.ID]; ok && other != path {
+			if other < path {
+				log.Printf("⚠️  Golden %s in %s is shadowed by %s", doc.ID, path, other)
+				continue
+			}
+			log.Printf("⚠️  Golden %s in %s shadows %s", doc.ID, path, other)
+		}
+
+		r.docs[doc.ID] = doc
+		r.paths[doc.ID] = path
+	}
+
+	return nil
+}
+
+// forget drops path from the index. When it held the golden of its id, the
+// files it shadowed are forgotten too so that the next Load reads them again.
+func (r *GoldenRepository) forget(path string, state fileState) {
+	delete(r.files, path)
+	if r.paths[state.id] != path {
+		return
+	}
+
+	delete(r.docs, state.id)
+	delete(r.paths, state.id)
+	for other, otherState := range r.files {
+		if otherState.id == state.id {
+			delete(r.files, other)
+		}
+	}
+}
+
+// Watch polls the directory every interval until ctx is done.
+func (r *GoldenRepository) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.Load(); err != nil {
+				log.Printf("⚠️  Failed to reload goldens: %v", err)
+			}
+		}
+	}
+}
+
+func readFile(path string, info fs.FileInfo) (domain.Golden, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return domain.Golden{}, err
+	}
+
+	doc, err := markdown.Decode(data)
+	if err != nil {
+		return domain.Golden{}, err
+	}
+	if doc.ID == "" {
+		doc.ID = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		if unescaped, err := url.PathUnescape(doc.ID); err == nil {
+			doc.ID = unescaped
+		}
+	}
+	if doc.UpdatedAt.IsZero() {
+		doc.UpdatedAt = info.ModTime().UTC()
+	}
+	if doc.Slug == "" {
+		doc.Slug = doc.BaseSlug()
+	}
+	// Hand-written files are live content unless they say otherwise.
+	if doc.Status == "" {
+		doc.Status = domain.StatusPublished
+	}
+	doc.ContentSize, doc.ContentHash = domain.ContentDigest(doc.ContentB64)
+
+	return doc, nil
+}
+
+func (r *GoldenRepository) GetAll(ctx context.Context, view domain.GoldenView) ([]domain.Golden, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var docs []domain.Golden
+	for _, doc := range r.docs {
+		docs = append(docs, doc.WithView(view))
+	}
+	sort.Slice(docs, func(i, j int) bool {
+		return docs[i].UpdatedAt.After(docs[j].UpdatedAt)
+	})
+
+	return docs, nil
+}
+
+func (r *GoldenRepository) GetByID(ctx context.Context, id string, view domain.GoldenView) (*domain.Golden, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	doc, ok := r.docs[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", domain.ErrNotFound, id)
+	}
+
+	doc = doc.WithView(view)
+	return &doc, nil
+}
+
+func (r *GoldenRepository) GetByIDs(ctx context.Context, ids []string, view domain.GoldenView) ([]domain.Golden, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var docs []domain.Golden
+	for _, id := range ids {
+		if doc, ok := r.docs[id]; ok {
+			docs = append(docs, doc.WithView(view))
+		}
+	}
+
+	return docs, nil
+}
+
+func (r *GoldenRepository) GetByCategories(ctx context.Context, categories []string, view domain.GoldenView) ([]domain.Golden, error) {
+	docs := r.filter(func(doc domain.Golden) bool {
+		return slices.Contains(categories, doc.Category)
+	})
+	for i := range docs {
+		docs[i] = docs[i].WithView(view)
+	}
+	sort.Slice(docs, func(i, j int) bool {
+		return docs[i].UpdatedAt.After(docs[j].UpdatedAt)
+	})
+
+	return docs, nil
+}
+
+// GetBySlug matches current slugs only: renamed files leave no redirects.
+// Files without a slug in their front matter get one derived from the title,
+// and when several files claim the same slug the smallest id wins.
+func (r *GoldenRepository) GetBySlug(ctx context.Context, slug string, view domain.GoldenView) (*domain.Golden, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var found *domain.Golden
+	for _, doc := range r.docs {
+		if doc.Slug == slug && (found == nil || doc.ID < found.ID) {
+			found = &doc
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("%w: slug %s", domain.ErrNotFound, slug)
+	}
+
+	doc := found.WithView(view)
+	return &doc, nil
+}
+
+func (r *GoldenRepository) SlugsInUse(ctx context.Context, base string) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var slugs []string
+	for _, doc := range r.docs {
+		if doc.Slug == base || strings.HasPrefix(doc.Slug, base+"-") {
+			slugs = append(slugs, doc.Slug)
+		}
+	}
+
+	return slugs, nil
+}
+
+func (r *GoldenRepository) CountCategories(ctx context.Context, tag string) ([]domain.FacetCount, error) {
+	now := time.Now()
+	docs := r.filter(func(doc domain.Golden) bool {
+		return doc.Live(now) && (tag == "" || slices.Contains(doc.Tags, tag))
+	})
+	return domain.CountFacets(docs, func(doc domain.Golden) []string { return []string{doc.Category} }), nil
+}
+
+func (r *GoldenRepository) CountTags(ctx context.Context, categories []string) ([]domain.FacetCount, error) {
+	now := time.Now()
+	docs := r.filter(func(doc domain.Golden) bool {
+		return doc.Live(now) && (len(categories) == 0 || slices.Contains(categories, doc.Category))
+	})
+	return domain.CountFacets(docs, func(doc domain.Golden) []string { return doc.Tags }), nil
+}
+
+// filter returns the loaded goldens matching keep.
+func (r *GoldenRepository) filter(keep func(doc domain.Golden) bool) []domain.Golden {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var docs []domain.Golden
+	for _, doc := range r.docs {
+		if keep(doc) {
+			docs = append(docs, doc)
+		}
+	}
+	return docs
+}
+
+func (r *GoldenRepository) Create(ctx context.Context, doc *domain.Golden) error {
+	if !r.writable {
+		return ErrReadOnly
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.docs[doc.ID]; ok {
+		return fmt.Errorf("%w: %s", domain.ErrAlreadyExists, doc.ID)
+	}
+	if doc.Status == "" {
+		doc.Status = domain.StatusDraft
+	}
+
+	return r.write(filepath.Join(r.root, markdown.FileName(doc.ID)), doc)
+}
+
+func (r *GoldenRepository) Update(ctx context.Context, doc *domain.Golden) error {
+	if !r.writable {
+		return ErrReadOnly
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	path, ok := r.paths[doc.ID]
+	if !ok {
+		return fmt.Errorf("%w: %s", domain.ErrNotFound, doc.ID)
+	}
+	stored := r.docs[doc.ID]
+	if doc.Slug == "" {
+		doc.Slug = stored.Slug
+	}
+	if doc.Status == "" {
+		doc.Status, doc.SubmittedAt, doc.PublishedAt, doc.ArchivedAt = stored.Status, stored.SubmittedAt, stored.PublishedAt, stored.ArchivedAt
+	}
+
+	return r.write(path, doc)
+}
+
+func (r *GoldenRepository) Delete(ctx context.Context, id string) error {
+	if !r.writable {
+		return ErrReadOnly
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	path, ok := r.paths[id]
+	if !ok {
+		return fmt.Errorf("%w: %s", domain.ErrNotFound, id)
+	}
+
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to delete golden: %w", err)
+	}
+	r.forget(path, r.files[path])
+
+	return nil
+}
+
+// Upsert writes every doc in turn. Files are replaced atomically one by one,
+// so unlike the postgres repository a failure can leave a partial batch.
+func (r *GoldenRepository) Upsert(ctx context.Context, docs []domain.Golden, dryRun bool) ([]domain.ImportResult, error) {
+	if !r.writable {
+		return nil, ErrReadOnly
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	results := make([]domain.ImportResult, 0, len(docs))
+	for i := range docs {
+		doc := &docs[i]
+		path, exists := r.paths[doc.ID]
+		status := domain.ImportStatusUpdated
+		if exists {
+			// Imports never rename.
+			stored := r.docs[doc.ID]
+			doc.Slug = stored.Slug
+			if doc.Status == "" {
+				doc.Status, doc.SubmittedAt, doc.PublishedAt, doc.ArchivedAt = stored.Status, stored.SubmittedAt, stored.PublishedAt, stored.ArchivedAt
+			}
+			if doc.PublishAt.IsZero() {
+				doc.PublishAt = stored.PublishAt
+			}
+			if doc.ExpireAt.IsZero() {
+				doc.ExpireAt = stored.ExpireAt
+			}
+		} else {
+			path = filepath.Join(r.root, markdown.FileName(doc.ID))
+			status = domain.ImportStatusCreated
+			if doc.Status == "" {
+				doc.Status, doc.SubmittedAt, doc.PublishedAt, doc.ArchivedAt = domain.StatusDraft, time.Time{}, time.Time{}, time.Time{}
+			}
+		}
+
+		if !dryRun {
+			if err := r.write(path, doc); err != nil {
+				return nil, err
+			}
+		}
+		results = append(results, domain.ImportResult{ID: doc.ID, Status: status})
+	}
+
+	return results, nil
+}
+
+// AppendEvents discards events: docs-as-code setups track changes in git.
+func (r *GoldenRepository) AppendEvents(ctx context.Context, events ...domain.Event) error {
+	return nil
+}
+
+// AppendTransition discards the entry: the status and its timestamps live in
+// the front matter, and the history in git.
+func (r *GoldenRepository) AppendTransition(ctx context.Context, transition domain.GoldenTransition) error {
+	return nil
+}
+
+func (r *GoldenRepository) ListTransitions(ctx context.Context, id string) ([]domain.GoldenTransition, error) {
+	return nil, nil
+}
+
+// RunInTx runs fn directly against the repository and ignores opts. Files
+// are written as fn goes, so there is no rollback when it fails.
+func (r *GoldenRepository) RunInTx(ctx context.Context, fn func(tx domain.Repository) error, opts ...domain.TxOption) error {
+	return fn(r)
+}
+
+// write stores doc at path through a temporary file and refreshes the index.
+// Callers must hold the write lock.
+func (r *GoldenRepository) write(path string, doc *domain.Golden) error {
+	data, err := markdown.Encode(*doc)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".golden-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write golden: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write golden: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write golden: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write golden: %w", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to stat golden: %w", err)
+	}
+
+	stored := *doc
+	stored.ContentSize, stored.ContentHash = domain.ContentDigest(stored.ContentB64)
+	if stored.Slug == "" {
+		stored.Slug = stored.BaseSlug()
+	}
+	r.docs[doc.ID] = stored
+	r.paths[doc.ID] = path
+	r.files[path] = fileState{modTime: info.ModTime(), size: info.Size(), id: doc.ID}
+
+	return nil
+}
@@ -0,0 +1,405 @@
+package filesystem
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"markitos-it-svc-goldens/internal/domain"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func helperWriteFile(t *testing.T, path, data string, modTime time.Time) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("failed to set mod time: %v", err)
+	}
+}
+
+func helperRandomGolden(t *testing.T) *domain.Golden {
+	t.Helper()
+
+	prefix := domain.HelperRandomAlphaPrefix(t, 8)
+	return &domain.Golden{
+		ID:          prefix + "-golden-id",
+		Title:       prefix + "-golden-title",
+		Description: prefix + "-golden-description",
+		Category:    prefix + "-golden-category",
+		Tags:        []string{prefix + "-go"},
+		UpdatedAt:   time.Date(2026, 3, 6, 12, 0, 0, 0, time.UTC),
+		ContentB64:  base64.StdEncoding.EncodeToString([]byte("# " + prefix)),
+		CoverImage:  "https://example.com/" + prefix + "/cover.png",
+	}
+}
+
+func TestGoldenRepository_Load_ReadsTreeOrderedByUpdatedAt(t *testing.T) {
+	root := t.TempDir()
+	older := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	helperWriteFile(t, filepath.Join(root, "old.md"), "---\ntitle: \"Old\"\n---\n# Old\n", older)
+	helperWriteFile(t, filepath.Join(root, "nested", "new.md"), "---\nid: \"new-id\"\ntitle: \"New\"\nupdated_at: 2026-02-01T00:00:00Z\n---\n# New\n", older)
+	helperWriteFile(t, filepath.Join(root, "notes.txt"), "ignored", older)
+	helperWriteFile(t, filepath.Join(root, "broken.md"), "no front matter", older)
+
+	r := NewGoldenRepository(root, false)
+	if err := r.Load(); err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+
+	docs, err := r.GetAll(context.Background(), domain.GoldenViewFull)
+	if err != nil {
+		t.Fatalf("GetAll() unexpected error: %v", err)
+	}
+	if len(docs) != 2 {
+		t.Fatalf("expected 2 docs, got %d", len(docs))
+	}
+	if docs[0].ID != "new-id" || docs[1].ID != "old" {
+		t.Fatalf("unexpected order: %s, %s", docs[0].ID, docs[1].ID)
+	}
+	if !docs[1].UpdatedAt.Equal(older) {
+		t.Errorf("expected UpdatedAt from mod time %v, got %v", older, docs[1].UpdatedAt)
+	}
+	if want := base64.StdEncoding.EncodeToString([]byte("# New\n")); docs[0].ContentB64 != want {
+		t.Errorf("ContentB64: want %q, got %q", want, docs[0].ContentB64)
+	}
+
+	basic, err := r.GetByID(context.Background(), "new-id", domain.GoldenViewBasic)
+	if err != nil {
+		t.Fatalf("GetByID() unexpected error: %v", err)
+	}
+	if basic.ContentB64 != "" || basic.ContentHash == "" {
+		t.Errorf("expected basic view with digest only, got %+v", basic)
+	}
+}
+
+func TestGoldenRepository_Load_PicksUpChangesAndRemovals(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "doc.md")
+	helperWriteFile(t, path, "---\ntitle: \"v1\"\n---\n", time.Unix(1000, 0))
+
+	r := NewGoldenRepository(root, false)
+	if err := r.Load(); err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+
+	helperWriteFile(t, path, "---\ntitle: \"v2\"\n---\n", time.Unix(2000, 0))
+	if err := r.Load(); err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	got, err := r.GetByID(context.Background(), "doc", domain.GoldenViewFull)
+	if err != nil {
+		t.Fatalf("GetByID() unexpected error: %v", err)
+	}
+	if got.Title != "v2" {
+		t.Fatalf("expected reloaded title v2, got %q", got.Title)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("failed to remove file: %v", err)
+	}
+	if err := r.Load(); err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if _, err := r.GetByID(context.Background(), "doc", domain.GoldenViewFull); err == nil {
+		t.Fatal("expected removed golden to be gone")
+	}
+}
+
+func TestGoldenRepository_Load_DuplicateIDsFirstPathWins(t *testing.T) {
+	root := t.TempDir()
+	first := filepath.Join(root, "a", "doc.md")
+	second := filepath.Join(root, "b", "doc.md")
+	helperWriteFile(t, second, "---\nid: \"doc\"\ntitle: \"b\"\n---\n", time.Unix(1000, 0))
+	helperWriteFile(t, first, "---\nid: \"doc\"\ntitle: \"a\"\n---\n", time.Unix(1000, 0))
+
+	title := func(r *GoldenRepository) string {
+		t.Helper()
+		if err := r.Load(); err != nil {
+			t.Fatalf("Load() unexpected error: %v", err)
+		}
+		got, err := r.GetByID(context.Background(), "doc", domain.GoldenViewFull)
+		if err != nil {
+			t.Fatalf("GetByID() unexpected error: %v", err)
+		}
+		return got.Title
+	}
+
+	for range 5 {
+		if got := title(NewGoldenRepository(root, false)); got != "a" {
+			t.Fatalf("expected the golden of the first path, got %q", got)
+		}
+	}
+
+	// The shadowed file stays out after it changes, and takes over once the
+	// first one is gone.
+	r := NewGoldenRepository(root, false)
+	title(r)
+	helperWriteFile(t, second, "---\nid: \"doc\"\ntitle: \"b2\"\n---\n", time.Unix(2000, 0))
+	if got := title(r); got != "a" {
+		t.Fatalf("expected the golden of the first path after a change, got %q", got)
+	}
+	if err := os.Remove(first); err != nil {
+		t.Fatalf("failed to remove file: %v", err)
+	}
+	if got := title(r); got != "b2" {
+		t.Fatalf("expected the shadowed golden once the first is removed, got %q", got)
+	}
+}
+
+func TestGoldenRepository_Load_MissingRoot(t *testing.T) {
+	r := NewGoldenRepository(filepath.Join(t.TempDir(), "missing"), false)
+	if err := r.Load(); err == nil {
+		t.Fatal("Load() expected error for missing root")
+	}
+}
+
+func TestGoldenRepository_Watch_ReloadsUntilCanceled(t *testing.T) {
+	root := t.TempDir()
+	r := NewGoldenRepository(root, false)
+	if err := r.Load(); err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		r.Watch(ctx, 10*time.Millisecond)
+		close(done)
+	}()
+
+	helperWriteFile(t, filepath.Join(root, "late.md"), "---\ntitle: \"Late\"\n---\n", time.Now())
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, err := r.GetByID(context.Background(), "late", domain.GoldenViewFull); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Watch() did not pick up the new file")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Watch() did not stop after cancel")
+	}
+}
+
+func TestGoldenRepository_ReadOnlyRejectsWrites(t *testing.T) {
+	r := NewGoldenRepository(t.TempDir(), false)
+	doc := helperRandomGolden(t)
+	ctx := context.Background()
+
+	if err := r.Create(ctx, doc); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Create() expected ErrReadOnly, got %v", err)
+	}
+	if err := r.Update(ctx, doc); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Update() expected ErrReadOnly, got %v", err)
+	}
+	if err := r.Delete(ctx, doc.ID); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Delete() expected ErrReadOnly, got %v", err)
+	}
+	if _, err := r.Upsert(ctx, []domain.Golden{*doc}, false); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Upsert() expected ErrReadOnly, got %v", err)
+	}
+}
+
+func TestGoldenRepository_WritableLifecycle(t *testing.T) {
+	root := t.TempDir()
+	r := NewGoldenRepository(root, true)
+	doc := helperRandomGolden(t)
+	ctx := context.Background()
+
+	if err := r.Create(ctx, doc); err != nil {
+		t.Fatalf("Create() unexpected error: %v", err)
+	}
+	if err := r.Create(ctx, doc); err == nil {
+		t.Fatal("Create() expected error for duplicate id")
+	}
+
+	// A fresh repository must read back what was written.
+	reloaded := NewGoldenRepository(root, true)
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	got, err := reloaded.GetByID(ctx, doc.ID, domain.GoldenViewFull)
+	if err != nil {
+		t.Fatalf("GetByID() unexpected error: %v", err)
+	}
+	if got.Title != doc.Title || got.ContentB64 != doc.ContentB64 {
+		t.Fatalf("unexpected golden read back: %+v", got)
+	}
+
+	publishAt := time.Date(2026, 4, 1, 9, 0, 0, 0, time.UTC)
+	doc.Title = doc.Title + "-updated"
+	doc.PublishAt = publishAt
+	if err := r.Update(ctx, doc); err != nil {
+		t.Fatalf("Update() unexpected error: %v", err)
+	}
+	got, _ = r.GetByID(ctx, doc.ID, domain.GoldenViewFull)
+	if got.Title != doc.Title || !got.PublishAt.Equal(publishAt) {
+		t.Fatalf("expected updated title %q and publish_at %v, got %q and %v", doc.Title, publishAt, got.Title, got.PublishAt)
+	}
+
+	// publish_at is written as given, so an update without it clears it.
+	doc.PublishAt = time.Time{}
+	if err := r.Update(ctx, doc); err != nil {
+		t.Fatalf("Update() unexpected error: %v", err)
+	}
+	if got, _ = r.GetByID(ctx, doc.ID, domain.GoldenViewFull); !got.PublishAt.IsZero() {
+		t.Fatalf("expected publish_at cleared, got %v", got.PublishAt)
+	}
+
+	if err := r.Delete(ctx, doc.ID); err != nil {
+		t.Fatalf("Delete() unexpected error: %v", err)
+	}
+	if err := r.Delete(ctx, doc.ID); err == nil {
+		t.Fatal("Delete() expected not found error")
+	}
+	if err := r.Update(ctx, doc); err == nil {
+		t.Fatal("Update() expected not found error")
+	}
+}
+
+func TestGoldenRepository_Upsert(t *testing.T) {
+	root := t.TempDir()
+	r := NewGoldenRepository(root, true)
+	existing := helperRandomGolden(t)
+	created := helperRandomGolden(t)
+	ctx := context.Background()
+
+	if err := r.Create(ctx, existing); err != nil {
+		t.Fatalf("Create() unexpected error: %v", err)
+	}
+
+	results, err := r.Upsert(ctx, []domain.Golden{*existing, *created}, true)
+	if err != nil {
+		t.Fatalf("Upsert() unexpected error: %v", err)
+	}
+	if results[0].Status != domain.ImportStatusUpdated || results[1].Status != domain.ImportStatusCreated {
+		t.Fatalf("unexpected results %+v", results)
+	}
+	if _, err := r.GetByID(ctx, created.ID, domain.GoldenViewFull); err == nil {
+		t.Fatal("expected dry run to skip writes")
+	}
+
+	if _, err := r.Upsert(ctx, []domain.Golden{*created}, false); err != nil {
+		t.Fatalf("Upsert() unexpected error: %v", err)
+	}
+	if _, err := r.GetByID(ctx, created.ID, domain.GoldenViewFull); err != nil {
+		t.Fatalf("expected upserted golden, got %v", err)
+	}
+}
+
+func TestGoldenRepository_GetByIDs(t *testing.T) {
+	root := t.TempDir()
+	modTime := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	helperWriteFile(t, filepath.Join(root, "first.md"), "---\ntitle: \"First\"\n---\n# First\n", modTime)
+	helperWriteFile(t, filepath.Join(root, "second.md"), "---\ntitle: \"Second\"\n---\n# Second\n", modTime)
+
+	r := NewGoldenRepository(root, false)
+	if err := r.Load(); err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+
+	docs, err := r.GetByIDs(context.Background(), []string{"second", "missing", "first"}, domain.GoldenViewBasic)
+	if err != nil {
+		t.Fatalf("GetByIDs() unexpected error: %v", err)
+	}
+	if len(docs) != 2 || docs[0].ID != "second" || docs[1].ID != "first" || docs[0].ContentB64 != "" {
+		t.Fatalf("GetByIDs() = %+v", docs)
+	}
+}
+
+func TestGoldenRepository_GetByCategories(t *testing.T) {
+	root := t.TempDir()
+	helperWriteFile(t, filepath.Join(root, "old.md"), "---\ntitle: \"Old\"\ncategory: \"ci-cd\"\n---\n# Old\n", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	helperWriteFile(t, filepath.Join(root, "new.md"), "---\ntitle: \"New\"\ncategory: \"devops\"\n---\n# New\n", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	helperWriteFile(t, filepath.Join(root, "api.md"), "---\ntitle: \"API\"\ncategory: \"apis\"\n---\n# API\n", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	r := NewGoldenRepository(root, false)
+	if err := r.Load(); err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+
+	docs, err := r.GetByCategories(context.Background(), []string{"devops", "ci-cd"}, domain.GoldenViewBasic)
+	if err != nil {
+		t.Fatalf("GetByCategories() unexpected error: %v", err)
+	}
+	if len(docs) != 2 || docs[0].ID != "new" || docs[1].ID != "old" || docs[0].ContentB64 != "" {
+		t.Fatalf("GetByCategories() = %+v", docs)
+	}
+}
+
+func TestGoldenRepository_CountFacets(t *testing.T) {
+	root := t.TempDir()
+	modTime := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	helperWriteFile(t, filepath.Join(root, "a.md"), "---\ntitle: \"A\"\ncategory: \"DevOps\"\ntags: [\"k8s\", \"ci-cd\"]\n---\n# A\n", modTime)
+	helperWriteFile(t, filepath.Join(root, "b.md"), "---\ntitle: \"B\"\ncategory: \"DevOps\"\ntags: [\"k8s\"]\n---\n# B\n", modTime)
+	helperWriteFile(t, filepath.Join(root, "c.md"), "---\ntitle: \"C\"\ncategory: \"APIs\"\ntags: [\"rest\"]\n---\n# C\n", modTime)
+	helperWriteFile(t, filepath.Join(root, "d.md"), "---\ntitle: \"D\"\ncategory: \"APIs\"\ntags: [\"k8s\"]\nstatus: \"draft\"\n---\n# D\n", modTime)
+
+	r := NewGoldenRepository(root, false)
+	if err := r.Load(); err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	ctx := context.Background()
+
+	categories, err := r.CountCategories(ctx, "")
+	want := []domain.FacetCount{{Value: "DevOps", Count: 2}, {Value: "APIs", Count: 1}}
+	if err != nil || !reflect.DeepEqual(categories, want) {
+		t.Fatalf("CountCategories() = %v, %v; want %v", categories, err, want)
+	}
+	categories, err = r.CountCategories(ctx, "rest")
+	want = []domain.FacetCount{{Value: "APIs", Count: 1}}
+	if err != nil || !reflect.DeepEqual(categories, want) {
+		t.Fatalf("CountCategories(rest) = %v, %v; want %v", categories, err, want)
+	}
+
+	tags, err := r.CountTags(ctx, []string{"DevOps"})
+	want = []domain.FacetCount{{Value: "k8s", Count: 2}, {Value: "ci-cd", Count: 1}}
+	if err != nil || !reflect.DeepEqual(tags, want) {
+		t.Fatalf("CountTags(DevOps) = %v, %v; want %v", tags, err, want)
+	}
+}
+
+func TestGoldenRepository_GetBySlug(t *testing.T) {
+	root := t.TempDir()
+	modTime := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	helperWriteFile(t, filepath.Join(root, "derived.md"), "---\ntitle: \"Guía de Keptn\"\n---\n# Keptn\n", modTime)
+	helperWriteFile(t, filepath.Join(root, "explicit.md"), "---\nslug: \"custom\"\ntitle: \"Other\"\n---\n# Other\n", modTime)
+
+	r := NewGoldenRepository(root, false)
+	if err := r.Load(); err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	ctx := context.Background()
+
+	got, err := r.GetBySlug(ctx, "guia-de-keptn", domain.GoldenViewFull)
+	if err != nil || got.ID != "derived" {
+		t.Fatalf("GetBySlug(derived) = %+v, %v", got, err)
+	}
+	got, err = r.GetBySlug(ctx, "custom", domain.GoldenViewBasic)
+	if err != nil || got.ID != "explicit" || got.ContentB64 != "" {
+		t.Fatalf("GetBySlug(custom) = %+v, %v", got, err)
+	}
+	if _, err := r.GetBySlug(ctx, "missing", domain.GoldenViewFull); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	taken, err := r.SlugsInUse(ctx, "custom")
+	if err != nil || len(taken) != 1 || taken[0] != "custom" {
+		t.Fatalf("SlugsInUse() = %v, %v", taken, err)
+	}
+}
@@ -0,0 +1,337 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"markitos-it-svc-goldens/internal/domain"
+
+	"github.com/lib/pq"
+)
+
+// categoryForeignKey ties goldens.category to categories.id. Its presence
+// also records that the free-text categories were normalized.
+const categoryForeignKey = "goldens_category_fkey"
+
+// categoryColumns is the select list read by scanCategory.
+const categoryColumns = `id, COALESCE(parent_id, ''), display_name, description, position, created_at, updated_at`
+
+func isUnknownCategory(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == pqForeignKeyViolation && pqErr.Constraint == categoryForeignKey
+}
+
+func isForeignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == pqForeignKeyViolation
+}
+
+// initCategorySchema creates the category tree. The unique index on the id
+// without hyphens enforces domain.CategoryKey, since ids are slugs.
+func (r *GoldenRepository) initCategorySchema(ctx context.Context) error {
+	schema := `
+	CREATE TABLE IF NOT EXISTS categories (
+		id VARCHAR(100) PRIMARY KEY,
+		parent_id VARCHAR(100) REFERENCES categories(id),
+		display_name VARCHAR(200) NOT NULL,
+		description TEXT NOT NULL DEFAULT '',
+		position INT NOT NULL DEFAULT 0,
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_key ON categories ((replace(id, '-', '')));
+	CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories(parent_id);
+	`
+
+	_, err := r.db.ExecContext(ctx, schema)
+	if err != nil {
+		return fmt.Errorf("failed to initialize category schema: %w", err)
+	}
+
+	return r.normalizeCategories(ctx)
+}
+
+// normalizeCategories turns the free-text categories stored before the
+// categories table existed into categories, folding the spellings that share
+// a domain.CategoryKey into one named after the most used spelling, and then
+// adds the foreign key. The advisory lock keeps replicas starting together
+// from racing.
+func (r *GoldenRepository) normalizeCategories(ctx context.Context) error {
+	return r.withTx(ctx, false, domain.NewTxOptions(), func(tx *GoldenRepository) error {
+		if _, err := tx.conn().ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('goldens_category_normalize'))`); err != nil {
+			return fmt.Errorf("failed to lock category normalization: %w", err)
+		}
+
+		var done bool
+		err := tx.conn().QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = $1)`, categoryForeignKey).Scan(&done)
+		if err != nil {
+			return fmt.Errorf("failed to check category foreign key: %w", err)
+		}
+		if done {
+			return nil
+		}
+
+		ids, err := queryStrings(ctx, tx.conn(), `SELECT id FROM categories`)
+		if err != nil {
+			return fmt.Errorf("failed to query categories: %w", err)
+		}
+		byKey := make(map[string]string, len(ids))
+		for _, id := range ids {
+			byKey[domain.CategoryKey(id)] = id
+		}
+
+		values, err := queryStrings(ctx, tx.conn(), `
+			SELECT category FROM goldens
+			WHERE category IS NOT NULL
+			GROUP BY category
+			ORDER BY COUNT(*) DESC, category
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to query golden categories: %w", err)
+		}
+
+		var changed []string
+		for _, value := range values {
+			key := domain.CategoryKey(value)
+			id, ok := byKey[key]
+			if !ok && key != "" {
+				id = domain.Slugify(value)
+				_, err := tx.conn().ExecContext(ctx, `INSERT INTO categories (id, display_name) VALUES ($1, $2)`, id, value)
+				if err != nil {
+					return fmt.Errorf("failed to create category %s: %w", id, err)
+				}
+				byKey[key] = id
+			}
+			if ok && id == value {
+				continue
+			}
+
+			// Values without letters or digits, such as "", lose their category.
+			updated, err := queryStrings(ctx, tx.conn(), `UPDATE goldens SET category = NULLIF($2, '') WHERE category = $1 RETURNING id`, value, id)
+			if err != nil {
+				return fmt.Errorf("failed to normalize category %q: %w", value, err)
+			}
+			changed = append(changed, updated...)
+		}
+
+		alter := `ALTER TABLE goldens ADD CONSTRAINT ` + categoryForeignKey + ` FOREIGN KEY (category) REFERENCES categories(id)`
+		if _, err := tx.conn().ExecContext(ctx, alter); err != nil {
+			return fmt.Errorf("failed to add category foreign key: %w", err)
+		}
+
+		tx.notifyInvalidation(ctx, tx.conn(), changed...)
+		return nil
+	})
+}
+
+func queryStrings(ctx context.Context, conn querier, query string, args ...any) ([]string, error) {
+	rows, err := conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var values []string
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
+
+// CategoryRepository stores the category tree that goldens belong to. Its
+// schema is created by GoldenRepository.InitSchema, as goldens refer to it.
+type CategoryRepository struct {
+	db *sql.DB
+}
+
+func NewCategoryRepository(db *sql.DB) *CategoryRepository {
+	return &CategoryRepository{db: db}
+}
+
+func (r *CategoryRepository) ListCategories(ctx context.Context) ([]domain.Category, error) {
+	query := `SELECT ` + categoryColumns + ` FROM categories ORDER BY id`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, wrapError("failed to query categories", err)
+	}
+	defer rows.Close()
+
+	var categories []domain.Category
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			return nil, wrapError("failed to scan category", err)
+		}
+		categories = append(categories, *category)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, wrapError("error iterating categories", err)
+	}
+
+	return categories, nil
+}
+
+func (r *CategoryRepository) GetCategory(ctx context.Context, id string) (*domain.Category, error) {
+	query := `SELECT ` + categoryColumns + ` FROM categories WHERE id = $1`
+
+	category, err := scanCategory(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: %s", domain.ErrCategoryNotFound, id)
+	}
+	if err != nil {
+		return nil, wrapError("failed to query category", err)
+	}
+
+	return category, nil
+}
+
+func (r *CategoryRepository) CreateCategory(ctx context.Context, category *domain.Category) error {
+	query := `
+		INSERT INTO categories (id, parent_id, display_name, description, position, created_at, updated_at)
+		VALUES ($1, NULLIF($2, ''), $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		category.ID,
+		category.ParentID,
+		category.DisplayName,
+		category.Description,
+		category.Position,
+		category.CreatedAt,
+		category.UpdatedAt,
+	)
+
+	if isUniqueViolation(err) {
+		return fmt.Errorf("%w: %s", domain.ErrCategoryExists, category.ID)
+	}
+	if isForeignKeyViolation(err) {
+		return fmt.Errorf("%w: unknown parent %s", domain.ErrInvalidCategory, category.ParentID)
+	}
+	if err != nil {
+		return wrapError("failed to create category", err)
+	}
+
+	return nil
+}
+
+func (r *CategoryRepository) UpdateCategory(ctx context.Context, category *domain.Category) error {
+	query := `
+		UPDATE categories
+		SET parent_id = NULLIF($2, ''), display_name = $3, description = $4, position = $5, updated_at = $6
+		WHERE id = $1
+		RETURNING created_at
+	`
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		category.ID,
+		category.ParentID,
+		category.DisplayName,
+		category.Description,
+		category.Position,
+		category.UpdatedAt,
+	).Scan(&category.CreatedAt)
+
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("%w: %s", domain.ErrCategoryNotFound, category.ID)
+	}
+	if isForeignKeyViolation(err) {
+		return fmt.Errorf("%w: unknown parent %s", domain.ErrInvalidCategory, category.ParentID)
+	}
+	if err != nil {
+		return wrapError("failed to update category", err)
+	}
+
+	return nil
+}
+
+// DeleteCategory refuses to delete a category that goldens or subcategories
+// still refer to.
+func (r *CategoryRepository) DeleteCategory(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM categories WHERE id = $1`, id)
+	if isForeignKeyViolation(err) {
+		return fmt.Errorf("%w: %s has goldens or subcategories", domain.ErrCategoryInUse, id)
+	}
+	if err != nil {
+		return wrapError("failed to delete category", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return wrapError("failed to get rows affected", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%w: %s", domain.ErrCategoryNotFound, id)
+	}
+
+	return nil
+}
+
+func (r *CategoryRepository) ResolveCategory(ctx context.Context, value string) (string, error) {
+	query := `SELECT id FROM categories WHERE replace(id, '-', '') = $1`
+
+	var id string
+	err := r.db.QueryRowContext(ctx, query, domain.CategoryKey(value)).Scan(&id)
+	if err == sql.ErrNoRows {
+		return "", fmt.Errorf("%w: %s", domain.ErrCategoryNotFound, value)
+	}
+	if err != nil {
+		return "", wrapError("failed to resolve category", err)
+	}
+
+	return id, nil
+}
+
+// CategorySubtree walks the tree with a recursive query. UNION rather than
+// UNION ALL stops the walk should the parents ever form a cycle.
+func (r *CategoryRepository) CategorySubtree(ctx context.Context, id string) ([]string, error) {
+	query := `
+		WITH RECURSIVE subtree(id) AS (
+			SELECT id FROM categories WHERE id = $1
+			UNION
+			SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+		)
+		SELECT id FROM subtree ORDER BY id <> $1, id
+	`
+
+	ids, err := queryStrings(ctx, r.db, query, id)
+	if err != nil {
+		return nil, wrapError("failed to query category subtree", err)
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("%w: %s", domain.ErrCategoryNotFound, id)
+	}
+
+	return ids, nil
+}
+
+func scanCategory(row rowScanner) (*domain.Category, error) {
+	var category domain.Category
+
+	err := row.Scan(
+		&category.ID,
+		&category.ParentID,
+		&category.DisplayName,
+		&category.Description,
+		&category.Position,
+		&category.CreatedAt,
+		&category.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &category, nil
+}
@@ -0,0 +1,121 @@
+package postgres
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"markitos-it-svc-goldens/internal/domain"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	pqAdminShutdown       pq.ErrorCode = "57P01"
+	pqCrashShutdown       pq.ErrorCode = "57P02"
+	pqCannotConnectNow    pq.ErrorCode = "57P03"
+	pqUniqueViolation     pq.ErrorCode = "23505"
+	pqForeignKeyViolation pq.ErrorCode = "23503"
+	// pqConnectionException is the SQLSTATE class of lost or refused
+	// connections.
+	pqConnectionException = "08"
+)
+
+// RetryPolicy bounds the retries of idempotent reads that failed with a
+// transient error.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseBackoff: 50 * time.Millisecond,
+		MaxBackoff:  time.Second,
+	}
+}
+
+// isUnavailable reports whether err means the database could not be reached
+// or is going away, as during a failover. The caller's own cancellation or
+// deadline is not: context errors are net.Errors too, but retrying them
+// cannot succeed.
+func isUnavailable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case pqAdminShutdown, pqCrashShutdown, pqCannotConnectNow:
+			return true
+		}
+		return pqErr.Code.Class() == pqConnectionException
+	}
+
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+
+	// Only failures to dial or of the connection itself, not any net.Error.
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	return errors.As(err, &opErr) || errors.As(err, &dnsErr)
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == pqUniqueViolation
+}
+
+// isTransient reports whether repeating the failed statement can succeed.
+func isTransient(err error) bool {
+	return isUnavailable(err) || isTxConflict(err)
+}
+
+// wrapError prefixes err with msg and marks availability failures with
+// domain.ErrUnavailable so that callers can tell them from query errors.
+func wrapError(msg string, err error) error {
+	if isUnavailable(err) {
+		return fmt.Errorf("%s: %w: %w", msg, domain.ErrUnavailable, err)
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
+
+// retryRead runs the idempotent read fn again after transient failures, up
+// to the repository's retry policy. Inside a transaction the first failure
+// aborts it anyway, so fn runs once. A replica that fails to answer is taken
+// out of rotation until its next health check, so the retry hits the primary.
+func (r *GoldenRepository) retryRead(ctx context.Context, fn func(conn querier) error) error {
+	if r.tx != nil {
+		return fn(r.tx)
+	}
+
+	backoff := r.retry.BaseBackoff
+	for attempt := 1; ; attempt++ {
+		conn, fromReplica := r.readConn(ctx)
+		err := fn(conn)
+		if err != nil && fromReplica && isUnavailable(err) {
+			r.monitor.setHealthy(false, err.Error())
+		}
+		if err == nil || attempt >= r.retry.MaxAttempts || !isTransient(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, r.retry.MaxBackoff)
+	}
+}
@@ -0,0 +1,108 @@
+package postgres
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"markitos-it-svc-goldens/internal/domain"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+func TestIsUnavailable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "admin-shutdown", err: &pq.Error{Code: "57P01"}, want: true},
+		{name: "cannot-connect-now", err: &pq.Error{Code: "57P03"}, want: true},
+		{name: "connection-failure", err: &pq.Error{Code: "08006"}, want: true},
+		{name: "bad-conn", err: driver.ErrBadConn, want: true},
+		{name: "eof", err: fmt.Errorf("read: %w", io.EOF), want: true},
+		{name: "connection-reset", err: &net.OpError{Op: "read", Err: syscall.ECONNRESET}, want: true},
+		{name: "dial-timeout", err: &net.OpError{Op: "dial", Err: &net.DNSError{IsTimeout: true}}, want: true},
+		{name: "lookup-failure", err: &net.DNSError{Err: "no such host", Name: "db"}, want: true},
+		{name: "caller-deadline", err: fmt.Errorf("query: %w", context.DeadlineExceeded), want: false},
+		{name: "caller-deadline-while-dialing", err: &net.OpError{Op: "dial", Err: context.DeadlineExceeded}, want: false},
+		{name: "caller-cancelled", err: context.Canceled, want: false},
+		{name: "serialization-failure", err: &pq.Error{Code: "40001"}, want: false},
+		{name: "undefined-table", err: &pq.Error{Code: "42P01"}, want: false},
+		{name: "no-rows", err: errors.New("sql: no rows in result set"), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isUnavailable(tt.err); got != tt.want {
+				t.Errorf("isUnavailable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsUniqueViolation(t *testing.T) {
+	if !isUniqueViolation(fmt.Errorf("insert: %w", &pq.Error{Code: "23505"})) {
+		t.Error("expected 23505 to be a unique violation")
+	}
+	if isUniqueViolation(&pq.Error{Code: "23503"}) {
+		t.Error("foreign key violation is not a unique violation")
+	}
+}
+
+func TestIsSlugConflict(t *testing.T) {
+	if !isSlugConflict(fmt.Errorf("insert: %w", &pq.Error{Code: "23505", Constraint: slugIndex})) {
+		t.Error("expected a violation of the slug index to be a slug conflict")
+	}
+	if isSlugConflict(&pq.Error{Code: "23505", Constraint: "goldens_pkey"}) {
+		t.Error("duplicate id is not a slug conflict")
+	}
+}
+
+func TestWrapError(t *testing.T) {
+	err := wrapError("failed to query goldens", &pq.Error{Code: "57P01"})
+	if !errors.Is(err, domain.ErrUnavailable) {
+		t.Errorf("expected ErrUnavailable in %v", err)
+	}
+
+	err = wrapError("failed to query goldens", &pq.Error{Code: "42601"})
+	if errors.Is(err, domain.ErrUnavailable) {
+		t.Errorf("expected a plain error, got %v", err)
+	}
+}
+
+func TestGoldenRepository_RetryRead(t *testing.T) {
+	r := NewGoldenRepository(nil)
+	r.SetRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseBackoff: time.Millisecond, MaxBackoff: time.Millisecond})
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		errs      []error
+		wantCalls int
+		wantErr   bool
+	}{
+		{name: "recovers-after-failover", errs: []error{&pq.Error{Code: "57P01"}, driver.ErrBadConn, nil}, wantCalls: 3},
+		{name: "gives-up", errs: []error{io.EOF, io.EOF, io.EOF, nil}, wantCalls: 3, wantErr: true},
+		{name: "permanent-error", errs: []error{&pq.Error{Code: "42P01"}, nil}, wantCalls: 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := r.retryRead(ctx, func(querier) error {
+				calls++
+				return tt.errs[calls-1]
+			})
+			if calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("retryRead() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
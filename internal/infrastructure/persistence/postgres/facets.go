@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"markitos-it-svc-goldens/internal/domain"
+
+	"github.com/lib/pq"
+)
+
+// liveCondition matches the goldens readers see, as domain.Golden.Live does.
+// Timestamps are stored in UTC.
+const liveCondition = `(status = 'published' OR (status = 'scheduled' AND publish_at <= (now() AT TIME ZONE 'UTC')))
+		AND (expire_at IS NULL OR expire_at > (now() AT TIME ZONE 'UTC'))`
+
+// visibleCondition restricts the reads of callers without editor rights to
+// live goldens, so hidden rows are neither fetched nor counted.
+func visibleCondition(ctx context.Context) string {
+	if domain.IsEditor(ctx) {
+		return "TRUE"
+	}
+	return liveCondition
+}
+
+// The filters are only added when set, so that the tag filter can use the GIN
+// index on tags through @>.
+func (r *GoldenRepository) CountCategories(ctx context.Context, tag string) ([]domain.FacetCount, error) {
+	query := `
+		SELECT category, COUNT(*)
+		FROM goldens
+		WHERE category IS NOT NULL AND category <> '' AND ` + liveCondition
+	var args []any
+	if tag != "" {
+		query += ` AND tags @> ARRAY[$1]::TEXT[]`
+		args = append(args, tag)
+	}
+	query += `
+		GROUP BY category
+		ORDER BY COUNT(*) DESC, category
+	`
+
+	return r.queryFacets(ctx, "categories", query, args...)
+}
+
+// CountTags counts each golden once per tag, even when the tag is repeated.
+func (r *GoldenRepository) CountTags(ctx context.Context, categories []string) ([]domain.FacetCount, error) {
+	query := `
+		SELECT tag, COUNT(DISTINCT id)
+		FROM goldens, unnest(tags) AS tag
+		WHERE tag <> '' AND ` + liveCondition
+	var args []any
+	if len(categories) > 0 {
+		query += ` AND category = ANY($1)`
+		args = append(args, pq.Array(categories))
+	}
+	query += `
+		GROUP BY tag
+		ORDER BY COUNT(DISTINCT id) DESC, tag
+	`
+
+	return r.queryFacets(ctx, "tags", query, args...)
+}
+
+func (r *GoldenRepository) queryFacets(ctx context.Context, name, query string, args ...any) ([]domain.FacetCount, error) {
+	var facets []domain.FacetCount
+	err := r.retryRead(ctx, func(conn querier) error {
+		facets = nil
+		rows, err := conn.QueryContext(ctx, query, args...)
+		if err != nil {
+			return wrapError("failed to count "+name, err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var facet domain.FacetCount
+			if err := rows.Scan(&facet.Value, &facet.Count); err != nil {
+				return wrapError("failed to scan "+name, err)
+			}
+			facets = append(facets, facet)
+		}
+
+		if err := rows.Err(); err != nil {
+			return wrapError("error iterating "+name, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return facets, nil
+}
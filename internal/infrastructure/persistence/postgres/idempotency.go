@@ -0,0 +1,121 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"markitos-it-svc-goldens/internal/domain"
+	"time"
+)
+
+// IdempotencyRepository stores the responses of writes made with an
+// idempotency key.
+type IdempotencyRepository struct {
+	db *sql.DB
+}
+
+func NewIdempotencyRepository(db *sql.DB) *IdempotencyRepository {
+	return &IdempotencyRepository{db: db}
+}
+
+func (r *IdempotencyRepository) InitSchema(ctx context.Context) error {
+	schema := `
+	CREATE TABLE IF NOT EXISTS idempotency_keys (
+		key VARCHAR(255) NOT NULL,
+		method VARCHAR(255) NOT NULL,
+		request_hash VARCHAR(64) NOT NULL,
+		response BYTEA,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+		expires_at TIMESTAMPTZ NOT NULL,
+		PRIMARY KEY (key, method)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
+
+	-- Keys are prefixed with the principal of the caller.
+	ALTER TABLE idempotency_keys ALTER COLUMN key TYPE VARCHAR(330);
+	`
+
+	_, err := r.db.ExecContext(ctx, schema)
+	if err != nil {
+		return fmt.Errorf("failed to initialize idempotency schema: %w", err)
+	}
+
+	return nil
+}
+
+func (r *IdempotencyRepository) Reserve(ctx context.Context, key, method, requestHash string, lease time.Duration) (*domain.IdempotencyRecord, error) {
+	// Only an expired record may be taken over; a live one is left alone and
+	// the statement returns no row.
+	claim := `
+		INSERT INTO idempotency_keys (key, method, request_hash, expires_at)
+		VALUES ($1, $2, $3, now() + $4 * interval '1 millisecond')
+		ON CONFLICT (key, method) DO UPDATE
+		SET request_hash = EXCLUDED.request_hash, response = NULL, created_at = now(), expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= now()
+		RETURNING key
+	`
+	lookup := `
+		SELECT key, method, request_hash, response, created_at, expires_at
+		FROM idempotency_keys
+		WHERE key = $1 AND method = $2 AND expires_at > now()
+	`
+
+	// The live record can expire or be released between both statements,
+	// in which case the key is claimed on the next round.
+	for range 3 {
+		var claimed string
+		err := r.db.QueryRowContext(ctx, claim, key, method, requestHash, lease.Milliseconds()).Scan(&claimed)
+		if err == nil {
+			return nil, nil
+		}
+		if err != sql.ErrNoRows {
+			return nil, wrapError("failed to reserve idempotency key", err)
+		}
+
+		var record domain.IdempotencyRecord
+		err = r.db.QueryRowContext(ctx, lookup, key, method).Scan(
+			&record.Key, &record.Method, &record.RequestHash, &record.Response, &record.CreatedAt, &record.ExpiresAt)
+		if err == nil {
+			return &record, nil
+		}
+		if err != sql.ErrNoRows {
+			return nil, wrapError("failed to read idempotency key", err)
+		}
+	}
+
+	return nil, fmt.Errorf("failed to reserve idempotency key %s: %w", key, domain.ErrIdempotencyInProgress)
+}
+
+func (r *IdempotencyRepository) Complete(ctx context.Context, key, method string, response []byte, ttl time.Duration) error {
+	query := `
+		UPDATE idempotency_keys
+		SET response = $3, expires_at = now() + $4 * interval '1 millisecond'
+		WHERE key = $1 AND method = $2
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, key, method, response, ttl.Milliseconds()); err != nil {
+		return wrapError("failed to store idempotent response", err)
+	}
+
+	return nil
+}
+
+func (r *IdempotencyRepository) Release(ctx context.Context, key, method string) error {
+	query := `DELETE FROM idempotency_keys WHERE key = $1 AND method = $2 AND response IS NULL`
+
+	if _, err := r.db.ExecContext(ctx, query, key, method); err != nil {
+		return wrapError("failed to release idempotency key", err)
+	}
+
+	return nil
+}
+
+func (r *IdempotencyRepository) PurgeExpired(ctx context.Context) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= now()`)
+	if err != nil {
+		return 0, wrapError("failed to purge idempotency keys", err)
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// InvalidationChannel is the LISTEN/NOTIFY channel carrying the ids of
+// goldens written by any replica.
+const InvalidationChannel = "goldens_invalidation"
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// notifyInvalidation tells every replica that ids changed. A failed notify
+// only delays other replicas until their cache TTL, so it is logged rather
+// than failing a write that already succeeded.
+func (r *GoldenRepository) notifyInvalidation(ctx context.Context, conn execer, ids ...string) {
+	if len(ids) == 0 {
+		return
+	}
+
+	_, err := conn.ExecContext(ctx, "SELECT pg_notify($1, id) FROM unnest($2::text[]) AS id", InvalidationChannel, pq.Array(ids))
+	if err != nil {
+		log.Printf("⚠️  Failed to publish cache invalidation for %v: %v", ids, err)
+	}
+}
+
+// Invalidator is the cache side of the invalidation bus.
+type Invalidator interface {
+	Invalidate(ids ...string)
+	Flush()
+}
+
+// InvalidationListener subscribes to InvalidationChannel and applies the
+// received invalidations to a cache. Whenever the connection drops the whole
+// cache is flushed, since notifications sent meanwhile are lost.
+type InvalidationListener struct {
+	target   Invalidator
+	listener *pq.Listener
+}
+
+func NewInvalidationListener(dsn string, target Invalidator) *InvalidationListener {
+	l := &InvalidationListener{target: target}
+	l.listener = pq.NewListener(dsn, time.Second, time.Minute, l.handleEvent)
+	return l
+}
+
+// Run listens until ctx is done.
+func (l *InvalidationListener) Run(ctx context.Context) error {
+	defer l.listener.Close()
+
+	if err := l.listener.Listen(InvalidationChannel); err != nil {
+		return err
+	}
+	log.Printf("📡 Listening for cache invalidations on %s", InvalidationChannel)
+
+	// pq recommends pinging idle listeners to detect dead connections.
+	ping := time.NewTicker(90 * time.Second)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case n := <-l.listener.Notify:
+			l.handleNotification(n)
+		case <-ping.C:
+			go l.listener.Ping()
+		}
+	}
+}
+
+func (l *InvalidationListener) handleNotification(n *pq.Notification) {
+	// pq sends nil after re-establishing a lost connection.
+	if n == nil {
+		l.target.Flush()
+		return
+	}
+	l.target.Invalidate(n.Extra)
+}
+
+func (l *InvalidationListener) handleEvent(event pq.ListenerEventType, err error) {
+	switch event {
+	case pq.ListenerEventDisconnected:
+		log.Printf("⚠️  Cache invalidation listener disconnected: %v", err)
+		l.target.Flush()
+	case pq.ListenerEventReconnected:
+		log.Println("📡 Cache invalidation listener reconnected, flushing cache")
+		l.target.Flush()
+	case pq.ListenerEventConnectionAttemptFailed:
+		log.Printf("⚠️  Cache invalidation listener reconnect failed: %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+type recordingInvalidator struct {
+	mu          sync.Mutex
+	invalidated []string
+	flushes     int
+}
+
+func (r *recordingInvalidator) Invalidate(ids ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.invalidated = append(r.invalidated, ids...)
+}
+
+func (r *recordingInvalidator) Flush() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.flushes++
+}
+
+func (r *recordingInvalidator) snapshot() ([]string, int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.invalidated...), r.flushes
+}
+
+func TestInvalidationListener_HandleNotification(t *testing.T) {
+	target := &recordingInvalidator{}
+	l := &InvalidationListener{target: target}
+
+	l.handleNotification(&pq.Notification{Channel: InvalidationChannel, Extra: "id-1"})
+	l.handleNotification(nil)
+
+	invalidated, flushes := target.snapshot()
+	if !reflect.DeepEqual(invalidated, []string{"id-1"}) {
+		t.Errorf("expected id-1 invalidated, got %v", invalidated)
+	}
+	if flushes != 1 {
+		t.Errorf("expected reconnect notification to flush, got %d flushes", flushes)
+	}
+}
+
+func TestInvalidationListener_HandleEvent(t *testing.T) {
+	tests := []struct {
+		name        string
+		event       pq.ListenerEventType
+		wantFlushes int
+	}{
+		{name: "connected", event: pq.ListenerEventConnected, wantFlushes: 0},
+		{name: "disconnected", event: pq.ListenerEventDisconnected, wantFlushes: 1},
+		{name: "reconnected", event: pq.ListenerEventReconnected, wantFlushes: 1},
+		{name: "attempt-failed", event: pq.ListenerEventConnectionAttemptFailed, wantFlushes: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &recordingInvalidator{}
+			l := &InvalidationListener{target: target}
+
+			l.handleEvent(tt.event, errors.New("connection reset"))
+
+			if _, flushes := target.snapshot(); flushes != tt.wantFlushes {
+				t.Fatalf("expected %d flushes, got %d", tt.wantFlushes, flushes)
+			}
+		})
+	}
+}
+
+func TestGoldenRepository_NotifyInvalidation_IgnoresErrors(t *testing.T) {
+	db := helperClosedDB(t)
+	r := NewGoldenRepository(db)
+
+	// Must not panic or block when the database is unavailable.
+	r.notifyInvalidation(t.Context(), db, "id-1")
+	r.notifyInvalidation(t.Context(), db)
+}
@@ -0,0 +1,160 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"markitos-it-svc-goldens/internal/domain"
+	"sort"
+	"time"
+)
+
+const (
+	outboxStatusPending   = "pending"
+	outboxStatusDelivered = "delivered"
+	outboxStatusDead      = "dead"
+)
+
+func (r *GoldenRepository) initOutboxSchema(ctx context.Context) error {
+	schema := `
+	CREATE TABLE IF NOT EXISTS outbox_events (
+		seq BIGSERIAL PRIMARY KEY,
+		event_id VARCHAR(64) NOT NULL UNIQUE,
+		event_type VARCHAR(100) NOT NULL,
+		aggregate_id VARCHAR(255) NOT NULL,
+		payload JSONB NOT NULL,
+		occurred_at TIMESTAMP NOT NULL,
+		status VARCHAR(20) NOT NULL DEFAULT 'pending',
+		attempts INT NOT NULL DEFAULT 0,
+		last_error TEXT,
+		next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		delivered_at TIMESTAMP
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_outbox_events_pending ON outbox_events(next_attempt_at) WHERE status = 'pending';
+	`
+
+	_, err := r.db.ExecContext(ctx, schema)
+	if err != nil {
+		return fmt.Errorf("failed to initialize outbox schema: %w", err)
+	}
+
+	return nil
+}
+
+// AppendEvents writes events to the outbox. Called through RunInTx they are
+// committed or discarded together with the change that raised them.
+func (r *GoldenRepository) AppendEvents(ctx context.Context, events ...domain.Event) error {
+	query := `
+		INSERT INTO outbox_events (event_id, event_type, aggregate_id, payload, occurred_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	for _, event := range events {
+		_, err := r.writeConn(ctx).ExecContext(ctx, query, event.ID, string(event.Type), event.AggregateID, string(event.Payload), event.OccurredAt)
+		if err != nil {
+			return wrapError(fmt.Sprintf("failed to append %s event", event.Type), err)
+		}
+	}
+
+	return nil
+}
+
+// OutboxRepository is the relay side of the outbox table.
+type OutboxRepository struct {
+	db *sql.DB
+}
+
+func NewOutboxRepository(db *sql.DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// ClaimPending leases up to limit due events in the order they were written.
+// SKIP LOCKED lets relays on several replicas claim disjoint sets, and the
+// lease pushes next_attempt_at forward so a crashed relay's events come back.
+func (o *OutboxRepository) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]domain.OutboxRecord, error) {
+	query := `
+		UPDATE outbox_events
+		SET attempts = attempts + 1, next_attempt_at = now() + $2 * interval '1 millisecond'
+		WHERE seq IN (
+			SELECT seq FROM outbox_events
+			WHERE status = 'pending' AND next_attempt_at <= now()
+			ORDER BY seq
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING seq, event_id, event_type, aggregate_id, payload, occurred_at, attempts
+	`
+
+	rows, err := o.db.QueryContext(ctx, query, limit, lease.Milliseconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim outbox events: %w", err)
+	}
+	defer rows.Close()
+
+	type claimed struct {
+		seq    int64
+		record domain.OutboxRecord
+	}
+	var batch []claimed
+	for rows.Next() {
+		var c claimed
+		var eventType, payload string
+		err := rows.Scan(&c.seq, &c.record.ID, &eventType, &c.record.AggregateID, &payload, &c.record.OccurredAt, &c.record.Attempts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan outbox event: %w", err)
+		}
+
+		c.record.Type = domain.EventType(eventType)
+		c.record.Payload = []byte(payload)
+		batch = append(batch, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating outbox events: %w", err)
+	}
+
+	// RETURNING does not keep the subquery order.
+	sort.Slice(batch, func(i, j int) bool { return batch[i].seq < batch[j].seq })
+	records := make([]domain.OutboxRecord, 0, len(batch))
+	for _, c := range batch {
+		records = append(records, c.record)
+	}
+
+	return records, nil
+}
+
+func (o *OutboxRepository) MarkDelivered(ctx context.Context, eventID string) error {
+	query := `
+		UPDATE outbox_events
+		SET status = $2, delivered_at = now(), last_error = NULL
+		WHERE event_id = $1
+	`
+
+	if _, err := o.db.ExecContext(ctx, query, eventID, outboxStatusDelivered); err != nil {
+		return fmt.Errorf("failed to mark event %s delivered: %w", eventID, err)
+	}
+
+	return nil
+}
+
+// MarkFailed records a delivery failure and schedules the next attempt, or
+// moves the event to the dead-letter state when dead is set.
+func (o *OutboxRepository) MarkFailed(ctx context.Context, eventID string, cause error, retryIn time.Duration, dead bool) error {
+	status := outboxStatusPending
+	if dead {
+		status = outboxStatusDead
+	}
+
+	query := `
+		UPDATE outbox_events
+		SET status = $2, last_error = $3, next_attempt_at = now() + $4 * interval '1 millisecond'
+		WHERE event_id = $1
+	`
+
+	if _, err := o.db.ExecContext(ctx, query, eventID, status, cause.Error(), retryIn.Milliseconds()); err != nil {
+		return fmt.Errorf("failed to mark event %s failed: %w", eventID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"markitos-it-svc-goldens/internal/domain"
+	"sync/atomic"
+	"time"
+)
+
+// ReplicaConfig tunes when reads may be served by a read replica.
+type ReplicaConfig struct {
+	// MaxLag is the replay delay beyond which reads go back to the primary.
+	MaxLag time.Duration
+	// CheckInterval is how often the replica health and lag are probed.
+	CheckInterval time.Duration
+}
+
+func DefaultReplicaConfig() ReplicaConfig {
+	return ReplicaConfig{MaxLag: 5 * time.Second, CheckInterval: 5 * time.Second}
+}
+
+// replicaLagQuery reports whether the server is a standby and how far its
+// replay is behind. A standby that has replayed everything it received is
+// not lagging, however old its last replayed transaction is.
+const replicaLagQuery = `
+	SELECT pg_is_in_recovery(),
+		CASE
+			WHEN NOT pg_is_in_recovery() OR pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+			ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+		END
+`
+
+// ReplicaMonitor tracks whether a read replica is reachable and close enough
+// to the primary to serve reads. It starts unhealthy until the first check.
+type ReplicaMonitor struct {
+	db      *sql.DB
+	config  ReplicaConfig
+	healthy atomic.Bool
+	lag     atomic.Int64
+}
+
+func NewReplicaMonitor(db *sql.DB, config ReplicaConfig) *ReplicaMonitor {
+	return &ReplicaMonitor{db: db, config: config}
+}
+
+// Healthy reports whether reads may currently be routed to the replica.
+func (m *ReplicaMonitor) Healthy() bool {
+	return m.healthy.Load()
+}
+
+// Lag returns the replay delay measured by the last successful check.
+func (m *ReplicaMonitor) Lag() time.Duration {
+	return time.Duration(m.lag.Load())
+}
+
+// Check probes the replica once and updates its health.
+func (m *ReplicaMonitor) Check(ctx context.Context) error {
+	lag, err := m.measureLag(ctx)
+	if err != nil {
+		m.setHealthy(false, err.Error())
+		return err
+	}
+
+	m.lag.Store(int64(lag))
+	if lag > m.config.MaxLag {
+		m.setHealthy(false, fmt.Sprintf("lagging %s behind the primary", lag))
+		return nil
+	}
+	m.setHealthy(true, "")
+	return nil
+}
+
+// Run checks the replica every CheckInterval until ctx is done.
+func (m *ReplicaMonitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.config.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		m.Check(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *ReplicaMonitor) measureLag(ctx context.Context) (time.Duration, error) {
+	var standby bool
+	var seconds float64
+	if err := m.db.QueryRowContext(ctx, replicaLagQuery).Scan(&standby, &seconds); err != nil {
+		return 0, fmt.Errorf("failed to check read replica: %w", err)
+	}
+	if !standby {
+		return 0, fmt.Errorf("read replica is not in recovery")
+	}
+
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// setHealthy logs transitions only, so a steady state stays quiet.
+func (m *ReplicaMonitor) setHealthy(healthy bool, reason string) {
+	if m.healthy.Swap(healthy) == healthy {
+		return
+	}
+	if healthy {
+		log.Println("📖 Read replica healthy, routing reads to it")
+		return
+	}
+	log.Printf("⚠️  Read replica unavailable, routing reads to the primary: %s", reason)
+}
+
+// SetReadReplica routes reads outside transactions to replica while monitor
+// reports it healthy. Reads made after a write in the same request, see
+// domain.WithWriteTracking, always go to the primary.
+func (r *GoldenRepository) SetReadReplica(replica *sql.DB, monitor *ReplicaMonitor) {
+	r.replica = replica
+	r.monitor = monitor
+}
+
+// readConn picks the connection for a read and reports whether it is the
+// replica.
+func (r *GoldenRepository) readConn(ctx context.Context) (querier, bool) {
+	if r.tx != nil || r.replica == nil || !r.monitor.Healthy() || domain.HasWritten(ctx) {
+		return r.conn(), false
+	}
+	return r.replica, true
+}
@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"markitos-it-svc-goldens/internal/domain"
+)
+
+func TestReplicaMonitor_CheckUnreachable(t *testing.T) {
+	m := NewReplicaMonitor(helperClosedDB(t), DefaultReplicaConfig())
+	m.healthy.Store(true)
+
+	if err := m.Check(context.Background()); err == nil {
+		t.Fatal("expected an error from a closed replica")
+	}
+	if m.Healthy() {
+		t.Fatal("unreachable replica must be unhealthy")
+	}
+}
+
+func TestGoldenRepository_ReadConn(t *testing.T) {
+	primary, replica := helperClosedDB(t), helperClosedDB(t)
+	monitor := NewReplicaMonitor(replica, DefaultReplicaConfig())
+	r := NewGoldenRepository(primary)
+	r.SetReadReplica(replica, monitor)
+
+	written := domain.WithWriteTracking(context.Background())
+	domain.MarkWrite(written)
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		healthy     bool
+		wantReplica bool
+	}{
+		{name: "healthy-replica", ctx: context.Background(), healthy: true, wantReplica: true},
+		{name: "unhealthy-replica", ctx: context.Background(), healthy: false, wantReplica: false},
+		{name: "read-your-writes", ctx: written, healthy: true, wantReplica: false},
+		{name: "untouched-request", ctx: domain.WithWriteTracking(context.Background()), healthy: true, wantReplica: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			monitor.healthy.Store(tt.healthy)
+			conn, fromReplica := r.readConn(tt.ctx)
+			if fromReplica != tt.wantReplica {
+				t.Errorf("readConn() replica = %v, want %v", fromReplica, tt.wantReplica)
+			}
+			want := querier(primary)
+			if tt.wantReplica {
+				want = replica
+			}
+			if conn != want {
+				t.Errorf("readConn() returned the wrong connection")
+			}
+		})
+	}
+}
+
+func TestGoldenRepository_RetryReadFallsBackToPrimary(t *testing.T) {
+	primary, replica := helperClosedDB(t), helperClosedDB(t)
+	monitor := NewReplicaMonitor(replica, DefaultReplicaConfig())
+	monitor.healthy.Store(true)
+	r := NewGoldenRepository(primary)
+	r.SetRetryPolicy(RetryPolicy{MaxAttempts: 2, BaseBackoff: time.Millisecond, MaxBackoff: time.Millisecond})
+	r.SetReadReplica(replica, monitor)
+
+	var used []querier
+	err := r.retryRead(context.Background(), func(conn querier) error {
+		used = append(used, conn)
+		if conn == querier(replica) {
+			return driver.ErrBadConn
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("retryRead() error = %v", err)
+	}
+	if len(used) != 2 || used[0] != querier(replica) || used[1] != querier(primary) {
+		t.Fatalf("expected replica then primary, got %v", used)
+	}
+	if monitor.Healthy() {
+		t.Fatal("failed replica must be taken out of rotation")
+	}
+}
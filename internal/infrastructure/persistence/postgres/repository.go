@@ -5,17 +5,43 @@ import (
 	"database/sql"
 	"fmt"
 	"markitos-it-svc-goldens/internal/domain"
+	"strings"
 	"time"
 
 	"github.com/lib/pq"
 )
 
+// contentDigestColumns computes the same size and hash as domain.ContentDigest
+// inside PostgreSQL, so the basic view never transfers the content.
+const contentDigestColumns = `octet_length(content_b64), encode(sha256(convert_to(content_b64, 'UTF8')), 'hex')`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
 type GoldenRepository struct {
-	db *sql.DB
+	db    *sql.DB
+	retry RetryPolicy
+	// tx is set on the repositories handed out by RunInTx.
+	tx *sql.Tx
+	// replica serves reads while monitor reports it healthy, see SetReadReplica.
+	replica *sql.DB
+	monitor *ReplicaMonitor
 }
 
 func NewGoldenRepository(db *sql.DB) *GoldenRepository {
-	return &GoldenRepository{db: db}
+	return &GoldenRepository{db: db, retry: DefaultRetryPolicy()}
+}
+
+// SetRetryPolicy replaces the policy used to retry reads.
+func (r *GoldenRepository) SetRetryPolicy(policy RetryPolicy) {
+	r.retry = policy
 }
 
 func (r *GoldenRepository) InitSchema(ctx context.Context) error {
@@ -33,6 +59,45 @@ func (r *GoldenRepository) InitSchema(ctx context.Context) error {
 
 	CREATE INDEX IF NOT EXISTS idx_goldens_category ON goldens(category);
 	CREATE INDEX IF NOT EXISTS idx_goldens_updated_at ON goldens(updated_at DESC);
+	CREATE INDEX IF NOT EXISTS idx_goldens_tags ON goldens USING GIN (tags);
+
+	ALTER TABLE goldens ADD COLUMN IF NOT EXISTS slug VARCHAR(255);
+	CREATE UNIQUE INDEX IF NOT EXISTS ` + slugIndex + ` ON goldens(slug);
+
+	CREATE TABLE IF NOT EXISTS slug_redirects (
+		slug VARCHAR(255) PRIMARY KEY,
+		golden_id VARCHAR(255) NOT NULL REFERENCES goldens(id) ON DELETE CASCADE,
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_slug_redirects_golden_id ON slug_redirects(golden_id);
+
+	-- Goldens stored before the editorial workflow stay public; new ones
+	-- start as drafts.
+	ALTER TABLE goldens ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'published';
+	ALTER TABLE goldens ALTER COLUMN status SET DEFAULT 'draft';
+	ALTER TABLE goldens ADD COLUMN IF NOT EXISTS submitted_at TIMESTAMP;
+	ALTER TABLE goldens ADD COLUMN IF NOT EXISTS published_at TIMESTAMP;
+	ALTER TABLE goldens ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP;
+	CREATE INDEX IF NOT EXISTS idx_goldens_status ON goldens(status);
+
+	CREATE TABLE IF NOT EXISTS golden_transitions (
+		id BIGSERIAL PRIMARY KEY,
+		golden_id VARCHAR(255) NOT NULL REFERENCES goldens(id) ON DELETE CASCADE,
+		from_status VARCHAR(20) NOT NULL,
+		to_status VARCHAR(20) NOT NULL,
+		reason TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMP NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_golden_transitions_golden_id ON golden_transitions(golden_id, id);
+
+	-- The partial indexes keep the scheduler queries cheap: they only cover
+	-- the goldens it still has to publish or archive.
+	ALTER TABLE goldens ADD COLUMN IF NOT EXISTS publish_at TIMESTAMP;
+	ALTER TABLE goldens ADD COLUMN IF NOT EXISTS expire_at TIMESTAMP;
+	CREATE INDEX IF NOT EXISTS idx_goldens_publish_at ON goldens(publish_at) WHERE status = 'scheduled';
+	CREATE INDEX IF NOT EXISTS idx_goldens_expire_at ON goldens(expire_at) WHERE status = 'published';
 	`
 
 	_, err := r.db.ExecContext(ctx, schema)
@@ -40,6 +105,18 @@ func (r *GoldenRepository) InitSchema(ctx context.Context) error {
 		return fmt.Errorf("failed to initialize schema: %w", err)
 	}
 
+	if err := r.backfillSlugs(ctx); err != nil {
+		return err
+	}
+
+	if err := r.initCategorySchema(ctx); err != nil {
+		return err
+	}
+
+	if err := r.initOutboxSchema(ctx); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -59,24 +136,40 @@ func (r *GoldenRepository) SeedData(ctx context.Context) error {
 			ID:          "getting-started-keptn",
 			Title:       "Getting Started with Keptn",
 			Description: "A comprehensive guide to get started with Keptn for automated deployment and operations",
-			Category:    "DevOps",
+			Category:    "devops",
 			Tags:        []string{"keptn", "ci-cd", "automation", "kubernetes"},
 			UpdatedAt:   time.Now(),
 			ContentB64:  "IyBHZXR0aW5nIFN0YXJ0ZWQgd2l0aCBLZXB0bg==",
 			CoverImage:  "https://images.unsplash.com/photo-1667372393119-3d4c48d07fc9",
+			Status:      domain.StatusPublished,
+			PublishedAt: time.Now(),
 		},
 		{
 			ID:          "youtube-api-integration",
 			Title:       "YouTube Data API v3 Integration",
 			Description: "Complete guide to integrate YouTube Data API with practical examples",
-			Category:    "APIs",
+			Category:    "apis",
 			Tags:        []string{"youtube", "api", "rest", "video"},
 			UpdatedAt:   time.Now(),
 			ContentB64:  "IyBZb3VUdWJlIERhdGEgQVBJIHYzIEludGVncmF0aW9u",
 			CoverImage:  "https://images.unsplash.com/photo-1611162616475-46b635cb6868",
+			Status:      domain.StatusPublished,
+			PublishedAt: time.Now(),
 		},
 	}
 
+	categories := []domain.Category{
+		{ID: "devops", DisplayName: "DevOps", Description: "Delivery pipelines, automation and operations"},
+		{ID: "apis", DisplayName: "APIs", Description: "Integrations with third-party APIs"},
+	}
+	for _, category := range categories {
+		_, err := r.db.ExecContext(ctx, `INSERT INTO categories (id, display_name, description) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`,
+			category.ID, category.DisplayName, category.Description)
+		if err != nil {
+			return fmt.Errorf("failed to seed category %s: %w", category.ID, err)
+		}
+	}
+
 	for _, doc := range docs {
 		err := r.Create(ctx, &doc)
 		if err != nil {
@@ -87,88 +180,183 @@ func (r *GoldenRepository) SeedData(ctx context.Context) error {
 	return nil
 }
 
-func (r *GoldenRepository) GetAll(ctx context.Context) ([]domain.Golden, error) {
+func (r *GoldenRepository) GetAll(ctx context.Context, view domain.GoldenView) ([]domain.Golden, error) {
 	query := `
-		SELECT id, title, description, category, tags, updated_at, content_b64, cover_image
+		SELECT ` + selectColumns(view) + `
 		FROM goldens
+		WHERE ` + visibleCondition(ctx) + `
 		ORDER BY updated_at DESC
 	`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	var docs []domain.Golden
+	err := r.retryRead(ctx, func(conn querier) error {
+		docs = nil
+		rows, err := conn.QueryContext(ctx, query)
+		if err != nil {
+			return wrapError("failed to query goldens", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			doc, err := scanGolden(rows)
+			if err != nil {
+				return wrapError("failed to scan golden", err)
+			}
+
+			docs = append(docs, *doc)
+		}
+
+		if err := rows.Err(); err != nil {
+			return wrapError("error iterating goldens", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return docs, nil
+}
+
+func (r *GoldenRepository) GetByID(ctx context.Context, id string, view domain.GoldenView) (*domain.Golden, error) {
+	query := `
+		SELECT ` + selectColumns(view) + `
+		FROM goldens
+		WHERE id = $1 AND ` + visibleCondition(ctx) + `
+	`
+
+	var doc *domain.Golden
+	err := r.retryRead(ctx, func(conn querier) error {
+		var err error
+		doc, err = scanGolden(conn.QueryRowContext(ctx, query, id))
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: %s", domain.ErrNotFound, id)
+		}
+		if err != nil {
+			return wrapError("failed to query golden", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to query goldens: %w", err)
+		return nil, err
 	}
-	defer rows.Close()
+
+	return doc, nil
+}
+
+// GetByIDs reads all ids with a single query.
+func (r *GoldenRepository) GetByIDs(ctx context.Context, ids []string, view domain.GoldenView) ([]domain.Golden, error) {
+	query := `
+		SELECT ` + selectColumns(view) + `
+		FROM goldens
+		WHERE id = ANY($1) AND ` + visibleCondition(ctx) + `
+	`
 
 	var docs []domain.Golden
-	for rows.Next() {
-		var doc domain.Golden
-		var tags pq.StringArray
-
-		err := rows.Scan(
-			&doc.ID,
-			&doc.Title,
-			&doc.Description,
-			&doc.Category,
-			&tags,
-			&doc.UpdatedAt,
-			&doc.ContentB64,
-			&doc.CoverImage,
-		)
+	err := r.retryRead(ctx, func(conn querier) error {
+		docs = nil
+		rows, err := conn.QueryContext(ctx, query, pq.Array(ids))
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan golden: %w", err)
+			return wrapError("failed to query goldens by id", err)
 		}
+		defer rows.Close()
 
-		doc.Tags = []string(tags)
-		docs = append(docs, doc)
-	}
+		for rows.Next() {
+			doc, err := scanGolden(rows)
+			if err != nil {
+				return wrapError("failed to scan golden", err)
+			}
+
+			docs = append(docs, *doc)
+		}
 
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating goldens: %w", err)
+		if err := rows.Err(); err != nil {
+			return wrapError("error iterating goldens", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return docs, nil
 }
 
-func (r *GoldenRepository) GetByID(ctx context.Context, id string) (*domain.Golden, error) {
+func (r *GoldenRepository) GetByCategories(ctx context.Context, categories []string, view domain.GoldenView) ([]domain.Golden, error) {
 	query := `
-		SELECT id, title, description, category, tags, updated_at, content_b64, cover_image
+		SELECT ` + selectColumns(view) + `
 		FROM goldens
-		WHERE id = $1
+		WHERE category = ANY($1) AND ` + visibleCondition(ctx) + `
+		ORDER BY updated_at DESC
 	`
 
-	var doc domain.Golden
-	var tags pq.StringArray
+	var docs []domain.Golden
+	err := r.retryRead(ctx, func(conn querier) error {
+		docs = nil
+		rows, err := conn.QueryContext(ctx, query, pq.Array(categories))
+		if err != nil {
+			return wrapError("failed to query goldens by category", err)
+		}
+		defer rows.Close()
 
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&doc.ID,
-		&doc.Title,
-		&doc.Description,
-		&doc.Category,
-		&tags,
-		&doc.UpdatedAt,
-		&doc.ContentB64,
-		&doc.CoverImage,
-	)
+		for rows.Next() {
+			doc, err := scanGolden(rows)
+			if err != nil {
+				return wrapError("failed to scan golden", err)
+			}
+
+			docs = append(docs, *doc)
+		}
 
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("golden not found: %s", id)
+		if err := rows.Err(); err != nil {
+			return wrapError("error iterating goldens", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+
+	return docs, nil
+}
+
+func (r *GoldenRepository) GetBySlug(ctx context.Context, slug string, view domain.GoldenView) (*domain.Golden, error) {
+	query := `
+		SELECT ` + selectColumns(view) + `
+		FROM goldens
+		WHERE id = COALESCE(
+			(SELECT id FROM goldens WHERE slug = $1),
+			(SELECT golden_id FROM slug_redirects WHERE slug = $1)
+		) AND ` + visibleCondition(ctx) + `
+	`
+
+	var doc *domain.Golden
+	err := r.retryRead(ctx, func(conn querier) error {
+		var err error
+		doc, err = scanGolden(conn.QueryRowContext(ctx, query, slug))
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: slug %s", domain.ErrNotFound, slug)
+		}
+		if err != nil {
+			return wrapError("failed to query golden by slug", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to query golden: %w", err)
+		return nil, err
 	}
 
-	doc.Tags = []string(tags)
-	return &doc, nil
+	return doc, nil
 }
 
 func (r *GoldenRepository) Create(ctx context.Context, doc *domain.Golden) error {
 	query := `
-		INSERT INTO goldens (id, title, description, category, tags, updated_at, content_b64, cover_image)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO goldens (id, title, description, category, tags, updated_at, content_b64, cover_image, slug,
+			status, submitted_at, published_at, archived_at, publish_at, expire_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7, $8, NULLIF($9, ''), COALESCE(NULLIF($10, ''), 'draft'), $11, $12, $13, $14, $15)
 	`
 
-	_, err := r.db.ExecContext(
+	_, err := r.writeConn(ctx).ExecContext(
 		ctx,
 		query,
 		doc.ID,
@@ -179,67 +367,332 @@ func (r *GoldenRepository) Create(ctx context.Context, doc *domain.Golden) error
 		doc.UpdatedAt,
 		doc.ContentB64,
 		doc.CoverImage,
+		doc.Slug,
+		string(doc.Status),
+		nullTime(doc.SubmittedAt),
+		nullTime(doc.PublishedAt),
+		nullTime(doc.ArchivedAt),
+		nullTime(doc.PublishAt),
+		nullTime(doc.ExpireAt),
 	)
 
+	if isSlugConflict(err) {
+		return fmt.Errorf("%w: %s", domain.ErrSlugTaken, doc.Slug)
+	}
+	if isUnknownCategory(err) {
+		return fmt.Errorf("%w: %s", domain.ErrUnknownCategory, doc.Category)
+	}
+	if isUniqueViolation(err) {
+		return fmt.Errorf("%w: %s", domain.ErrAlreadyExists, doc.ID)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to create golden: %w", err)
+		return wrapError("failed to create golden", err)
 	}
 
+	r.notifyInvalidation(ctx, r.conn(), doc.ID)
 	return nil
 }
 
 func (r *GoldenRepository) Update(ctx context.Context, doc *domain.Golden) error {
+	// The CTE reads the slug before the update so a rename can be redirected.
 	query := `
+		WITH old AS (SELECT id, slug FROM goldens WHERE id = $1 FOR UPDATE)
 		UPDATE goldens
-		SET title = $2, description = $3, category = $4, tags = $5, updated_at = $6, content_b64 = $7, cover_image = $8
-		WHERE id = $1
+		SET title = $2, description = $3, category = NULLIF($4, ''), tags = $5, updated_at = $6, content_b64 = $7, cover_image = $8,
+			slug = COALESCE(NULLIF($9, ''), goldens.slug), status = COALESCE(NULLIF($10, ''), goldens.status),
+			submitted_at = CASE WHEN $10 = '' THEN goldens.submitted_at ELSE $11 END,
+			published_at = CASE WHEN $10 = '' THEN goldens.published_at ELSE $12 END,
+			archived_at = CASE WHEN $10 = '' THEN goldens.archived_at ELSE $13 END,
+			publish_at = $14, expire_at = $15
+		FROM old
+		WHERE goldens.id = old.id
+		RETURNING COALESCE(old.slug, ''), COALESCE(goldens.slug, ''),
+			goldens.status, goldens.submitted_at, goldens.published_at, goldens.archived_at
 	`
 
-	result, err := r.db.ExecContext(
-		ctx,
-		query,
-		doc.ID,
-		doc.Title,
-		doc.Description,
-		doc.Category,
-		pq.Array(doc.Tags),
-		doc.UpdatedAt,
-		doc.ContentB64,
-		doc.CoverImage,
-	)
+	return r.withTx(ctx, false, domain.NewTxOptions(), func(tx *GoldenRepository) error {
+		var oldSlug, newSlug string
+		var submittedAt, publishedAt, archivedAt sql.NullTime
+		err := tx.writeConn(ctx).QueryRowContext(
+			ctx,
+			query,
+			doc.ID,
+			doc.Title,
+			doc.Description,
+			doc.Category,
+			pq.Array(doc.Tags),
+			doc.UpdatedAt,
+			doc.ContentB64,
+			doc.CoverImage,
+			doc.Slug,
+			string(doc.Status),
+			nullTime(doc.SubmittedAt),
+			nullTime(doc.PublishedAt),
+			nullTime(doc.ArchivedAt),
+			nullTime(doc.PublishAt),
+			nullTime(doc.ExpireAt),
+		).Scan(&oldSlug, &newSlug, &doc.Status, &submittedAt, &publishedAt, &archivedAt)
+
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: %s", domain.ErrNotFound, doc.ID)
+		}
+		if isSlugConflict(err) {
+			return fmt.Errorf("%w: %s", domain.ErrSlugTaken, doc.Slug)
+		}
+		if isUnknownCategory(err) {
+			return fmt.Errorf("%w: %s", domain.ErrUnknownCategory, doc.Category)
+		}
+		if err != nil {
+			return wrapError("failed to update golden", err)
+		}
+
+		doc.Slug = newSlug
+		doc.SubmittedAt, doc.PublishedAt, doc.ArchivedAt = submittedAt.Time, publishedAt.Time, archivedAt.Time
+		if oldSlug != "" && oldSlug != newSlug {
+			if err := tx.redirectSlug(ctx, oldSlug, newSlug, doc.ID); err != nil {
+				return err
+			}
+		}
+
+		tx.notifyInvalidation(ctx, tx.conn(), doc.ID)
+		return nil
+	})
+}
 
+func (r *GoldenRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM goldens WHERE id = $1`
+
+	result, err := r.writeConn(ctx).ExecContext(ctx, query, id)
 	if err != nil {
-		return fmt.Errorf("failed to update golden: %w", err)
+		return wrapError("failed to delete golden", err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+		return wrapError("failed to get rows affected", err)
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("golden not found: %s", doc.ID)
+		return fmt.Errorf("%w: %s", domain.ErrNotFound, id)
 	}
 
+	r.notifyInvalidation(ctx, r.conn(), id)
 	return nil
 }
 
-func (r *GoldenRepository) Delete(ctx context.Context, id string) error {
-	query := `DELETE FROM goldens WHERE id = $1`
+func selectColumns(view domain.GoldenView) string {
+	content := "content_b64"
+	if view == domain.GoldenViewBasic {
+		content = "'' AS content_b64"
+	}
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	return "id, COALESCE(slug, ''), title, description, COALESCE(category, ''), tags, updated_at, " + content + ", cover_image, " +
+		"status, submitted_at, published_at, archived_at, publish_at, expire_at, " + contentDigestColumns
+}
+
+func scanGolden(row rowScanner) (*domain.Golden, error) {
+	var doc domain.Golden
+	var tags pq.StringArray
+	var submittedAt, publishedAt, archivedAt, publishAt, expireAt sql.NullTime
+
+	err := row.Scan(
+		&doc.ID,
+		&doc.Slug,
+		&doc.Title,
+		&doc.Description,
+		&doc.Category,
+		&tags,
+		&doc.UpdatedAt,
+		&doc.ContentB64,
+		&doc.CoverImage,
+		&doc.Status,
+		&submittedAt,
+		&publishedAt,
+		&archivedAt,
+		&publishAt,
+		&expireAt,
+		&doc.ContentSize,
+		&doc.ContentHash,
+	)
 	if err != nil {
-		return fmt.Errorf("failed to delete golden: %w", err)
+		return nil, err
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	doc.Tags = []string(tags)
+	doc.SubmittedAt = submittedAt.Time
+	doc.PublishedAt = publishedAt.Time
+	doc.ArchivedAt = archivedAt.Time
+	doc.PublishAt = publishAt.Time
+	doc.ExpireAt = expireAt.Time
+	return &doc, nil
+}
+
+// nullTime stores a zero time as NULL.
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+func (r *GoldenRepository) Upsert(ctx context.Context, docs []domain.Golden, dryRun bool) ([]domain.ImportResult, error) {
+	if len(docs) == 0 {
+		return nil, nil
+	}
+
+	const columns = 15
+	values := make([]string, 0, len(docs))
+	args := make([]any, 0, len(docs)*columns)
+	for i, doc := range docs {
+		base := i * columns
+		values = append(values, fmt.Sprintf("($%d, $%d, $%d, NULLIF($%d, ''), $%d, $%d, $%d, $%d, NULLIF($%d, ''), $%d, $%d, COALESCE(NULLIF($%d, ''), 'draft'), $%d, $%d, $%d)",
+			base+1, base+2, base+3, base+4, base+5, base+6, base+7, base+8, base+9, base+10, base+11, base+12, base+13, base+14, base+15))
+		args = append(args,
+			doc.ID,
+			doc.Title,
+			doc.Description,
+			doc.Category,
+			pq.Array(doc.Tags),
+			doc.UpdatedAt,
+			doc.ContentB64,
+			doc.CoverImage,
+			doc.Slug,
+			nullTime(doc.PublishAt),
+			nullTime(doc.ExpireAt),
+			string(doc.Status),
+			nullTime(doc.SubmittedAt),
+			nullTime(doc.PublishedAt),
+			nullTime(doc.ArchivedAt),
+		)
+	}
+
+	// Imports never rename: an existing slug wins over the imported one.
+	// Existing goldens keep their status here, and those imported with one
+	// get it from statusQuery, as the batch cannot tell them apart.
+	query := `
+		INSERT INTO goldens (id, title, description, category, tags, updated_at, content_b64, cover_image, slug, publish_at, expire_at,
+			status, submitted_at, published_at, archived_at)
+		VALUES ` + strings.Join(values, ", ") + `
+		ON CONFLICT (id) DO UPDATE
+		SET title = EXCLUDED.title, description = EXCLUDED.description, category = EXCLUDED.category,
+			tags = EXCLUDED.tags, updated_at = EXCLUDED.updated_at, content_b64 = EXCLUDED.content_b64,
+			cover_image = EXCLUDED.cover_image, slug = COALESCE(goldens.slug, EXCLUDED.slug),
+			publish_at = COALESCE(EXCLUDED.publish_at, goldens.publish_at), expire_at = COALESCE(EXCLUDED.expire_at, goldens.expire_at)
+		RETURNING id, (xmax = 0) AS inserted
+	`
+	statusQuery := `UPDATE goldens SET status = $2, submitted_at = $3, published_at = $4, archived_at = $5 WHERE id = $1`
+
+	var results []domain.ImportResult
+	err := r.withTx(ctx, dryRun, domain.NewTxOptions(), func(tx *GoldenRepository) error {
+		rows, err := tx.conn().QueryContext(ctx, query, args...)
+		if isSlugConflict(err) {
+			return fmt.Errorf("%w: %s", domain.ErrSlugTaken, err)
+		}
+		if isUnknownCategory(err) {
+			return fmt.Errorf("%w: %s", domain.ErrUnknownCategory, err)
+		}
+		if err != nil {
+			return wrapError("failed to upsert goldens", err)
+		}
+
+		results = make([]domain.ImportResult, 0, len(docs))
+		for rows.Next() {
+			var id string
+			var inserted bool
+			if err := rows.Scan(&id, &inserted); err != nil {
+				rows.Close()
+				return wrapError("failed to scan upsert result", err)
+			}
+
+			status := domain.ImportStatusUpdated
+			if inserted {
+				status = domain.ImportStatusCreated
+			}
+			results = append(results, domain.ImportResult{ID: id, Status: status})
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return wrapError("error iterating upsert results", err)
+		}
+
+		created := make(map[string]bool, len(results))
+		for _, result := range results {
+			created[result.ID] = result.Status == domain.ImportStatusCreated
+		}
+		for _, doc := range docs {
+			if doc.Status == "" || created[doc.ID] {
+				continue
+			}
+			_, err := tx.conn().ExecContext(ctx, statusQuery, doc.ID, string(doc.Status),
+				nullTime(doc.SubmittedAt), nullTime(doc.PublishedAt), nullTime(doc.ArchivedAt))
+			if err != nil {
+				return wrapError("failed to import golden status", err)
+			}
+		}
+
+		if dryRun {
+			return nil
+		}
+
+		// Inside the transaction the notifications are only delivered on commit.
+		ids := make([]string, 0, len(results))
+		for _, result := range results {
+			ids = append(ids, result.ID)
+		}
+		tx.notifyInvalidation(ctx, tx.conn(), ids...)
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+		return nil, err
 	}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("golden not found: %s", id)
+	return results, nil
+}
+
+func (r *GoldenRepository) AppendTransition(ctx context.Context, transition domain.GoldenTransition) error {
+	query := `
+		INSERT INTO golden_transitions (golden_id, from_status, to_status, reason, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := r.writeConn(ctx).ExecContext(ctx, query, transition.GoldenID, string(transition.From), string(transition.To), transition.Reason, transition.At)
+	if err != nil {
+		return wrapError("failed to append golden transition", err)
 	}
 
 	return nil
 }
+
+func (r *GoldenRepository) ListTransitions(ctx context.Context, id string) ([]domain.GoldenTransition, error) {
+	query := `
+		SELECT golden_id, from_status, to_status, reason, created_at
+		FROM golden_transitions
+		WHERE golden_id = $1
+		ORDER BY id
+	`
+
+	var transitions []domain.GoldenTransition
+	err := r.retryRead(ctx, func(conn querier) error {
+		transitions = nil
+		rows, err := conn.QueryContext(ctx, query, id)
+		if err != nil {
+			return wrapError("failed to query golden transitions", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var t domain.GoldenTransition
+			if err := rows.Scan(&t.GoldenID, &t.From, &t.To, &t.Reason, &t.At); err != nil {
+				return wrapError("failed to scan golden transition", err)
+			}
+			transitions = append(transitions, t)
+		}
+
+		if err := rows.Err(); err != nil {
+			return wrapError("error iterating golden transitions", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return transitions, nil
+}
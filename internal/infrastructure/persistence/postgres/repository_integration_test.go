@@ -3,16 +3,23 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"markitos-it-svc-goldens/internal/domain"
 	"os"
 	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/lib/pq"
 )
 
-func helperIntegrationDB(t *testing.T) *sql.DB {
+func helperIntegrationDSN(t *testing.T) string {
 	t.Helper()
 
 	host := os.Getenv("DB_HOST")
@@ -24,8 +31,13 @@ func helperIntegrationDB(t *testing.T) *sql.DB {
 		t.Skip("integration DB not configured; set DB_HOST, DB_PORT, DB_USER, DB_PASS, DB_NAME")
 	}
 
-	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable", host, port, user, pass, name)
-	db, err := sql.Open("postgres", dsn)
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable", host, port, user, pass, name)
+}
+
+func helperIntegrationDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("postgres", helperIntegrationDSN(t))
 	if err != nil {
 		t.Fatalf("failed to open integration db: %v", err)
 	}
@@ -42,15 +54,34 @@ func helperIntegrationDB(t *testing.T) *sql.DB {
 
 func helperEnsureSchemaAndClean(t *testing.T, r *GoldenRepository) {
 	t.Helper()
-	ctx := context.Background()
+	ctx := helperEditorContext()
 	if err := r.InitSchema(ctx); err != nil {
 		t.Fatalf("InitSchema() failed: %v", err)
 	}
-	if _, err := r.db.ExecContext(ctx, "TRUNCATE TABLE goldens"); err != nil {
+	if _, err := r.db.ExecContext(ctx, "TRUNCATE TABLE goldens CASCADE"); err != nil {
 		t.Fatalf("failed to truncate goldens: %v", err)
 	}
 }
 
+// helperEnsureCategories creates the categories goldens are about to refer to.
+func helperEnsureCategories(t *testing.T, db *sql.DB, ids ...string) {
+	t.Helper()
+	for _, id := range ids {
+		_, err := db.Exec(`INSERT INTO categories (id, display_name) VALUES ($1, $1) ON CONFLICT DO NOTHING`, id)
+		if err != nil {
+			t.Fatalf("failed to insert category %s: %v", id, err)
+		}
+	}
+}
+
+// helperCategorizedGolden is helperRandomGolden with its category created.
+func helperCategorizedGolden(t *testing.T, db *sql.DB) *domain.Golden {
+	t.Helper()
+	doc := helperRandomGolden(t)
+	helperEnsureCategories(t, db, doc.Category)
+	return doc
+}
+
 func helperInsertDocDirect(t *testing.T, db *sql.DB, doc *domain.Golden) {
 	t.Helper()
 	_, err := db.Exec(
@@ -67,7 +98,7 @@ func TestGoldenRepository_InitSchema_Success_Integration(t *testing.T) {
 	db := helperIntegrationDB(t)
 	r := NewGoldenRepository(db)
 
-	if err := r.InitSchema(context.Background()); err != nil {
+	if err := r.InitSchema(helperEditorContext()); err != nil {
 		t.Fatalf("InitSchema() unexpected error: %v", err)
 	}
 }
@@ -77,10 +108,10 @@ func TestGoldenRepository_SeedData_CountGreaterThanZero_Integration(t *testing.T
 	r := NewGoldenRepository(db)
 	helperEnsureSchemaAndClean(t, r)
 
-	doc := helperRandomGolden(t)
+	doc := helperCategorizedGolden(t, db)
 	helperInsertDocDirect(t, db, doc)
 
-	if err := r.SeedData(context.Background()); err != nil {
+	if err := r.SeedData(helperEditorContext()); err != nil {
 		t.Fatalf("SeedData() unexpected error: %v", err)
 	}
 
@@ -98,7 +129,7 @@ func TestGoldenRepository_SeedData_Success_Integration(t *testing.T) {
 	r := NewGoldenRepository(db)
 	helperEnsureSchemaAndClean(t, r)
 
-	if err := r.SeedData(context.Background()); err != nil {
+	if err := r.SeedData(helperEditorContext()); err != nil {
 		t.Fatalf("SeedData() unexpected error: %v", err)
 	}
 
@@ -116,10 +147,10 @@ func TestGoldenRepository_GetAll_Success_Integration(t *testing.T) {
 	r := NewGoldenRepository(db)
 	helperEnsureSchemaAndClean(t, r)
 
-	doc := helperRandomGolden(t)
+	doc := helperCategorizedGolden(t, db)
 	helperInsertDocDirect(t, db, doc)
 
-	docs, err := r.GetAll(context.Background())
+	docs, err := r.GetAll(helperEditorContext(), domain.GoldenViewFull)
 	if err != nil {
 		t.Fatalf("GetAll() unexpected error: %v", err)
 	}
@@ -150,12 +181,41 @@ func TestGoldenRepository_GetAll_Success_Integration(t *testing.T) {
 	}
 }
 
+func TestGoldenRepository_GetAll_BasicView_Integration(t *testing.T) {
+	db := helperIntegrationDB(t)
+	r := NewGoldenRepository(db)
+	helperEnsureSchemaAndClean(t, r)
+
+	doc := helperCategorizedGolden(t, db)
+	helperInsertDocDirect(t, db, doc)
+
+	docs, err := r.GetAll(helperEditorContext(), domain.GoldenViewBasic)
+	if err != nil {
+		t.Fatalf("GetAll() unexpected error: %v", err)
+	}
+	if len(docs) != 1 {
+		t.Fatalf("expected 1 doc, got %d", len(docs))
+	}
+
+	wantSize, wantHash := domain.ContentDigest(doc.ContentB64)
+	got := docs[0]
+	if got.ContentB64 != "" {
+		t.Errorf("ContentB64: want empty, got %q", got.ContentB64)
+	}
+	if got.ContentSize != wantSize {
+		t.Errorf("ContentSize: want %d, got %d", wantSize, got.ContentSize)
+	}
+	if got.ContentHash != wantHash {
+		t.Errorf("ContentHash: want %q, got %q", wantHash, got.ContentHash)
+	}
+}
+
 func TestGoldenRepository_GetByID_NotFound_Integration(t *testing.T) {
 	db := helperIntegrationDB(t)
 	r := NewGoldenRepository(db)
 	helperEnsureSchemaAndClean(t, r)
 
-	_, err := r.GetByID(context.Background(), "missing-id")
+	_, err := r.GetByID(helperEditorContext(), "missing-id", domain.GoldenViewFull)
 	if err == nil {
 		t.Fatalf("GetByID() expected not found error")
 	}
@@ -166,10 +226,10 @@ func TestGoldenRepository_GetByID_Success_Integration(t *testing.T) {
 	r := NewGoldenRepository(db)
 	helperEnsureSchemaAndClean(t, r)
 
-	doc := helperRandomGolden(t)
+	doc := helperCategorizedGolden(t, db)
 	helperInsertDocDirect(t, db, doc)
 
-	got, err := r.GetByID(context.Background(), doc.ID)
+	got, err := r.GetByID(helperEditorContext(), doc.ID, domain.GoldenViewFull)
 	if err != nil {
 		t.Fatalf("GetByID() unexpected error: %v", err)
 	}
@@ -202,13 +262,104 @@ func TestGoldenRepository_GetByID_Success_Integration(t *testing.T) {
 	}
 }
 
+func TestGoldenRepository_GetByIDs_Integration(t *testing.T) {
+	db := helperIntegrationDB(t)
+	r := NewGoldenRepository(db)
+	helperEnsureSchemaAndClean(t, r)
+
+	first, second := helperCategorizedGolden(t, db), helperCategorizedGolden(t, db)
+	helperInsertDocDirect(t, db, first)
+	helperInsertDocDirect(t, db, second)
+
+	docs, err := r.GetByIDs(helperEditorContext(), []string{second.ID, "missing", first.ID}, domain.GoldenViewBasic)
+	if err != nil {
+		t.Fatalf("GetByIDs() unexpected error: %v", err)
+	}
+	got := map[string]domain.Golden{}
+	for _, doc := range docs {
+		got[doc.ID] = doc
+	}
+	if len(docs) != 2 || got[first.ID].Title != first.Title || got[second.ID].Title != second.Title {
+		t.Fatalf("GetByIDs() = %+v", docs)
+	}
+	if got[first.ID].ContentB64 != "" || got[first.ID].ContentHash == "" {
+		t.Errorf("basic view: want digest without content, got %+v", got[first.ID])
+	}
+}
+
+func TestGoldenRepository_CountFacets_Integration(t *testing.T) {
+	db := helperIntegrationDB(t)
+	r := NewGoldenRepository(db)
+	helperEnsureSchemaAndClean(t, r)
+	ctx := helperEditorContext()
+	helperEnsureCategories(t, db, "devops", "apis")
+	for _, doc := range []domain.Golden{
+		{ID: "a", Title: "A", Category: "devops", Tags: []string{"k8s", "ci-cd", "k8s"}},
+		{ID: "b", Title: "B", Category: "devops", Tags: []string{"k8s"}},
+		{ID: "c", Title: "C", Category: "apis", Tags: []string{"rest"}},
+		{ID: "d", Title: "D", Tags: []string{"rest"}},
+		{ID: "draft", Title: "Draft", Category: "apis", Tags: []string{"k8s"}, Status: domain.StatusDraft},
+	} {
+		if doc.Status == "" {
+			doc.Status = domain.StatusPublished
+		}
+		doc.UpdatedAt = time.Now().UTC()
+		if err := r.Create(ctx, &doc); err != nil {
+			t.Fatalf("Create() unexpected error: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		count func() ([]domain.FacetCount, error)
+		want  []domain.FacetCount
+	}{
+		{
+			name:  "categories",
+			count: func() ([]domain.FacetCount, error) { return r.CountCategories(ctx, "") },
+			want:  []domain.FacetCount{{Value: "devops", Count: 2}, {Value: "apis", Count: 1}},
+		},
+		{
+			name:  "categories-by-tag",
+			count: func() ([]domain.FacetCount, error) { return r.CountCategories(ctx, "rest") },
+			want:  []domain.FacetCount{{Value: "apis", Count: 1}},
+		},
+		{
+			name:  "tags",
+			count: func() ([]domain.FacetCount, error) { return r.CountTags(ctx, nil) },
+			want:  []domain.FacetCount{{Value: "k8s", Count: 2}, {Value: "rest", Count: 2}, {Value: "ci-cd", Count: 1}},
+		},
+		{
+			name:  "tags-by-category",
+			count: func() ([]domain.FacetCount, error) { return r.CountTags(ctx, []string{"apis"}) },
+			want:  []domain.FacetCount{{Value: "rest", Count: 1}},
+		},
+		{
+			name:  "tags-by-categories",
+			count: func() ([]domain.FacetCount, error) { return r.CountTags(ctx, []string{"apis", "devops"}) },
+			want:  []domain.FacetCount{{Value: "k8s", Count: 2}, {Value: "ci-cd", Count: 1}, {Value: "rest", Count: 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.count()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestGoldenRepository_Create_Success_Integration(t *testing.T) {
 	db := helperIntegrationDB(t)
 	r := NewGoldenRepository(db)
 	helperEnsureSchemaAndClean(t, r)
 
-	doc := helperRandomGolden(t)
-	if err := r.Create(context.Background(), doc); err != nil {
+	doc := helperCategorizedGolden(t, db)
+	if err := r.Create(helperEditorContext(), doc); err != nil {
 		t.Fatalf("Create() unexpected error: %v", err)
 	}
 
@@ -226,8 +377,8 @@ func TestGoldenRepository_Update_NotFound_Integration(t *testing.T) {
 	r := NewGoldenRepository(db)
 	helperEnsureSchemaAndClean(t, r)
 
-	doc := helperRandomGolden(t)
-	if err := r.Update(context.Background(), doc); err == nil {
+	doc := helperCategorizedGolden(t, db)
+	if err := r.Update(helperEditorContext(), doc); err == nil {
 		t.Fatalf("Update() expected not found error")
 	}
 }
@@ -237,11 +388,11 @@ func TestGoldenRepository_Update_Success_Integration(t *testing.T) {
 	r := NewGoldenRepository(db)
 	helperEnsureSchemaAndClean(t, r)
 
-	doc := helperRandomGolden(t)
+	doc := helperCategorizedGolden(t, db)
 	helperInsertDocDirect(t, db, doc)
 
 	doc.Title = doc.Title + "-updated"
-	if err := r.Update(context.Background(), doc); err != nil {
+	if err := r.Update(helperEditorContext(), doc); err != nil {
 		t.Fatalf("Update() unexpected error: %v", err)
 	}
 
@@ -259,7 +410,7 @@ func TestGoldenRepository_Delete_NotFound_Integration(t *testing.T) {
 	r := NewGoldenRepository(db)
 	helperEnsureSchemaAndClean(t, r)
 
-	if err := r.Delete(context.Background(), "missing-id"); err == nil {
+	if err := r.Delete(helperEditorContext(), "missing-id"); err == nil {
 		t.Fatalf("Delete() expected not found error")
 	}
 }
@@ -269,10 +420,10 @@ func TestGoldenRepository_Delete_Success_Integration(t *testing.T) {
 	r := NewGoldenRepository(db)
 	helperEnsureSchemaAndClean(t, r)
 
-	doc := helperRandomGolden(t)
+	doc := helperCategorizedGolden(t, db)
 	helperInsertDocDirect(t, db, doc)
 
-	if err := r.Delete(context.Background(), doc.ID); err != nil {
+	if err := r.Delete(helperEditorContext(), doc.ID); err != nil {
 		t.Fatalf("Delete() unexpected error: %v", err)
 	}
 
@@ -284,3 +435,760 @@ func TestGoldenRepository_Delete_Success_Integration(t *testing.T) {
 		t.Fatalf("expected 0 rows for id=%s, got %d", doc.ID, count)
 	}
 }
+
+func TestGoldenRepository_Upsert_CreatesAndUpdates_Integration(t *testing.T) {
+	db := helperIntegrationDB(t)
+	r := NewGoldenRepository(db)
+	helperEnsureSchemaAndClean(t, r)
+
+	existing := helperCategorizedGolden(t, db)
+	helperInsertDocDirect(t, db, existing)
+	created := helperCategorizedGolden(t, db)
+
+	existing.Title = existing.Title + "-updated"
+	results, err := r.Upsert(helperEditorContext(), []domain.Golden{*existing, *created}, false)
+	if err != nil {
+		t.Fatalf("Upsert() unexpected error: %v", err)
+	}
+
+	statuses := map[string]domain.ImportStatus{}
+	for _, result := range results {
+		statuses[result.ID] = result.Status
+	}
+	if statuses[existing.ID] != domain.ImportStatusUpdated {
+		t.Errorf("expected %s updated, got %v", existing.ID, statuses[existing.ID])
+	}
+	if statuses[created.ID] != domain.ImportStatusCreated {
+		t.Errorf("expected %s created, got %v", created.ID, statuses[created.ID])
+	}
+
+	var title string
+	if err := db.QueryRow("SELECT title FROM goldens WHERE id = $1", existing.ID).Scan(&title); err != nil {
+		t.Fatalf("verification query failed: %v", err)
+	}
+	if title != existing.Title {
+		t.Fatalf("expected updated title=%s, got %s", existing.Title, title)
+	}
+}
+
+func TestGoldenRepository_Upsert_DryRunRollsBack_Integration(t *testing.T) {
+	db := helperIntegrationDB(t)
+	r := NewGoldenRepository(db)
+	helperEnsureSchemaAndClean(t, r)
+
+	doc := helperCategorizedGolden(t, db)
+	results, err := r.Upsert(helperEditorContext(), []domain.Golden{*doc}, true)
+	if err != nil {
+		t.Fatalf("Upsert() unexpected error: %v", err)
+	}
+	if len(results) != 1 || results[0].Status != domain.ImportStatusCreated {
+		t.Fatalf("expected one created result, got %+v", results)
+	}
+
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM goldens WHERE id = $1", doc.ID).Scan(&count); err != nil {
+		t.Fatalf("verification query failed: %v", err)
+	}
+	if count != 0 {
+		t.Fatalf("expected dry run to leave no rows, got %d", count)
+	}
+}
+
+func TestInvalidationListener_ReceivesWrites_Integration(t *testing.T) {
+	db := helperIntegrationDB(t)
+	r := NewGoldenRepository(db)
+	helperEnsureSchemaAndClean(t, r)
+
+	target := &recordingInvalidator{}
+	listener := NewInvalidationListener(helperIntegrationDSN(t), target)
+	ctx, cancel := context.WithCancel(helperEditorContext())
+	t.Cleanup(cancel)
+	go listener.Run(ctx)
+
+	doc := helperCategorizedGolden(t, db)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		// Keep writing until the listener is subscribed and sees the id.
+		_ = r.Delete(helperEditorContext(), doc.ID)
+		if err := r.Create(helperEditorContext(), doc); err != nil {
+			t.Fatalf("Create() unexpected error: %v", err)
+		}
+		time.Sleep(50 * time.Millisecond)
+
+		invalidated, _ := target.snapshot()
+		if slices.Contains(invalidated, doc.ID) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected invalidation for %s, got %v", doc.ID, invalidated)
+		}
+	}
+}
+
+func TestGoldenRepository_RunInTx_RollsBackChangeAndEvents_Integration(t *testing.T) {
+	db := helperIntegrationDB(t)
+	r := NewGoldenRepository(db)
+	helperEnsureSchemaAndClean(t, r)
+	ctx := helperEditorContext()
+
+	doc := helperCategorizedGolden(t, db)
+	event, err := domain.NewGoldenEvent(domain.EventGoldenCreated, *doc)
+	if err != nil {
+		t.Fatalf("NewGoldenEvent() unexpected error: %v", err)
+	}
+
+	want := fmt.Errorf("abort")
+	err = r.RunInTx(ctx, func(tx domain.Repository) error {
+		if err := tx.Create(ctx, doc); err != nil {
+			return err
+		}
+		if err := tx.AppendEvents(ctx, event); err != nil {
+			return err
+		}
+		return want
+	})
+	if err != want {
+		t.Fatalf("RunInTx() error = %v, want %v", err, want)
+	}
+
+	if _, err := r.GetByID(ctx, doc.ID, domain.GoldenViewBasic); err == nil {
+		t.Fatal("expected golden to be rolled back")
+	}
+	var count int
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM outbox_events WHERE event_id = $1", event.ID).Scan(&count); err != nil {
+		t.Fatalf("failed to count outbox events: %v", err)
+	}
+	if count != 0 {
+		t.Fatalf("expected event to be rolled back, found %d", count)
+	}
+}
+
+func TestOutboxRepository_ClaimDeliverAndDeadLetter_Integration(t *testing.T) {
+	db := helperIntegrationDB(t)
+	r := NewGoldenRepository(db)
+	helperEnsureSchemaAndClean(t, r)
+	ctx := helperEditorContext()
+	if _, err := db.ExecContext(ctx, "TRUNCATE TABLE outbox_events"); err != nil {
+		t.Fatalf("failed to truncate outbox_events: %v", err)
+	}
+
+	var events []domain.Event
+	for _, eventType := range []domain.EventType{domain.EventGoldenCreated, domain.EventGoldenUpdated} {
+		event, err := domain.NewGoldenEvent(eventType, *helperCategorizedGolden(t, db))
+		if err != nil {
+			t.Fatalf("NewGoldenEvent() unexpected error: %v", err)
+		}
+		events = append(events, event)
+	}
+	if err := r.AppendEvents(ctx, events...); err != nil {
+		t.Fatalf("AppendEvents() unexpected error: %v", err)
+	}
+
+	outbox := NewOutboxRepository(db)
+	claimed, err := outbox.ClaimPending(ctx, 10, time.Minute)
+	if err != nil {
+		t.Fatalf("ClaimPending() unexpected error: %v", err)
+	}
+	if len(claimed) != 2 || claimed[0].ID != events[0].ID || claimed[1].ID != events[1].ID {
+		t.Fatalf("expected both events in order, got %+v", claimed)
+	}
+	if claimed[0].Attempts != 1 {
+		t.Fatalf("expected first attempt, got %d", claimed[0].Attempts)
+	}
+
+	// Leased events are hidden from other relays.
+	again, err := outbox.ClaimPending(ctx, 10, time.Minute)
+	if err != nil {
+		t.Fatalf("ClaimPending() unexpected error: %v", err)
+	}
+	if len(again) != 0 {
+		t.Fatalf("expected leased events to be skipped, got %d", len(again))
+	}
+
+	if err := outbox.MarkDelivered(ctx, events[0].ID); err != nil {
+		t.Fatalf("MarkDelivered() unexpected error: %v", err)
+	}
+	if err := outbox.MarkFailed(ctx, events[1].ID, fmt.Errorf("broker down"), 0, true); err != nil {
+		t.Fatalf("MarkFailed() unexpected error: %v", err)
+	}
+
+	statuses := map[string]string{}
+	rows, err := db.QueryContext(ctx, "SELECT event_id, status FROM outbox_events")
+	if err != nil {
+		t.Fatalf("failed to query outbox: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, status string
+		if err := rows.Scan(&id, &status); err != nil {
+			t.Fatalf("failed to scan outbox row: %v", err)
+		}
+		statuses[id] = status
+	}
+	want := map[string]string{events[0].ID: outboxStatusDelivered, events[1].ID: outboxStatusDead}
+	if !reflect.DeepEqual(statuses, want) {
+		t.Fatalf("expected statuses %v, got %v", want, statuses)
+	}
+}
+
+func TestWebhookRepository_SubscriptionsAndDeliveries_Integration(t *testing.T) {
+	db := helperIntegrationDB(t)
+	r := NewWebhookRepository(db)
+	ctx := helperEditorContext()
+	if err := r.InitSchema(ctx); err != nil {
+		t.Fatalf("InitSchema() unexpected error: %v", err)
+	}
+
+	prefix := domain.HelperRandomAlphaPrefix(t, 8)
+	sub := &domain.WebhookSubscription{
+		ID:         prefix,
+		URL:        "https://hooks.example.com/" + prefix,
+		Secret:     prefix + "-secret",
+		EventTypes: []domain.EventType{domain.EventGoldenCreated},
+		Categories: []string{"DevOps"},
+		CreatedAt:  time.Now().UTC().Truncate(time.Microsecond),
+	}
+	if err := r.CreateSubscription(ctx, sub); err != nil {
+		t.Fatalf("CreateSubscription() unexpected error: %v", err)
+	}
+
+	delivery := &domain.WebhookDelivery{SubscriptionID: prefix, EventID: prefix + "-event", EventType: domain.EventGoldenCreated, Attempt: 1, StatusCode: 200, Succeeded: true}
+	if err := r.RecordDelivery(ctx, delivery); err != nil {
+		t.Fatalf("RecordDelivery() unexpected error: %v", err)
+	}
+
+	deliveries, err := r.ListDeliveries(ctx, prefix, 10)
+	if err != nil {
+		t.Fatalf("ListDeliveries() unexpected error: %v", err)
+	}
+	if len(deliveries) != 1 || deliveries[0].ID != delivery.ID || !deliveries[0].Succeeded {
+		t.Fatalf("unexpected deliveries: %+v", deliveries)
+	}
+	deliveries, err = r.ListEventDeliveries(ctx, prefix+"-event")
+	if err != nil {
+		t.Fatalf("ListEventDeliveries() unexpected error: %v", err)
+	}
+	if len(deliveries) != 1 || deliveries[0].ID != delivery.ID {
+		t.Fatalf("unexpected event deliveries: %+v", deliveries)
+	}
+
+	if err := r.DeleteSubscription(ctx, prefix); err != nil {
+		t.Fatalf("DeleteSubscription() unexpected error: %v", err)
+	}
+	if err := r.DeleteSubscription(ctx, prefix); !errors.Is(err, domain.ErrWebhookNotFound) {
+		t.Fatalf("expected ErrWebhookNotFound, got %v", err)
+	}
+	if deliveries, _ := r.ListDeliveries(ctx, prefix, 10); len(deliveries) != 0 {
+		t.Fatalf("expected deliveries to be removed with the subscription, got %d", len(deliveries))
+	}
+}
+
+func TestGoldenRepository_RunInTx_RetriesSerializationFailures_Integration(t *testing.T) {
+	db := helperIntegrationDB(t)
+	r := NewGoldenRepository(db)
+	helperEnsureSchemaAndClean(t, r)
+	ctx := helperEditorContext()
+
+	doc := helperCategorizedGolden(t, db)
+	doc.Title = "0"
+	helperInsertDocDirect(t, db, doc)
+
+	// Both transactions read before either writes, so one of them must fail
+	// with a serialization error and succeed when rerun.
+	var ready sync.WaitGroup
+	ready.Add(2)
+	var attempts atomic.Int32
+	increment := func(tx domain.Repository) error {
+		current, err := tx.GetByID(ctx, doc.ID, domain.GoldenViewFull)
+		if err != nil {
+			return err
+		}
+		if attempts.Add(1) <= 2 {
+			ready.Done()
+			ready.Wait()
+		}
+
+		n, _ := strconv.Atoi(current.Title)
+		current.Title = strconv.Itoa(n + 1)
+		return tx.Update(ctx, current)
+	}
+
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			errs <- r.RunInTx(ctx, increment, domain.WithIsolation(domain.IsolationSerializable))
+		}()
+	}
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != nil {
+			t.Fatalf("RunInTx() unexpected error: %v", err)
+		}
+	}
+
+	got, err := r.GetByID(ctx, doc.ID, domain.GoldenViewBasic)
+	if err != nil {
+		t.Fatalf("GetByID() unexpected error: %v", err)
+	}
+	if got.Title != "2" {
+		t.Fatalf("expected both increments to apply, got title %q", got.Title)
+	}
+	if attempts.Load() < 3 {
+		t.Fatalf("expected a retried attempt, got %d attempts", attempts.Load())
+	}
+}
+
+func TestIdempotencyRepository_ReserveCompleteRelease_Integration(t *testing.T) {
+	db := helperIntegrationDB(t)
+	r := NewIdempotencyRepository(db)
+	ctx := helperEditorContext()
+	if err := r.InitSchema(ctx); err != nil {
+		t.Fatalf("InitSchema() unexpected error: %v", err)
+	}
+
+	key := domain.HelperRandomAlphaPrefix(t, 12)
+	const method = "/goldens.v1.GoldenService/CreateGolden"
+
+	record, err := r.Reserve(ctx, key, method, "hash-1", time.Minute)
+	if err != nil || record != nil {
+		t.Fatalf("first Reserve() = %+v, %v; want the key", record, err)
+	}
+	record, err = r.Reserve(ctx, key, method, "hash-1", time.Minute)
+	if err != nil || record == nil || record.Response != nil {
+		t.Fatalf("second Reserve() = %+v, %v; want an in-progress record", record, err)
+	}
+
+	if err := r.Release(ctx, key, method); err != nil {
+		t.Fatalf("Release() unexpected error: %v", err)
+	}
+	if record, err := r.Reserve(ctx, key, method, "hash-2", time.Minute); err != nil || record != nil {
+		t.Fatalf("Reserve() after Release = %+v, %v; want the key", record, err)
+	}
+
+	if err := r.Complete(ctx, key, method, []byte("response"), time.Hour); err != nil {
+		t.Fatalf("Complete() unexpected error: %v", err)
+	}
+	record, err = r.Reserve(ctx, key, method, "hash-2", time.Minute)
+	if err != nil || record == nil || string(record.Response) != "response" || record.RequestHash != "hash-2" {
+		t.Fatalf("Reserve() after Complete = %+v, %v; want the stored response", record, err)
+	}
+
+	// An expired record is taken over by the next request.
+	if _, err := db.ExecContext(ctx, "UPDATE idempotency_keys SET expires_at = now() - interval '1 second' WHERE key = $1", key); err != nil {
+		t.Fatalf("failed to expire key: %v", err)
+	}
+	if record, err := r.Reserve(ctx, key, method, "hash-3", time.Minute); err != nil || record != nil {
+		t.Fatalf("Reserve() after expiry = %+v, %v; want the key", record, err)
+	}
+}
+
+func TestGoldenRepository_SlugRedirects_Integration(t *testing.T) {
+	db := helperIntegrationDB(t)
+	r := NewGoldenRepository(db)
+	helperEnsureSchemaAndClean(t, r)
+	ctx := helperEditorContext()
+
+	doc := helperCategorizedGolden(t, db)
+	doc.Slug = "first"
+	if err := r.Create(ctx, doc); err != nil {
+		t.Fatalf("Create() unexpected error: %v", err)
+	}
+
+	other := helperCategorizedGolden(t, db)
+	other.Slug = "first"
+	if err := r.Create(ctx, other); !errors.Is(err, domain.ErrAlreadyExists) {
+		t.Fatalf("Create() with a taken slug: expected ErrAlreadyExists, got %v", err)
+	}
+
+	doc.Slug = "second"
+	if err := r.Update(ctx, doc); err != nil {
+		t.Fatalf("Update() unexpected error: %v", err)
+	}
+	for _, slug := range []string{"first", "second"} {
+		got, err := r.GetBySlug(ctx, slug, domain.GoldenViewBasic)
+		if err != nil || got.ID != doc.ID || got.Slug != "second" {
+			t.Fatalf("GetBySlug(%q) = %+v, %v", slug, got, err)
+		}
+	}
+
+	// An update without a slug keeps the stored one.
+	doc.Slug = ""
+	if err := r.Update(ctx, doc); err != nil || doc.Slug != "second" {
+		t.Fatalf("Update() without slug = %q, %v", doc.Slug, err)
+	}
+
+	// Another golden cannot take a slug redirecting to this one, but the
+	// golden itself can go back to it.
+	other.Slug = "third"
+	if err := r.Create(ctx, other); err != nil {
+		t.Fatalf("Create() unexpected error: %v", err)
+	}
+	other.Slug = "first"
+	if err := r.Update(ctx, other); !errors.Is(err, domain.ErrSlugTaken) {
+		t.Fatalf("Update() onto a redirect of another golden: expected ErrSlugTaken, got %v", err)
+	}
+	doc.Slug = "first"
+	if err := r.Update(ctx, doc); err != nil {
+		t.Fatalf("Update() unexpected error: %v", err)
+	}
+	for _, slug := range []string{"first", "second"} {
+		got, err := r.GetBySlug(ctx, slug, domain.GoldenViewBasic)
+		if err != nil || got.ID != doc.ID || got.Slug != "first" {
+			t.Fatalf("GetBySlug(%q) after renaming back = %+v, %v", slug, got, err)
+		}
+	}
+
+	taken, err := r.SlugsInUse(ctx, "first")
+	if err != nil || !slices.Equal(taken, []string{"first"}) {
+		t.Fatalf("SlugsInUse() = %v, %v", taken, err)
+	}
+
+	if err := r.Delete(ctx, doc.ID); err != nil {
+		t.Fatalf("Delete() unexpected error: %v", err)
+	}
+	if _, err := r.GetBySlug(ctx, "first", domain.GoldenViewBasic); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("GetBySlug() after Delete: expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestCategoryRepository_TreeAndGoldens_Integration(t *testing.T) {
+	db := helperIntegrationDB(t)
+	r := NewGoldenRepository(db)
+	helperEnsureSchemaAndClean(t, r)
+	categories := NewCategoryRepository(db)
+	ctx := helperEditorContext()
+
+	prefix := domain.HelperRandomAlphaPrefix(t, 8)
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	root := &domain.Category{ID: prefix + "-ops", DisplayName: "Ops", CreatedAt: now, UpdatedAt: now}
+	child := &domain.Category{ID: prefix + "-ci", ParentID: root.ID, DisplayName: "CI", Position: 1, CreatedAt: now, UpdatedAt: now}
+	for _, c := range []*domain.Category{root, child} {
+		if err := categories.CreateCategory(ctx, c); err != nil {
+			t.Fatalf("CreateCategory() unexpected error: %v", err)
+		}
+	}
+
+	clash := &domain.Category{ID: prefix + "ops", DisplayName: "Clash", CreatedAt: now, UpdatedAt: now}
+	if err := categories.CreateCategory(ctx, clash); !errors.Is(err, domain.ErrCategoryExists) {
+		t.Fatalf("CreateCategory() with the key of another: expected ErrCategoryExists, got %v", err)
+	}
+	orphan := &domain.Category{ID: prefix + "-orphan", ParentID: prefix + "-gone", DisplayName: "Orphan", CreatedAt: now, UpdatedAt: now}
+	if err := categories.CreateCategory(ctx, orphan); !errors.Is(err, domain.ErrInvalidCategory) {
+		t.Fatalf("CreateCategory() with an unknown parent: expected ErrInvalidCategory, got %v", err)
+	}
+
+	if id, err := categories.ResolveCategory(ctx, strings.ToUpper(prefix)+" Ops"); err != nil || id != root.ID {
+		t.Fatalf("ResolveCategory() = %q, %v, want %s", id, err, root.ID)
+	}
+	if subtree, err := categories.CategorySubtree(ctx, root.ID); err != nil || !reflect.DeepEqual(subtree, []string{root.ID, child.ID}) {
+		t.Fatalf("CategorySubtree() = %v, %v", subtree, err)
+	}
+
+	doc := helperRandomGolden(t)
+	doc.Category = child.ID
+	if err := r.Create(ctx, doc); err != nil {
+		t.Fatalf("Create() unexpected error: %v", err)
+	}
+	other := helperRandomGolden(t)
+	other.Category = prefix + "-gone"
+	if err := r.Create(ctx, other); !errors.Is(err, domain.ErrUnknownCategory) {
+		t.Fatalf("Create() with an unknown category: expected ErrUnknownCategory, got %v", err)
+	}
+	uncategorized := helperRandomGolden(t)
+	uncategorized.Category = ""
+	if err := r.Create(ctx, uncategorized); err != nil {
+		t.Fatalf("Create() without category unexpected error: %v", err)
+	}
+
+	docs, err := r.GetByCategories(ctx, []string{root.ID, child.ID}, domain.GoldenViewBasic)
+	if err != nil || len(docs) != 1 || docs[0].ID != doc.ID {
+		t.Fatalf("GetByCategories() = %+v, %v", docs, err)
+	}
+
+	if err := categories.DeleteCategory(ctx, child.ID); !errors.Is(err, domain.ErrCategoryInUse) {
+		t.Fatalf("DeleteCategory() of a used category: expected ErrCategoryInUse, got %v", err)
+	}
+	if err := categories.DeleteCategory(ctx, root.ID); !errors.Is(err, domain.ErrCategoryInUse) {
+		t.Fatalf("DeleteCategory() of a parent: expected ErrCategoryInUse, got %v", err)
+	}
+
+	child.ParentID = ""
+	child.DisplayName = "Continuous Integration"
+	if err := categories.UpdateCategory(ctx, child); err != nil {
+		t.Fatalf("UpdateCategory() unexpected error: %v", err)
+	}
+	got, err := categories.GetCategory(ctx, child.ID)
+	if err != nil || got.ParentID != "" || got.DisplayName != "Continuous Integration" || !got.CreatedAt.Equal(now) {
+		t.Fatalf("GetCategory() = %+v, %v", got, err)
+	}
+	if err := categories.DeleteCategory(ctx, root.ID); err != nil {
+		t.Fatalf("DeleteCategory() unexpected error: %v", err)
+	}
+	if _, err := categories.GetCategory(ctx, root.ID); !errors.Is(err, domain.ErrCategoryNotFound) {
+		t.Fatalf("GetCategory() after delete: expected ErrCategoryNotFound, got %v", err)
+	}
+}
+
+func TestGoldenRepository_InitSchema_NormalizesCategories_Integration(t *testing.T) {
+	db := helperIntegrationDB(t)
+	r := NewGoldenRepository(db)
+	helperEnsureSchemaAndClean(t, r)
+	ctx := helperEditorContext()
+
+	// Go back to the free-text categories stored before the categories table.
+	if _, err := db.ExecContext(ctx, `ALTER TABLE goldens DROP CONSTRAINT `+categoryForeignKey); err != nil {
+		t.Fatalf("failed to drop category foreign key: %v", err)
+	}
+	prefix := domain.HelperRandomAlphaPrefix(t, 8)
+	spellings := map[string]string{
+		"a": prefix + " Ops",
+		"b": prefix + " Ops",
+		"c": strings.ToUpper(prefix) + "OPS",
+		"d": "",
+	}
+	for id, category := range spellings {
+		doc := helperRandomGolden(t)
+		doc.ID, doc.Category = prefix+"-"+id, category
+		helperInsertDocDirect(t, db, doc)
+	}
+
+	if err := r.InitSchema(ctx); err != nil {
+		t.Fatalf("InitSchema() unexpected error: %v", err)
+	}
+
+	category, err := NewCategoryRepository(db).GetCategory(ctx, prefix+"-ops")
+	if err != nil || category.DisplayName != prefix+" Ops" {
+		t.Fatalf("GetCategory() = %+v, %v, want the most used spelling", category, err)
+	}
+	for id, want := range map[string]string{"a": prefix + "-ops", "b": prefix + "-ops", "c": prefix + "-ops", "d": ""} {
+		doc, err := r.GetByID(ctx, prefix+"-"+id, domain.GoldenViewBasic)
+		if err != nil || doc.Category != want {
+			t.Fatalf("golden %s has category %q, %v, want %q", id, doc.Category, err, want)
+		}
+	}
+}
+
+func TestTagRepository_RegistryRenameAndMerge_Integration(t *testing.T) {
+	db := helperIntegrationDB(t)
+	r := NewGoldenRepository(db)
+	helperEnsureSchemaAndClean(t, r)
+	tags := NewTagRepository(db)
+	ctx := helperEditorContext()
+	if err := tags.InitSchema(ctx); err != nil {
+		t.Fatalf("InitSchema() unexpected error: %v", err)
+	}
+
+	prefix := domain.HelperRandomAlphaPrefix(t, 8)
+	cicd := prefix + "-ci-cd"
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	if err := tags.RegisterTag(ctx, &domain.RegisteredTag{Name: cicd, Synonyms: []string{prefix + " delivery"}, CreatedAt: now}); err != nil {
+		t.Fatalf("RegisterTag() unexpected error: %v", err)
+	}
+	clash := &domain.RegisteredTag{Name: prefix + "-other", Synonyms: []string{strings.ToUpper(prefix) + " CICD"}, CreatedAt: now}
+	if err := tags.RegisterTag(ctx, clash); !errors.Is(err, domain.ErrInvalidTag) {
+		t.Fatalf("RegisterTag() with the spelling of another tag: expected ErrInvalidTag, got %v", err)
+	}
+
+	spellings := map[string][]string{
+		"a": {strings.ToUpper(prefix) + "-CI/CD", prefix + "-cicd", "go"},
+		"b": {cicd, prefix + "-cicd"},
+		"c": {"go"},
+	}
+	for id, tagList := range spellings {
+		doc := helperCategorizedGolden(t, db)
+		doc.ID, doc.Tags = prefix+"-"+id, tagList
+		if err := r.Create(ctx, doc); err != nil {
+			t.Fatalf("Create() unexpected error: %v", err)
+		}
+	}
+
+	updated, err := tags.MergeTags(ctx, []string{strings.ToUpper(prefix) + "-CI/CD", prefix + "-cicd"}, cicd)
+	if err != nil || updated != 2 {
+		t.Fatalf("MergeTags() = %d, %v, want 2 goldens", updated, err)
+	}
+	for id, want := range map[string][]string{"a": {cicd, "go"}, "b": {cicd}, "c": {"go"}} {
+		doc, err := r.GetByID(ctx, prefix+"-"+id, domain.GoldenViewBasic)
+		if err != nil || !reflect.DeepEqual(doc.Tags, want) {
+			t.Fatalf("golden %s has tags %v, %v, want %v", id, doc.Tags, err, want)
+		}
+	}
+
+	var events int
+	err = db.QueryRowContext(ctx, `SELECT COUNT(*) FROM outbox_events WHERE aggregate_id IN ($1, $2) AND event_type = $3`,
+		prefix+"-a", prefix+"-b", string(domain.EventGoldenUpdated)).Scan(&events)
+	if err != nil || events != 2 {
+		t.Fatalf("expected 2 update events, got %d, %v", events, err)
+	}
+
+	if _, err := tags.RenameTag(ctx, "go", cicd); !errors.Is(err, domain.ErrTagExists) {
+		t.Fatalf("RenameTag() onto a used tag: expected ErrTagExists, got %v", err)
+	}
+	renamed := prefix + "-delivery-pipelines"
+	if updated, err := tags.RenameTag(ctx, cicd, renamed); err != nil || updated != 2 {
+		t.Fatalf("RenameTag() = %d, %v, want 2 goldens", updated, err)
+	}
+
+	registry, err := tags.ListRegisteredTags(ctx)
+	if err != nil {
+		t.Fatalf("ListRegisteredTags() unexpected error: %v", err)
+	}
+	var got *domain.RegisteredTag
+	for i := range registry {
+		if registry[i].Name == renamed {
+			got = &registry[i]
+		}
+		if registry[i].Name == cicd {
+			t.Fatalf("expected %s to be renamed, got %+v", cicd, registry)
+		}
+	}
+	// The merged spellings fold into the tag itself; only the old name is
+	// kept as a new synonym.
+	want := []string{prefix + " delivery", cicd}
+	if got == nil || !got.CreatedAt.Equal(now) || !reflect.DeepEqual(slices.Sorted(slices.Values(got.Synonyms)), want) {
+		t.Fatalf("renamed tag = %+v, want synonyms %v", got, want)
+	}
+
+	if err := tags.UnregisterTag(ctx, renamed); err != nil {
+		t.Fatalf("UnregisterTag() unexpected error: %v", err)
+	}
+	if err := tags.UnregisterTag(ctx, renamed); !errors.Is(err, domain.ErrTagNotFound) {
+		t.Fatalf("UnregisterTag() twice: expected ErrTagNotFound, got %v", err)
+	}
+}
+
+func TestGoldenRepository_StatusAndTransitions_Integration(t *testing.T) {
+	db := helperIntegrationDB(t)
+	r := NewGoldenRepository(db)
+	helperEnsureSchemaAndClean(t, r)
+	ctx := helperEditorContext()
+
+	doc := helperCategorizedGolden(t, db)
+	if err := r.Create(ctx, doc); err != nil {
+		t.Fatalf("Create() unexpected error: %v", err)
+	}
+	got, err := r.GetByID(ctx, doc.ID, domain.GoldenViewBasic)
+	if err != nil || got.Status != domain.StatusDraft {
+		t.Fatalf("GetByID() = %+v, %v; want a draft", got, err)
+	}
+	if _, err := r.GetByID(context.Background(), doc.ID, domain.GoldenViewBasic); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("GetByID() by a reader = %v, want ErrNotFound for a draft", err)
+	}
+
+	at := time.Now().UTC().Truncate(time.Microsecond)
+	got.ExpireAt = at.Add(24 * time.Hour)
+	var transitions []domain.GoldenTransition
+	for _, to := range []domain.GoldenStatus{domain.StatusInReview, domain.StatusPublished} {
+		transition, err := got.Transition(to, "step to "+string(to), at)
+		if err != nil {
+			t.Fatalf("Transition(%s) unexpected error: %v", to, err)
+		}
+		if err := r.Update(ctx, got); err != nil {
+			t.Fatalf("Update() unexpected error: %v", err)
+		}
+		if err := r.AppendTransition(ctx, transition); err != nil {
+			t.Fatalf("AppendTransition() unexpected error: %v", err)
+		}
+		transitions = append(transitions, transition)
+	}
+
+	// Writes without a status keep the stored one.
+	doc.Title += "-edited"
+	if err := r.Update(ctx, doc); err != nil {
+		t.Fatalf("Update() unexpected error: %v", err)
+	}
+	if doc.Status != domain.StatusPublished || !doc.PublishedAt.Equal(at) {
+		t.Fatalf("Update() left %s published at %v, want the stored status", doc.Status, doc.PublishedAt)
+	}
+	// Unlike the status, expire_at is written as given.
+	if stored, err := r.GetByID(ctx, doc.ID, domain.GoldenViewBasic); err != nil || !stored.ExpireAt.IsZero() {
+		t.Fatalf("GetByID() after update = %+v, %v; want expire_at cleared", stored, err)
+	}
+
+	docs, err := r.GetAll(context.Background(), domain.GoldenViewBasic)
+	if err != nil || len(docs) != 1 || docs[0].ID != doc.ID {
+		t.Fatalf("GetAll() by a reader = %+v, %v; want the published golden", docs, err)
+	}
+
+	facets, err := r.CountTags(ctx, nil)
+	if err != nil || len(facets) != len(doc.Tags) {
+		t.Fatalf("CountTags() = %v, %v; want the tags of the published golden", facets, err)
+	}
+
+	// Imports restore the status they carry, and keep the stored one
+	// otherwise.
+	restored := helperCategorizedGolden(t, db)
+	restored.Status, restored.PublishedAt, restored.ArchivedAt = domain.StatusArchived, at, at.Add(time.Hour)
+	doc.Status = ""
+	if _, err := r.Upsert(ctx, []domain.Golden{*doc, *restored}, false); err != nil {
+		t.Fatalf("Upsert() unexpected error: %v", err)
+	}
+	if got, err := r.GetByID(ctx, doc.ID, domain.GoldenViewBasic); err != nil || got.Status != domain.StatusPublished {
+		t.Fatalf("GetByID() after upsert = %+v, %v; want the stored status", got, err)
+	}
+	if got, err := r.GetByID(ctx, restored.ID, domain.GoldenViewBasic); err != nil || got.Status != domain.StatusArchived || !got.ArchivedAt.Equal(restored.ArchivedAt) {
+		t.Fatalf("GetByID() after restoring upsert = %+v, %v; want archived", got, err)
+	}
+
+	history, err := r.ListTransitions(ctx, doc.ID)
+	if err != nil {
+		t.Fatalf("ListTransitions() unexpected error: %v", err)
+	}
+	if len(history) != len(transitions) {
+		t.Fatalf("ListTransitions() = %+v, want %+v", history, transitions)
+	}
+	for i := range history {
+		if history[i].To != transitions[i].To || history[i].Reason != transitions[i].Reason || !history[i].At.Equal(at) {
+			t.Fatalf("ListTransitions()[%d] = %+v, want %+v", i, history[i], transitions[i])
+		}
+	}
+}
+
+func TestScheduleRepository_RunDue_Integration(t *testing.T) {
+	db := helperIntegrationDB(t)
+	r := NewGoldenRepository(db)
+	helperEnsureSchemaAndClean(t, r)
+	schedule := NewScheduleRepository(db)
+	ctx := helperEditorContext()
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	due := helperCategorizedGolden(t, db)
+	due.Status, due.PublishAt = domain.StatusScheduled, now.Add(-time.Minute)
+	later := helperCategorizedGolden(t, db)
+	later.Status, later.PublishAt = domain.StatusScheduled, now.Add(time.Hour)
+	for _, doc := range []*domain.Golden{due, later} {
+		if err := r.Create(ctx, doc); err != nil {
+			t.Fatalf("Create() unexpected error: %v", err)
+		}
+	}
+
+	// Scheduled goldens are counted once their publish_at has come, before
+	// the scheduler flips them.
+	facets, err := r.CountCategories(ctx, "")
+	if err != nil || len(facets) != 1 || facets[0].Count != 1 {
+		t.Fatalf("CountCategories() = %v, %v; want the due golden only", facets, err)
+	}
+
+	next, err := schedule.NextDue(ctx)
+	if err != nil || !next.Equal(due.PublishAt) {
+		t.Fatalf("NextDue() = %v, %v; want %v", next, err, due.PublishAt)
+	}
+
+	var got []string
+	ran, err := schedule.RunDue(ctx, now, 10, func(tx domain.Repository, ids []string) error {
+		got = ids
+		// A second scheduler cannot take the lock while this one holds it.
+		ran, err := schedule.RunDue(ctx, now, 10, func(domain.Repository, []string) error {
+			t.Fatal("RunDue() ran while another scheduler held the lock")
+			return nil
+		})
+		if err != nil || ran {
+			t.Fatalf("concurrent RunDue() = %v, %v; want false", ran, err)
+		}
+		return nil
+	})
+	if err != nil || !ran {
+		t.Fatalf("RunDue() = %v, %v; want true", ran, err)
+	}
+	if len(got) != 1 || got[0] != due.ID {
+		t.Fatalf("RunDue() ids = %v, want [%s]", got, due.ID)
+	}
+}
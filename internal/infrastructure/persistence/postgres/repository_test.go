@@ -3,10 +3,15 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"markitos-it-svc-goldens/internal/domain"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 func helperClosedDB(t *testing.T) *sql.DB {
@@ -39,6 +44,11 @@ func helperRandomGolden(t *testing.T) *domain.Golden {
 	}
 }
 
+// helperEditorContext reads goldens whatever their status.
+func helperEditorContext() context.Context {
+	return domain.WithEditor(context.Background())
+}
+
 func TestNewGoldenRepository(t *testing.T) {
 	prefix := domain.HelperRandomAlphaPrefix(t, 6)
 	db := helperClosedDB(t)
@@ -144,7 +154,8 @@ func TestGoldenRepository_GetAll(t *testing.T) {
 		db *sql.DB
 	}
 	type args struct {
-		ctx context.Context
+		ctx  context.Context
+		view domain.GoldenView
 	}
 	tests := []struct {
 		name    string
@@ -160,11 +171,18 @@ func TestGoldenRepository_GetAll(t *testing.T) {
 			want:    nil,
 			wantErr: true,
 		},
+		{
+			name:    prefix + "-returns-error-on-closed-db-basic-view",
+			fields:  fields{db: db},
+			args:    args{ctx: context.Background(), view: domain.GoldenViewBasic},
+			want:    nil,
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := &GoldenRepository{db: tt.fields.db}
-			got, err := r.GetAll(tt.args.ctx)
+			got, err := r.GetAll(tt.args.ctx, tt.args.view)
 			if (err != nil) != tt.wantErr {
 				t.Fatalf("GoldenRepository.GetAll() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -186,8 +204,9 @@ func TestGoldenRepository_GetByID(t *testing.T) {
 		db *sql.DB
 	}
 	type args struct {
-		ctx context.Context
-		id  string
+		ctx  context.Context
+		id   string
+		view domain.GoldenView
 	}
 	tests := []struct {
 		name    string
@@ -207,7 +226,7 @@ func TestGoldenRepository_GetByID(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := &GoldenRepository{db: tt.fields.db}
-			got, err := r.GetByID(tt.args.ctx, tt.args.id)
+			got, err := r.GetByID(tt.args.ctx, tt.args.id, tt.args.view)
 			if (err != nil) != tt.wantErr {
 				t.Fatalf("GoldenRepository.GetByID() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -221,6 +240,56 @@ func TestGoldenRepository_GetByID(t *testing.T) {
 	}
 }
 
+func TestGoldenRepository_GetByIDs(t *testing.T) {
+	prefix := domain.HelperRandomAlphaPrefix(t, 6)
+	r := &GoldenRepository{db: helperClosedDB(t)}
+
+	got, err := r.GetByIDs(context.Background(), []string{prefix + "-a", prefix + "-b"}, domain.GoldenViewBasic)
+	if err == nil {
+		t.Fatalf("GoldenRepository.GetByIDs() = %v, want error on closed db", got)
+	}
+}
+
+func TestGoldenRepository_GetByCategories(t *testing.T) {
+	r := &GoldenRepository{db: helperClosedDB(t)}
+
+	got, err := r.GetByCategories(context.Background(), []string{"devops", "ci-cd"}, domain.GoldenViewBasic)
+	if err == nil {
+		t.Fatalf("GoldenRepository.GetByCategories() = %v, want error on closed db", got)
+	}
+}
+
+func TestIsUnknownCategory(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "golden-category", err: fmt.Errorf("insert: %w", &pq.Error{Code: "23503", Constraint: categoryForeignKey}), want: true},
+		{name: "category-parent", err: &pq.Error{Code: "23503", Constraint: "categories_parent_id_fkey"}, want: false},
+		{name: "unique-violation", err: &pq.Error{Code: "23505", Constraint: categoryForeignKey}, want: false},
+		{name: "nil", err: nil, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isUnknownCategory(tt.err); got != tt.want {
+				t.Fatalf("isUnknownCategory(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGoldenRepository_CountFacets(t *testing.T) {
+	r := &GoldenRepository{db: helperClosedDB(t)}
+
+	if got, err := r.CountCategories(context.Background(), "k8s"); err == nil {
+		t.Fatalf("GoldenRepository.CountCategories() = %v, want error on closed db", got)
+	}
+	if got, err := r.CountTags(context.Background(), nil); err == nil {
+		t.Fatalf("GoldenRepository.CountTags() = %v, want error on closed db", got)
+	}
+}
+
 func TestGoldenRepository_Create(t *testing.T) {
 	prefix := domain.HelperRandomAlphaPrefix(t, 6)
 	db := helperClosedDB(t)
@@ -325,3 +394,128 @@ func TestGoldenRepository_Delete(t *testing.T) {
 	}
 }
 
+func TestGoldenRepository_Upsert(t *testing.T) {
+	prefix := domain.HelperRandomAlphaPrefix(t, 6)
+	db := helperClosedDB(t)
+	randomDoc := helperRandomGolden(t)
+
+	type fields struct {
+		db *sql.DB
+	}
+	type args struct {
+		ctx    context.Context
+		docs   []domain.Golden
+		dryRun bool
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []domain.ImportResult
+		wantErr bool
+	}{
+		{
+			name:    prefix + "-empty-batch-is-noop",
+			fields:  fields{db: db},
+			args:    args{ctx: context.Background()},
+			want:    nil,
+			wantErr: false,
+		},
+		{
+			name:    prefix + "-returns-error-on-closed-db",
+			fields:  fields{db: db},
+			args:    args{ctx: context.Background(), docs: []domain.Golden{*randomDoc}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &GoldenRepository{db: tt.fields.db}
+			got, err := r.Upsert(tt.args.ctx, tt.args.docs, tt.args.dryRun)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GoldenRepository.Upsert() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GoldenRepository.Upsert() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectColumns(t *testing.T) {
+	tests := []struct {
+		name        string
+		view        domain.GoldenView
+		wantContent string
+	}{
+		{name: "full-view-selects-content", view: domain.GoldenViewFull, wantContent: ", content_b64,"},
+		{name: "basic-view-omits-content", view: domain.GoldenViewBasic, wantContent: ", '' AS content_b64,"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := selectColumns(tt.view)
+			if !strings.Contains(got, tt.wantContent) {
+				t.Errorf("selectColumns() = %q, want it to contain %q", got, tt.wantContent)
+			}
+			if !strings.HasSuffix(got, contentDigestColumns) {
+				t.Errorf("selectColumns() = %q, want digest columns", got)
+			}
+		})
+	}
+}
+
+func TestIsTxConflict(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "serialization-failure", err: &pq.Error{Code: "40001"}, want: true},
+		{name: "deadlock", err: fmt.Errorf("failed to update golden: %w", &pq.Error{Code: "40P01"}), want: true},
+		{name: "unique-violation", err: &pq.Error{Code: "23505"}, want: false},
+		{name: "plain-error", err: errors.New("boom"), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTxConflict(tt.err); got != tt.want {
+				t.Errorf("isTxConflict() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsolationLevel(t *testing.T) {
+	tests := []struct {
+		level domain.IsolationLevel
+		want  sql.IsolationLevel
+	}{
+		{level: domain.IsolationDefault, want: sql.LevelDefault},
+		{level: domain.IsolationReadCommitted, want: sql.LevelReadCommitted},
+		{level: domain.IsolationRepeatableRead, want: sql.LevelRepeatableRead},
+		{level: domain.IsolationSerializable, want: sql.LevelSerializable},
+	}
+	for _, tt := range tests {
+		if got := isolationLevel(tt.level); got != tt.want {
+			t.Errorf("isolationLevel(%d) = %v, want %v", tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestGoldenRepository_RunInTx_BeginError(t *testing.T) {
+	r := NewGoldenRepository(helperClosedDB(t))
+	called := false
+
+	err := r.RunInTx(context.Background(), func(tx domain.Repository) error {
+		called = true
+		return nil
+	})
+	if err == nil || !strings.Contains(err.Error(), "failed to begin transaction") {
+		t.Fatalf("expected begin error, got %v", err)
+	}
+	if called {
+		t.Fatal("fn must not run without a transaction")
+	}
+}
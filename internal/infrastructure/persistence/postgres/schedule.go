@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"markitos-it-svc-goldens/internal/domain"
+	"time"
+)
+
+// ScheduleRepository implements domain.ScheduleStore. The lock is a
+// transaction level advisory lock, so it is released on commit or when the
+// connection of a crashed replica goes away.
+type ScheduleRepository struct {
+	goldens *GoldenRepository
+}
+
+func NewScheduleRepository(db *sql.DB) *ScheduleRepository {
+	return &ScheduleRepository{goldens: NewGoldenRepository(db)}
+}
+
+// RunDue locks the due rows too, skipping those an editor is changing: they
+// are picked up by a later run.
+func (r *ScheduleRepository) RunDue(ctx context.Context, now time.Time, limit int, fn func(tx domain.Repository, ids []string) error) (bool, error) {
+	query := `
+		SELECT id
+		FROM goldens
+		WHERE (status = 'scheduled' AND publish_at <= $1) OR (status = 'published' AND expire_at <= $1)
+		ORDER BY CASE WHEN status = 'scheduled' THEN publish_at ELSE expire_at END, id
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED
+	`
+
+	locked := false
+	err := r.goldens.withTx(ctx, false, domain.NewTxOptions(), func(tx *GoldenRepository) error {
+		err := tx.conn().QueryRowContext(ctx, `SELECT pg_try_advisory_xact_lock(hashtext('goldens_scheduler'))`).Scan(&locked)
+		if err != nil {
+			return fmt.Errorf("failed to lock scheduler: %w", err)
+		}
+		if !locked {
+			return nil
+		}
+
+		ids, err := queryStrings(ctx, tx.conn(), query, now.UTC(), limit)
+		if err != nil {
+			return wrapError("failed to query due goldens", err)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		return fn(tx, ids)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return locked, nil
+}
+
+func (r *ScheduleRepository) NextDue(ctx context.Context) (time.Time, error) {
+	query := `
+		SELECT LEAST(
+			(SELECT MIN(publish_at) FROM goldens WHERE status = 'scheduled'),
+			(SELECT MIN(expire_at) FROM goldens WHERE status = 'published')
+		)
+	`
+
+	var next sql.NullTime
+	if err := r.goldens.conn().QueryRowContext(ctx, query).Scan(&next); err != nil {
+		return time.Time{}, wrapError("failed to query next due golden", err)
+	}
+
+	return next.Time, nil
+}
@@ -0,0 +1,130 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"markitos-it-svc-goldens/internal/domain"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// slugIndex is the unique index on goldens.slug, named so that its
+// violations can be told apart from duplicate ids.
+const slugIndex = "idx_goldens_slug"
+
+// likeEscaper escapes the LIKE wildcards, with backslash as the default
+// escape character.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func isSlugConflict(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == pqUniqueViolation && pqErr.Constraint == slugIndex
+}
+
+func (r *GoldenRepository) SlugsInUse(ctx context.Context, base string) ([]string, error) {
+	query := `
+		SELECT slug FROM goldens WHERE slug = $1 OR slug LIKE $2
+		UNION
+		SELECT slug FROM slug_redirects WHERE slug = $1 OR slug LIKE $2
+	`
+
+	var slugs []string
+	err := r.retryRead(ctx, func(conn querier) error {
+		slugs = nil
+		rows, err := conn.QueryContext(ctx, query, base, likeEscaper.Replace(base)+"-%")
+		if err != nil {
+			return wrapError("failed to query slugs", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var slug string
+			if err := rows.Scan(&slug); err != nil {
+				return wrapError("failed to scan slug", err)
+			}
+			slugs = append(slugs, slug)
+		}
+
+		if err := rows.Err(); err != nil {
+			return wrapError("error iterating slugs", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return slugs, nil
+}
+
+// redirectSlug points oldSlug at id after a rename. A redirect the golden
+// left behind on newSlug earlier is dropped, as the slug is current again; one
+// of another golden fails the rename with ErrSlugTaken.
+func (r *GoldenRepository) redirectSlug(ctx context.Context, oldSlug, newSlug, id string) error {
+	query := `
+		INSERT INTO slug_redirects (slug, golden_id)
+		VALUES ($1, $2)
+		ON CONFLICT (slug) DO UPDATE SET golden_id = EXCLUDED.golden_id, created_at = CURRENT_TIMESTAMP
+	`
+	if _, err := r.writeConn(ctx).ExecContext(ctx, query, oldSlug, id); err != nil {
+		return wrapError("failed to record slug redirect", err)
+	}
+
+	query = `
+		WITH dropped AS (DELETE FROM slug_redirects WHERE slug = $1 AND golden_id = $2)
+		SELECT EXISTS (SELECT 1 FROM slug_redirects WHERE slug = $1 AND golden_id <> $2)
+	`
+	var taken bool
+	if err := r.writeConn(ctx).QueryRowContext(ctx, query, newSlug, id).Scan(&taken); err != nil {
+		return wrapError("failed to drop slug redirect", err)
+	}
+	if taken {
+		return fmt.Errorf("%w: %s", domain.ErrSlugTaken, newSlug)
+	}
+
+	return nil
+}
+
+// backfillSlugs derives a slug for the goldens stored before slugs existed.
+// The advisory lock keeps replicas starting together from racing.
+func (r *GoldenRepository) backfillSlugs(ctx context.Context) error {
+	return r.withTx(ctx, false, domain.NewTxOptions(), func(tx *GoldenRepository) error {
+		if _, err := tx.conn().ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('goldens_slug_backfill'))`); err != nil {
+			return fmt.Errorf("failed to lock slug backfill: %w", err)
+		}
+
+		rows, err := tx.conn().QueryContext(ctx, `SELECT id, title FROM goldens WHERE slug IS NULL ORDER BY updated_at, id`)
+		if err != nil {
+			return fmt.Errorf("failed to query goldens without slug: %w", err)
+		}
+		var docs []domain.Golden
+		for rows.Next() {
+			var doc domain.Golden
+			if err := rows.Scan(&doc.ID, &doc.Title); err != nil {
+				rows.Close()
+				return fmt.Errorf("failed to scan golden without slug: %w", err)
+			}
+			docs = append(docs, doc)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("error iterating goldens without slug: %w", err)
+		}
+
+		for _, doc := range docs {
+			base := doc.BaseSlug()
+			taken, err := tx.SlugsInUse(ctx, base)
+			if err != nil {
+				return err
+			}
+			slug := domain.UniqueSlug(base, taken)
+			if _, err := tx.conn().ExecContext(ctx, `UPDATE goldens SET slug = $2 WHERE id = $1`, doc.ID, slug); err != nil {
+				return fmt.Errorf("failed to backfill slug of %s: %w", doc.ID, err)
+			}
+		}
+
+		return nil
+	})
+}
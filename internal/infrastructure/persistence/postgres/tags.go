@@ -0,0 +1,301 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"markitos-it-svc-goldens/internal/domain"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// TagRepository stores the tag registry and rewrites tags across goldens.
+// Renames and merges run in one transaction of the golden repository, so the
+// rewritten rows, their change events and the registry commit together.
+type TagRepository struct {
+	db      *sql.DB
+	goldens *GoldenRepository
+}
+
+func NewTagRepository(db *sql.DB) *TagRepository {
+	return &TagRepository{db: db, goldens: NewGoldenRepository(db)}
+}
+
+// InitSchema creates the registry. Synonyms are keyed by domain.TagKey, so
+// one spelling cannot stand for two tags.
+func (r *TagRepository) InitSchema(ctx context.Context) error {
+	schema := `
+	CREATE TABLE IF NOT EXISTS tags (
+		name VARCHAR(100) PRIMARY KEY,
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS tag_synonyms (
+		key VARCHAR(100) PRIMARY KEY,
+		synonym VARCHAR(100) NOT NULL,
+		tag VARCHAR(100) NOT NULL REFERENCES tags(name) ON UPDATE CASCADE ON DELETE CASCADE
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_tag_synonyms_tag ON tag_synonyms(tag);
+	`
+
+	_, err := r.db.ExecContext(ctx, schema)
+	if err != nil {
+		return fmt.Errorf("failed to initialize tag schema: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TagRepository) ListRegisteredTags(ctx context.Context) ([]domain.RegisteredTag, error) {
+	query := `
+		SELECT t.name, t.created_at, COALESCE(array_agg(s.synonym ORDER BY s.synonym) FILTER (WHERE s.synonym IS NOT NULL), '{}')
+		FROM tags t
+		LEFT JOIN tag_synonyms s ON s.tag = t.name
+		GROUP BY t.name, t.created_at
+		ORDER BY t.name
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, wrapError("failed to query tags", err)
+	}
+	defer rows.Close()
+
+	var tags []domain.RegisteredTag
+	for rows.Next() {
+		var tag domain.RegisteredTag
+		if err := rows.Scan(&tag.Name, &tag.CreatedAt, pq.Array(&tag.Synonyms)); err != nil {
+			return nil, wrapError("failed to scan tag", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, wrapError("error iterating tags", err)
+	}
+
+	return tags, nil
+}
+
+// RegisterTag keeps the creation time of a tag that is already registered
+// and replaces its synonyms.
+func (r *TagRepository) RegisterTag(ctx context.Context, tag *domain.RegisteredTag) error {
+	return r.goldens.withTx(ctx, false, domain.NewTxOptions(), func(tx *GoldenRepository) error {
+		conn := tx.conn()
+
+		err := conn.QueryRowContext(ctx, `
+			INSERT INTO tags (name, created_at) VALUES ($1, $2)
+			ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+			RETURNING created_at
+		`, tag.Name, tag.CreatedAt).Scan(&tag.CreatedAt)
+		if err != nil {
+			return wrapError("failed to register tag", err)
+		}
+
+		var owner string
+		err = conn.QueryRowContext(ctx, `
+			SELECT name FROM tags WHERE replace(name, '-', '') = $1 AND name <> $2
+			UNION ALL
+			SELECT tag FROM tag_synonyms WHERE key = $1 AND tag <> $2
+			LIMIT 1
+		`, domain.TagKey(tag.Name), tag.Name).Scan(&owner)
+		if err == nil {
+			return fmt.Errorf("%w: %s is a spelling of %s", domain.ErrInvalidTag, tag.Name, owner)
+		}
+		if err != sql.ErrNoRows {
+			return wrapError("failed to check tag", err)
+		}
+
+		if _, err := conn.ExecContext(ctx, `DELETE FROM tag_synonyms WHERE tag = $1`, tag.Name); err != nil {
+			return wrapError("failed to replace tag synonyms", err)
+		}
+		for _, synonym := range tag.Synonyms {
+			if err := insertSynonym(ctx, conn, synonym, tag.Name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// insertSynonym refuses spellings that are already registered tags or
+// synonyms of another tag.
+func insertSynonym(ctx context.Context, conn querier, synonym, tag string) error {
+	key := domain.TagKey(synonym)
+
+	var owner string
+	err := conn.QueryRowContext(ctx, `
+		SELECT name FROM tags WHERE replace(name, '-', '') = $1
+		UNION ALL
+		SELECT tag FROM tag_synonyms WHERE key = $1
+		LIMIT 1
+	`, key).Scan(&owner)
+	if err == nil {
+		return fmt.Errorf("%w: %q already stands for %s", domain.ErrInvalidTag, synonym, owner)
+	}
+	if err != sql.ErrNoRows {
+		return wrapError("failed to check tag synonym", err)
+	}
+
+	_, err = conn.ExecContext(ctx, `INSERT INTO tag_synonyms (key, synonym, tag) VALUES ($1, $2, $3)`, key, synonym, tag)
+	if err != nil {
+		return wrapError("failed to add tag synonym", err)
+	}
+	return nil
+}
+
+func (r *TagRepository) UnregisterTag(ctx context.Context, name string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM tags WHERE name = $1`, name)
+	if err != nil {
+		return wrapError("failed to unregister tag", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return wrapError("failed to get rows affected", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%w: %s", domain.ErrTagNotFound, name)
+	}
+
+	return nil
+}
+
+func (r *TagRepository) RenameTag(ctx context.Context, from, to string) (int, error) {
+	var updated int
+	err := r.goldens.withTx(ctx, false, domain.NewTxOptions(), func(tx *GoldenRepository) error {
+		if err := lockTags(ctx, tx); err != nil {
+			return err
+		}
+
+		var registered, used bool
+		err := tx.conn().QueryRowContext(ctx, `
+			SELECT EXISTS (SELECT 1 FROM tags WHERE name = $1), EXISTS (SELECT 1 FROM goldens WHERE $1 = ANY(tags))
+		`, from).Scan(&registered, &used)
+		if err != nil {
+			return wrapError("failed to check tag", err)
+		}
+		if !registered && !used {
+			return fmt.Errorf("%w: %s", domain.ErrTagNotFound, from)
+		}
+
+		var taken bool
+		err = tx.conn().QueryRowContext(ctx, `
+			SELECT EXISTS (SELECT 1 FROM tags WHERE name = $1) OR EXISTS (SELECT 1 FROM goldens WHERE $1 = ANY(tags))
+		`, to).Scan(&taken)
+		if err != nil {
+			return wrapError("failed to check tag", err)
+		}
+		if taken {
+			return fmt.Errorf("%w: %s is in use, merge the tags instead", domain.ErrTagExists, to)
+		}
+
+		// Renaming the registered tag carries its synonyms over.
+		if registered {
+			if _, err := tx.conn().ExecContext(ctx, `UPDATE tags SET name = $2 WHERE name = $1`, from, to); err != nil {
+				return wrapError("failed to rename tag", err)
+			}
+		}
+
+		updated, err = mergeTags(ctx, tx, []string{from}, to)
+		return err
+	})
+	return updated, err
+}
+
+func (r *TagRepository) MergeTags(ctx context.Context, sources []string, target string) (int, error) {
+	var updated int
+	err := r.goldens.withTx(ctx, false, domain.NewTxOptions(), func(tx *GoldenRepository) error {
+		if err := lockTags(ctx, tx); err != nil {
+			return err
+		}
+
+		var err error
+		updated, err = mergeTags(ctx, tx, sources, target)
+		return err
+	})
+	return updated, err
+}
+
+// lockTags serializes renames and merges, which would otherwise deadlock
+// locking the same goldens in a different order.
+func lockTags(ctx context.Context, tx *GoldenRepository) error {
+	if _, err := tx.conn().ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('goldens_tag_rewrite'))`); err != nil {
+		return fmt.Errorf("failed to lock tag rewrite: %w", err)
+	}
+	return nil
+}
+
+// mergeTags registers target, moves the synonyms of the registered sources
+// to it and records each source as a synonym, then rewrites the goldens
+// carrying any source. A golden keeps every tag once, at its first position.
+func mergeTags(ctx context.Context, tx *GoldenRepository, sources []string, target string) (int, error) {
+	conn := tx.conn()
+
+	_, err := conn.ExecContext(ctx, `INSERT INTO tags (name, created_at) VALUES ($1, $2) ON CONFLICT (name) DO NOTHING`, target, time.Now().UTC())
+	if err != nil {
+		return 0, wrapError("failed to register tag", err)
+	}
+	if _, err := conn.ExecContext(ctx, `UPDATE tag_synonyms SET tag = $2 WHERE tag = ANY($1)`, pq.Array(sources), target); err != nil {
+		return 0, wrapError("failed to move tag synonyms", err)
+	}
+	if _, err := conn.ExecContext(ctx, `DELETE FROM tags WHERE name = ANY($1)`, pq.Array(sources)); err != nil {
+		return 0, wrapError("failed to unregister merged tags", err)
+	}
+	for _, source := range sources {
+		key := domain.TagKey(source)
+		if key == "" || key == domain.TagKey(target) {
+			continue
+		}
+		_, err := conn.ExecContext(ctx, `
+			INSERT INTO tag_synonyms (key, synonym, tag) VALUES ($1, $2, $3)
+			ON CONFLICT (key) DO UPDATE SET synonym = EXCLUDED.synonym, tag = EXCLUDED.tag
+		`, key, source, target)
+		if err != nil {
+			return 0, wrapError("failed to add tag synonym", err)
+		}
+	}
+
+	query := `
+		UPDATE goldens g
+		SET tags = (
+			SELECT array_agg(tag ORDER BY first)
+			FROM (
+				SELECT CASE WHEN u.tag = ANY($1) THEN $2 ELSE u.tag END AS tag, MIN(u.ord) AS first
+				FROM unnest(g.tags) WITH ORDINALITY AS u(tag, ord)
+				GROUP BY 1
+			) merged
+		), updated_at = $3
+		WHERE g.tags && $1
+		RETURNING g.id
+	`
+	ids, err := queryStrings(ctx, conn, query, pq.Array(sources), target, time.Now().UTC())
+	if err != nil {
+		return 0, wrapError("failed to rewrite golden tags", err)
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	docs, err := tx.GetByIDs(ctx, ids, domain.GoldenViewBasic)
+	if err != nil {
+		return 0, err
+	}
+	events := make([]domain.Event, 0, len(docs))
+	for _, doc := range docs {
+		event, err := domain.NewGoldenEvent(domain.EventGoldenUpdated, doc)
+		if err != nil {
+			return 0, err
+		}
+		events = append(events, event)
+	}
+	if err := tx.AppendEvents(ctx, events...); err != nil {
+		return 0, err
+	}
+
+	tx.notifyInvalidation(ctx, conn, ids...)
+	return len(ids), nil
+}
@@ -0,0 +1,126 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"markitos-it-svc-goldens/internal/domain"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	pqSerializationFailure pq.ErrorCode = "40001"
+	pqDeadlockDetected     pq.ErrorCode = "40P01"
+)
+
+// txRetryBackoff is the pause before the first rerun of a conflicting
+// transaction; it doubles on every further attempt.
+var txRetryBackoff = 10 * time.Millisecond
+
+func (r *GoldenRepository) RunInTx(ctx context.Context, fn func(tx domain.Repository) error, opts ...domain.TxOption) error {
+	return r.withTx(ctx, false, domain.NewTxOptions(opts...), func(tx *GoldenRepository) error {
+		return fn(tx)
+	})
+}
+
+// withTx runs fn against a repository bound to a transaction. A repository
+// that is already bound joins its transaction. With rollback the changes made
+// by fn are discarded even when it succeeds, using a savepoint when joining.
+// A new transaction aborted by a serialization failure or deadlock is rerun
+// from scratch up to opts.MaxRetries times.
+func (r *GoldenRepository) withTx(ctx context.Context, rollback bool, opts domain.TxOptions, fn func(tx *GoldenRepository) error) error {
+	if r.tx != nil {
+		if !rollback {
+			return fn(r)
+		}
+
+		if _, err := r.tx.ExecContext(ctx, "SAVEPOINT dry_run"); err != nil {
+			return fmt.Errorf("failed to create savepoint: %w", err)
+		}
+		err := fn(r)
+		if _, rbErr := r.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT dry_run"); rbErr != nil && err == nil {
+			err = fmt.Errorf("failed to roll back to savepoint: %w", rbErr)
+		}
+		return err
+	}
+
+	if !rollback {
+		domain.MarkWrite(ctx)
+	}
+
+	backoff := txRetryBackoff
+	for attempt := 0; ; attempt++ {
+		err := r.runTx(ctx, rollback, opts, fn)
+		if err == nil || attempt >= opts.MaxRetries || !isTxConflict(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (r *GoldenRepository) runTx(ctx context.Context, rollback bool, opts domain.TxOptions, fn func(tx *GoldenRepository) error) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: isolationLevel(opts.Isolation)})
+	if err != nil {
+		return wrapError("failed to begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(&GoldenRepository{db: r.db, retry: r.retry, tx: tx, replica: r.replica, monitor: r.monitor}); err != nil {
+		return err
+	}
+	if rollback {
+		return nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		return wrapError("failed to commit transaction", err)
+	}
+
+	return nil
+}
+
+// writeConn returns the connection for a write and records it on ctx, so the
+// rest of the request reads from the primary.
+func (r *GoldenRepository) writeConn(ctx context.Context) querier {
+	domain.MarkWrite(ctx)
+	return r.conn()
+}
+
+func (r *GoldenRepository) conn() querier {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+func isolationLevel(level domain.IsolationLevel) sql.IsolationLevel {
+	switch level {
+	case domain.IsolationReadCommitted:
+		return sql.LevelReadCommitted
+	case domain.IsolationRepeatableRead:
+		return sql.LevelRepeatableRead
+	case domain.IsolationSerializable:
+		return sql.LevelSerializable
+	default:
+		return sql.LevelDefault
+	}
+}
+
+// isTxConflict reports whether PostgreSQL aborted the transaction because of
+// a concurrent one, in which case rerunning it can succeed.
+func isTxConflict(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	return pqErr.Code == pqSerializationFailure || pqErr.Code == pqDeadlockDetected
+}
@@ -0,0 +1,204 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"markitos-it-svc-goldens/internal/domain"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// WebhookRepository stores webhook subscriptions and their delivery log.
+type WebhookRepository struct {
+	db *sql.DB
+}
+
+func NewWebhookRepository(db *sql.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+func (r *WebhookRepository) InitSchema(ctx context.Context) error {
+	schema := `
+	CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+		id VARCHAR(64) PRIMARY KEY,
+		url VARCHAR(2000) NOT NULL,
+		secret VARCHAR(255) NOT NULL,
+		event_types TEXT[] NOT NULL DEFAULT '{}',
+		categories TEXT[] NOT NULL DEFAULT '{}',
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS webhook_deliveries (
+		id BIGSERIAL PRIMARY KEY,
+		subscription_id VARCHAR(64) NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+		event_id VARCHAR(64) NOT NULL,
+		event_type VARCHAR(100) NOT NULL,
+		attempt INT NOT NULL,
+		status_code INT NOT NULL DEFAULT 0,
+		error TEXT NOT NULL DEFAULT '',
+		duration_ms BIGINT NOT NULL DEFAULT 0,
+		succeeded BOOLEAN NOT NULL,
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription ON webhook_deliveries(subscription_id, id DESC);
+	CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_event ON webhook_deliveries(event_id, id);
+	`
+
+	_, err := r.db.ExecContext(ctx, schema)
+	if err != nil {
+		return fmt.Errorf("failed to initialize webhook schema: %w", err)
+	}
+
+	return nil
+}
+
+func (r *WebhookRepository) CreateSubscription(ctx context.Context, sub *domain.WebhookSubscription) error {
+	query := `
+		INSERT INTO webhook_subscriptions (id, url, secret, event_types, categories, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	eventTypes := make([]string, 0, len(sub.EventTypes))
+	for _, eventType := range sub.EventTypes {
+		eventTypes = append(eventTypes, string(eventType))
+	}
+
+	_, err := r.db.ExecContext(ctx, query, sub.ID, sub.URL, sub.Secret, pq.Array(eventTypes), pq.Array(sub.Categories), sub.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook subscription: %w", err)
+	}
+
+	return nil
+}
+
+func (r *WebhookRepository) ListSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	query := `
+		SELECT id, url, secret, event_types, categories, created_at
+		FROM webhook_subscriptions
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	var subs []domain.WebhookSubscription
+	for rows.Next() {
+		var sub domain.WebhookSubscription
+		var eventTypes, categories pq.StringArray
+		if err := rows.Scan(&sub.ID, &sub.URL, &sub.Secret, &eventTypes, &categories, &sub.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook subscription: %w", err)
+		}
+
+		for _, eventType := range eventTypes {
+			sub.EventTypes = append(sub.EventTypes, domain.EventType(eventType))
+		}
+		sub.Categories = []string(categories)
+		subs = append(subs, sub)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating webhook subscriptions: %w", err)
+	}
+
+	return subs, nil
+}
+
+func (r *WebhookRepository) DeleteSubscription(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook subscription: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%w: %s", domain.ErrWebhookNotFound, id)
+	}
+
+	return nil
+}
+
+func (r *WebhookRepository) RecordDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	query := `
+		INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, attempt, status_code, error, duration_ms, succeeded)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		delivery.SubscriptionID,
+		delivery.EventID,
+		string(delivery.EventType),
+		delivery.Attempt,
+		delivery.StatusCode,
+		delivery.Error,
+		delivery.Duration.Milliseconds(),
+		delivery.Succeeded,
+	).Scan(&delivery.ID, &delivery.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, subscriptionID string, limit int) ([]domain.WebhookDelivery, error) {
+	query := `
+		SELECT id, subscription_id, event_id, event_type, attempt, status_code, error, duration_ms, succeeded, created_at
+		FROM webhook_deliveries
+		WHERE $1 = '' OR subscription_id = $1
+		ORDER BY id DESC
+		LIMIT $2
+	`
+
+	return r.queryDeliveries(ctx, query, subscriptionID, limit)
+}
+
+func (r *WebhookRepository) ListEventDeliveries(ctx context.Context, eventID string) ([]domain.WebhookDelivery, error) {
+	query := `
+		SELECT id, subscription_id, event_id, event_type, attempt, status_code, error, duration_ms, succeeded, created_at
+		FROM webhook_deliveries
+		WHERE event_id = $1
+		ORDER BY id
+	`
+
+	return r.queryDeliveries(ctx, query, eventID)
+}
+
+func (r *WebhookRepository) queryDeliveries(ctx context.Context, query string, args ...any) ([]domain.WebhookDelivery, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []domain.WebhookDelivery
+	for rows.Next() {
+		var d domain.WebhookDelivery
+		var eventType string
+		var durationMS int64
+		err := rows.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &eventType, &d.Attempt, &d.StatusCode, &d.Error, &durationMS, &d.Succeeded, &d.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+
+		d.EventType = domain.EventType(eventType)
+		d.Duration = time.Duration(durationMS) * time.Millisecond
+		deliveries = append(deliveries, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
@@ -0,0 +1,798 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"markitos-it-svc-goldens/internal/domain"
+	"strings"
+	"time"
+)
+
+// DriverName is the database/sql driver registered by modernc.org/sqlite.
+const DriverName = "sqlite"
+
+// timeLayout is fixed width so that ordering by the TEXT column matches
+// chronological order.
+const timeLayout = "2006-01-02T15:04:05.000000000Z"
+
+var migrations = []string{
+	`
+	CREATE TABLE IF NOT EXISTS goldens (
+		id TEXT PRIMARY KEY,
+		title TEXT NOT NULL,
+		description TEXT NOT NULL DEFAULT '',
+		category TEXT NOT NULL DEFAULT '',
+		tags TEXT NOT NULL DEFAULT '[]',
+		updated_at TEXT NOT NULL,
+		content_b64 TEXT NOT NULL,
+		cover_image TEXT NOT NULL,
+		content_size INTEGER NOT NULL DEFAULT 0,
+		content_hash TEXT NOT NULL DEFAULT ''
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_goldens_category ON goldens(category);
+	CREATE INDEX IF NOT EXISTS idx_goldens_updated_at ON goldens(updated_at DESC);
+	`,
+	`
+	ALTER TABLE goldens ADD COLUMN slug TEXT NOT NULL DEFAULT '';
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_goldens_slug ON goldens(slug) WHERE slug <> '';
+
+	CREATE TABLE IF NOT EXISTS slug_redirects (
+		slug TEXT PRIMARY KEY,
+		golden_id TEXT NOT NULL REFERENCES goldens(id) ON DELETE CASCADE,
+		created_at TEXT NOT NULL
+	);
+	`,
+	`
+	ALTER TABLE goldens ADD COLUMN status TEXT NOT NULL DEFAULT 'published';
+	ALTER TABLE goldens ADD COLUMN submitted_at TEXT NOT NULL DEFAULT '';
+	ALTER TABLE goldens ADD COLUMN published_at TEXT NOT NULL DEFAULT '';
+	ALTER TABLE goldens ADD COLUMN archived_at TEXT NOT NULL DEFAULT '';
+	CREATE INDEX IF NOT EXISTS idx_goldens_status ON goldens(status);
+
+	CREATE TABLE IF NOT EXISTS golden_transitions (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		golden_id TEXT NOT NULL REFERENCES goldens(id) ON DELETE CASCADE,
+		from_status TEXT NOT NULL,
+		to_status TEXT NOT NULL,
+		reason TEXT NOT NULL DEFAULT '',
+		created_at TEXT NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_golden_transitions_golden_id ON golden_transitions(golden_id, id);
+	`,
+	`
+	ALTER TABLE goldens ADD COLUMN publish_at TEXT NOT NULL DEFAULT '';
+	ALTER TABLE goldens ADD COLUMN expire_at TEXT NOT NULL DEFAULT '';
+	`,
+}
+
+// liveCondition matches the goldens readers see at ?2, as
+// domain.Golden.Live does. Empty times never compare as due.
+const liveCondition = `(goldens.status = 'published' OR (goldens.status = 'scheduled' AND goldens.publish_at <> '' AND goldens.publish_at <= ?2))
+			AND (goldens.expire_at = '' OR goldens.expire_at > ?2)`
+
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+type GoldenRepository struct {
+	db *sql.DB
+	// tx is set on the repositories handed out by RunInTx.
+	tx *sql.Tx
+}
+
+func NewGoldenRepository(db *sql.DB) *GoldenRepository {
+	return &GoldenRepository{db: db}
+}
+
+// InitSchema applies every migration newer than the recorded schema version.
+func (r *GoldenRepository) InitSchema(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			applied_at TEXT NOT NULL
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to initialize schema: %w", err)
+	}
+
+	var current int
+	err = r.db.QueryRowContext(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&current)
+	if err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	for i := current; i < len(migrations); i++ {
+		version := i + 1
+		if err := r.migrate(ctx, version, migrations[i]); err != nil {
+			return fmt.Errorf("failed to apply migration %d: %w", version, err)
+		}
+	}
+
+	return r.backfillSlugs(ctx)
+}
+
+func (r *GoldenRepository) migrate(ctx context.Context, version int, statements string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, statements); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, "INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)",
+		version, time.Now().UTC().Format(timeLayout))
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *GoldenRepository) GetAll(ctx context.Context, view domain.GoldenView) ([]domain.Golden, error) {
+	query := `
+		SELECT ` + selectColumns(view) + `
+		FROM goldens
+		ORDER BY updated_at DESC
+	`
+
+	rows, err := r.conn().QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query goldens: %w", err)
+	}
+	defer rows.Close()
+
+	var docs []domain.Golden
+	for rows.Next() {
+		doc, err := scanGolden(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan golden: %w", err)
+		}
+
+		docs = append(docs, *doc)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating goldens: %w", err)
+	}
+
+	return docs, nil
+}
+
+func (r *GoldenRepository) GetByID(ctx context.Context, id string, view domain.GoldenView) (*domain.Golden, error) {
+	query := `
+		SELECT ` + selectColumns(view) + `
+		FROM goldens
+		WHERE id = ?
+	`
+
+	doc, err := scanGolden(r.conn().QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: %s", domain.ErrNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query golden: %w", err)
+	}
+
+	return doc, nil
+}
+
+// GetByIDs reads all ids with a single query, passing them as a JSON array.
+func (r *GoldenRepository) GetByIDs(ctx context.Context, ids []string, view domain.GoldenView) ([]domain.Golden, error) {
+	query := `
+		SELECT ` + selectColumns(view) + `
+		FROM goldens
+		WHERE id IN (SELECT value FROM json_each(?))
+	`
+
+	encodedIDs, err := json.Marshal(ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode ids: %w", err)
+	}
+	rows, err := r.conn().QueryContext(ctx, query, string(encodedIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query goldens by id: %w", err)
+	}
+	defer rows.Close()
+
+	var docs []domain.Golden
+	for rows.Next() {
+		doc, err := scanGolden(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan golden: %w", err)
+		}
+
+		docs = append(docs, *doc)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating goldens: %w", err)
+	}
+
+	return docs, nil
+}
+
+// GetByCategories passes categories as a JSON array, like GetByIDs.
+func (r *GoldenRepository) GetByCategories(ctx context.Context, categories []string, view domain.GoldenView) ([]domain.Golden, error) {
+	query := `
+		SELECT ` + selectColumns(view) + `
+		FROM goldens
+		WHERE category IN (SELECT value FROM json_each(?))
+		ORDER BY updated_at DESC
+	`
+
+	encodedCategories, err := json.Marshal(categories)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode categories: %w", err)
+	}
+	rows, err := r.conn().QueryContext(ctx, query, string(encodedCategories))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query goldens by category: %w", err)
+	}
+	defer rows.Close()
+
+	var docs []domain.Golden
+	for rows.Next() {
+		doc, err := scanGolden(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan golden: %w", err)
+		}
+
+		docs = append(docs, *doc)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating goldens: %w", err)
+	}
+
+	return docs, nil
+}
+
+func (r *GoldenRepository) GetBySlug(ctx context.Context, slug string, view domain.GoldenView) (*domain.Golden, error) {
+	query := `
+		SELECT ` + selectColumns(view) + `
+		FROM goldens
+		WHERE id = COALESCE(
+			(SELECT id FROM goldens WHERE slug = ?1),
+			(SELECT golden_id FROM slug_redirects WHERE slug = ?1)
+		)
+	`
+
+	doc, err := scanGolden(r.conn().QueryRowContext(ctx, query, slug))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: slug %s", domain.ErrNotFound, slug)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query golden by slug: %w", err)
+	}
+
+	return doc, nil
+}
+
+func (r *GoldenRepository) SlugsInUse(ctx context.Context, base string) ([]string, error) {
+	query := `
+		SELECT slug FROM goldens WHERE slug = ?1 OR slug LIKE ?2 ESCAPE '\'
+		UNION
+		SELECT slug FROM slug_redirects WHERE slug = ?1 OR slug LIKE ?2 ESCAPE '\'
+	`
+
+	rows, err := r.conn().QueryContext(ctx, query, base, likeEscaper.Replace(base)+"-%")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query slugs: %w", err)
+	}
+	defer rows.Close()
+
+	var slugs []string
+	for rows.Next() {
+		var slug string
+		if err := rows.Scan(&slug); err != nil {
+			return nil, fmt.Errorf("failed to scan slug: %w", err)
+		}
+		slugs = append(slugs, slug)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating slugs: %w", err)
+	}
+
+	return slugs, nil
+}
+
+func (r *GoldenRepository) CountCategories(ctx context.Context, tag string) ([]domain.FacetCount, error) {
+	query := `
+		SELECT category, COUNT(*)
+		FROM goldens
+		WHERE category <> '' AND ` + liveCondition + `
+			AND (?1 = '' OR EXISTS (SELECT 1 FROM json_each(goldens.tags) WHERE value = ?1))
+		GROUP BY category
+		ORDER BY COUNT(*) DESC, category
+	`
+
+	return r.queryFacets(ctx, "categories", query, tag, formatTime(time.Now()))
+}
+
+// CountTags passes categories as a JSON array, like GetByCategories.
+func (r *GoldenRepository) CountTags(ctx context.Context, categories []string) ([]domain.FacetCount, error) {
+	query := `
+		SELECT tag.value, COUNT(DISTINCT goldens.id)
+		FROM goldens, json_each(goldens.tags) AS tag
+		WHERE tag.value <> '' AND ` + liveCondition + `
+			AND (json_array_length(?1) = 0 OR goldens.category IN (SELECT value FROM json_each(?1)))
+		GROUP BY tag.value
+		ORDER BY COUNT(DISTINCT goldens.id) DESC, tag.value
+	`
+
+	encodedCategories, err := json.Marshal(append([]string{}, categories...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode categories: %w", err)
+	}
+	return r.queryFacets(ctx, "tags", query, string(encodedCategories), formatTime(time.Now()))
+}
+
+func (r *GoldenRepository) queryFacets(ctx context.Context, name, query string, args ...any) ([]domain.FacetCount, error) {
+	rows, err := r.conn().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count %s: %w", name, err)
+	}
+	defer rows.Close()
+
+	var facets []domain.FacetCount
+	for rows.Next() {
+		var facet domain.FacetCount
+		if err := rows.Scan(&facet.Value, &facet.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", name, err)
+		}
+		facets = append(facets, facet)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating %s: %w", name, err)
+	}
+
+	return facets, nil
+}
+
+func (r *GoldenRepository) Create(ctx context.Context, doc *domain.Golden) error {
+	query := `
+		INSERT INTO goldens (id, title, description, category, tags, updated_at, content_b64, cover_image, content_size, content_hash, slug,
+			status, submitted_at, published_at, archived_at, publish_at, expire_at)
+		VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9, ?10, ?11, COALESCE(NULLIF(?12, ''), 'draft'), ?13, ?14, ?15, ?16, ?17)
+	`
+
+	args, err := writeArgs(doc)
+	if err != nil {
+		return fmt.Errorf("failed to create golden: %w", err)
+	}
+
+	if _, err := r.conn().ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to create golden: %w", err)
+	}
+
+	return nil
+}
+
+func (r *GoldenRepository) Update(ctx context.Context, doc *domain.Golden) error {
+	query := `
+		UPDATE goldens
+		SET title = ?2, description = ?3, category = ?4, tags = ?5, updated_at = ?6, content_b64 = ?7, cover_image = ?8,
+			content_size = ?9, content_hash = ?10, slug = COALESCE(NULLIF(?11, ''), slug), status = COALESCE(NULLIF(?12, ''), status),
+			submitted_at = CASE WHEN ?12 = '' THEN submitted_at ELSE ?13 END,
+			published_at = CASE WHEN ?12 = '' THEN published_at ELSE ?14 END,
+			archived_at = CASE WHEN ?12 = '' THEN archived_at ELSE ?15 END,
+			publish_at = ?16, expire_at = ?17
+		WHERE id = ?1
+	`
+
+	args, err := writeArgs(doc)
+	if err != nil {
+		return fmt.Errorf("failed to update golden: %w", err)
+	}
+
+	return r.withTx(ctx, false, func(tx *GoldenRepository) error {
+		var oldSlug string
+		stored := domain.Golden{ID: doc.ID}
+		var statusTimes [3]string
+		err := tx.conn().QueryRowContext(ctx, "SELECT slug, status, submitted_at, published_at, archived_at FROM goldens WHERE id = ?", doc.ID).
+			Scan(&oldSlug, &stored.Status, &statusTimes[0], &statusTimes[1], &statusTimes[2])
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: %s", domain.ErrNotFound, doc.ID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to update golden: %w", err)
+		}
+		if err := parseTimes(stored.ID, statusTimes[:], &stored.SubmittedAt, &stored.PublishedAt, &stored.ArchivedAt); err != nil {
+			return err
+		}
+
+		if _, err := tx.conn().ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to update golden: %w", err)
+		}
+
+		if doc.Status == "" {
+			doc.Status, doc.SubmittedAt, doc.PublishedAt, doc.ArchivedAt = stored.Status, stored.SubmittedAt, stored.PublishedAt, stored.ArchivedAt
+		}
+		if doc.Slug == "" {
+			doc.Slug = oldSlug
+		}
+		if oldSlug == "" || oldSlug == doc.Slug {
+			return nil
+		}
+
+		_, err = tx.conn().ExecContext(ctx, `
+			INSERT INTO slug_redirects (slug, golden_id, created_at) VALUES (?, ?, ?)
+			ON CONFLICT (slug) DO UPDATE SET golden_id = excluded.golden_id, created_at = excluded.created_at
+		`, oldSlug, doc.ID, formatTime(time.Now()))
+		if err != nil {
+			return fmt.Errorf("failed to record slug redirect: %w", err)
+		}
+		// A redirect the golden left behind on its new slug is dropped; one of
+		// another golden fails the rename.
+		if _, err := tx.conn().ExecContext(ctx, `DELETE FROM slug_redirects WHERE slug = ? AND golden_id = ?`, doc.Slug, doc.ID); err != nil {
+			return fmt.Errorf("failed to drop slug redirect: %w", err)
+		}
+		var taken bool
+		if err := tx.conn().QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM slug_redirects WHERE slug = ?)`, doc.Slug).Scan(&taken); err != nil {
+			return fmt.Errorf("failed to check slug redirect: %w", err)
+		}
+		if taken {
+			return fmt.Errorf("%w: %s", domain.ErrSlugTaken, doc.Slug)
+		}
+		return nil
+	})
+}
+
+func (r *GoldenRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.conn().ExecContext(ctx, `DELETE FROM goldens WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete golden: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%w: %s", domain.ErrNotFound, id)
+	}
+
+	return nil
+}
+
+func (r *GoldenRepository) Upsert(ctx context.Context, docs []domain.Golden, dryRun bool) ([]domain.ImportResult, error) {
+	if len(docs) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		INSERT INTO goldens (id, title, description, category, tags, updated_at, content_b64, cover_image, content_size, content_hash, slug,
+			status, submitted_at, published_at, archived_at, publish_at, expire_at)
+		VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9, ?10, ?11, COALESCE(NULLIF(?12, ''), 'draft'), ?13, ?14, ?15, ?16, ?17)
+		ON CONFLICT (id) DO UPDATE
+		SET title = excluded.title, description = excluded.description, category = excluded.category,
+			tags = excluded.tags, updated_at = excluded.updated_at, content_b64 = excluded.content_b64,
+			cover_image = excluded.cover_image, content_size = excluded.content_size, content_hash = excluded.content_hash,
+			slug = CASE WHEN goldens.slug = '' THEN excluded.slug ELSE goldens.slug END,
+			status = COALESCE(NULLIF(?12, ''), goldens.status),
+			submitted_at = CASE WHEN ?12 = '' THEN goldens.submitted_at ELSE ?13 END,
+			published_at = CASE WHEN ?12 = '' THEN goldens.published_at ELSE ?14 END,
+			archived_at = CASE WHEN ?12 = '' THEN goldens.archived_at ELSE ?15 END,
+			publish_at = COALESCE(NULLIF(?16, ''), goldens.publish_at), expire_at = COALESCE(NULLIF(?17, ''), goldens.expire_at)
+	`
+
+	results := make([]domain.ImportResult, 0, len(docs))
+	err := r.withTx(ctx, dryRun, func(tx *GoldenRepository) error {
+		for i := range docs {
+			doc := &docs[i]
+
+			var exists bool
+			err := tx.conn().QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM goldens WHERE id = ?)", doc.ID).Scan(&exists)
+			if err != nil {
+				return fmt.Errorf("failed to upsert goldens: %w", err)
+			}
+
+			args, err := writeArgs(doc)
+			if err != nil {
+				return fmt.Errorf("failed to upsert goldens: %w", err)
+			}
+			if _, err := tx.conn().ExecContext(ctx, query, args...); err != nil {
+				return fmt.Errorf("failed to upsert goldens: %w", err)
+			}
+
+			status := domain.ImportStatusCreated
+			if exists {
+				status = domain.ImportStatusUpdated
+			}
+			results = append(results, domain.ImportResult{ID: doc.ID, Status: status})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// AppendEvents discards events: the SQLite backend targets single-node
+// setups without an outbox relay.
+func (r *GoldenRepository) AppendEvents(ctx context.Context, events ...domain.Event) error {
+	return nil
+}
+
+func (r *GoldenRepository) AppendTransition(ctx context.Context, transition domain.GoldenTransition) error {
+	query := `
+		INSERT INTO golden_transitions (golden_id, from_status, to_status, reason, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	_, err := r.conn().ExecContext(ctx, query, transition.GoldenID, string(transition.From), string(transition.To), transition.Reason, formatTime(transition.At))
+	if err != nil {
+		return fmt.Errorf("failed to append golden transition: %w", err)
+	}
+
+	return nil
+}
+
+func (r *GoldenRepository) ListTransitions(ctx context.Context, id string) ([]domain.GoldenTransition, error) {
+	query := `
+		SELECT golden_id, from_status, to_status, reason, created_at
+		FROM golden_transitions
+		WHERE golden_id = ?
+		ORDER BY id
+	`
+
+	rows, err := r.conn().QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query golden transitions: %w", err)
+	}
+	defer rows.Close()
+
+	var transitions []domain.GoldenTransition
+	for rows.Next() {
+		var t domain.GoldenTransition
+		var at string
+		if err := rows.Scan(&t.GoldenID, &t.From, &t.To, &t.Reason, &at); err != nil {
+			return nil, fmt.Errorf("failed to scan golden transition: %w", err)
+		}
+		if t.At, err = time.Parse(timeLayout, at); err != nil {
+			return nil, fmt.Errorf("invalid time for golden transition: %w", err)
+		}
+		transitions = append(transitions, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating golden transitions: %w", err)
+	}
+
+	return transitions, nil
+}
+
+// RunInTx ignores the isolation level: SQLite transactions are always
+// serializable, and with a single connection they never conflict.
+func (r *GoldenRepository) RunInTx(ctx context.Context, fn func(tx domain.Repository) error, opts ...domain.TxOption) error {
+	return r.withTx(ctx, false, func(tx *GoldenRepository) error {
+		return fn(tx)
+	})
+}
+
+// withTx runs fn against a repository bound to a transaction. A repository
+// that is already bound joins its transaction. With rollback the changes made
+// by fn are discarded even when it succeeds, using a savepoint when joining.
+func (r *GoldenRepository) withTx(ctx context.Context, rollback bool, fn func(tx *GoldenRepository) error) error {
+	if r.tx != nil {
+		if !rollback {
+			return fn(r)
+		}
+
+		if _, err := r.tx.ExecContext(ctx, "SAVEPOINT dry_run"); err != nil {
+			return fmt.Errorf("failed to create savepoint: %w", err)
+		}
+		err := fn(r)
+		if _, rbErr := r.tx.ExecContext(ctx, "ROLLBACK TO dry_run"); rbErr != nil && err == nil {
+			err = fmt.Errorf("failed to roll back to savepoint: %w", rbErr)
+		}
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(&GoldenRepository{db: r.db, tx: tx}); err != nil {
+		return err
+	}
+	if rollback {
+		return nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *GoldenRepository) conn() querier {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+func selectColumns(view domain.GoldenView) string {
+	content := "content_b64"
+	if view == domain.GoldenViewBasic {
+		content = "'' AS content_b64"
+	}
+
+	return "id, title, description, category, tags, updated_at, " + content + ", cover_image, content_size, content_hash, slug, " +
+		"status, submitted_at, published_at, archived_at, publish_at, expire_at"
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanGolden(row rowScanner) (*domain.Golden, error) {
+	var doc domain.Golden
+	var tags, updatedAt string
+	var times [5]string
+
+	err := row.Scan(
+		&doc.ID,
+		&doc.Title,
+		&doc.Description,
+		&doc.Category,
+		&tags,
+		&updatedAt,
+		&doc.ContentB64,
+		&doc.CoverImage,
+		&doc.ContentSize,
+		&doc.ContentHash,
+		&doc.Slug,
+		&doc.Status,
+		&times[0],
+		&times[1],
+		&times[2],
+		&times[3],
+		&times[4],
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(tags), &doc.Tags); err != nil {
+		return nil, fmt.Errorf("invalid tags for golden %s: %w", doc.ID, err)
+	}
+	doc.UpdatedAt, err = time.Parse(timeLayout, updatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid updated_at for golden %s: %w", doc.ID, err)
+	}
+	if err := parseTimes(doc.ID, times[:], &doc.SubmittedAt, &doc.PublishedAt, &doc.ArchivedAt, &doc.PublishAt, &doc.ExpireAt); err != nil {
+		return nil, err
+	}
+
+	return &doc, nil
+}
+
+// parseTimes reads the optional time columns of golden id into fields, an
+// empty value being the zero time.
+func parseTimes(id string, values []string, fields ...*time.Time) error {
+	for i, field := range fields {
+		if values[i] == "" {
+			*field = time.Time{}
+			continue
+		}
+		t, err := time.Parse(timeLayout, values[i])
+		if err != nil {
+			return fmt.Errorf("invalid time for golden %s: %w", id, err)
+		}
+		*field = t
+	}
+	return nil
+}
+
+func writeArgs(doc *domain.Golden) ([]any, error) {
+	tags := doc.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	encodedTags, err := json.Marshal(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	size, hash := domain.ContentDigest(doc.ContentB64)
+	return []any{
+		doc.ID,
+		doc.Title,
+		doc.Description,
+		doc.Category,
+		string(encodedTags),
+		formatTime(doc.UpdatedAt),
+		doc.ContentB64,
+		doc.CoverImage,
+		size,
+		hash,
+		doc.Slug,
+		string(doc.Status),
+		formatOptionalTime(doc.SubmittedAt),
+		formatOptionalTime(doc.PublishedAt),
+		formatOptionalTime(doc.ArchivedAt),
+		formatOptionalTime(doc.PublishAt),
+		formatOptionalTime(doc.ExpireAt),
+	}, nil
+}
+
+// likeEscaper escapes the LIKE wildcards for ESCAPE '\'.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// backfillSlugs derives a slug for the goldens stored before slugs existed.
+func (r *GoldenRepository) backfillSlugs(ctx context.Context) error {
+	return r.withTx(ctx, false, func(tx *GoldenRepository) error {
+		rows, err := tx.conn().QueryContext(ctx, `SELECT id, title FROM goldens WHERE slug = '' ORDER BY updated_at, id`)
+		if err != nil {
+			return fmt.Errorf("failed to query goldens without slug: %w", err)
+		}
+		var docs []domain.Golden
+		for rows.Next() {
+			var doc domain.Golden
+			if err := rows.Scan(&doc.ID, &doc.Title); err != nil {
+				rows.Close()
+				return fmt.Errorf("failed to scan golden without slug: %w", err)
+			}
+			docs = append(docs, doc)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("error iterating goldens without slug: %w", err)
+		}
+
+		for _, doc := range docs {
+			base := doc.BaseSlug()
+			taken, err := tx.SlugsInUse(ctx, base)
+			if err != nil {
+				return err
+			}
+			if _, err := tx.conn().ExecContext(ctx, `UPDATE goldens SET slug = ? WHERE id = ?`, domain.UniqueSlug(base, taken), doc.ID); err != nil {
+				return fmt.Errorf("failed to backfill slug of %s: %w", doc.ID, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+// formatOptionalTime stores a zero time as an empty string.
+func formatOptionalTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return formatTime(t)
+}
+
+// DSN builds a modernc.org/sqlite connection string with the pragmas the
+// repository relies on.
+func DSN(path string) string {
+	pragmas := []string{
+		"_pragma=busy_timeout(5000)",
+		"_pragma=journal_mode(WAL)",
+		"_pragma=foreign_keys(1)",
+	}
+	return "file:" + path + "?" + strings.Join(pragmas, "&")
+}
@@ -0,0 +1,359 @@
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"markitos-it-svc-goldens/internal/domain"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+func helperIntegrationRepository(t *testing.T) *GoldenRepository {
+	t.Helper()
+
+	db, err := sql.Open(DriverName, DSN(filepath.Join(t.TempDir(), "goldens.db")))
+	if err != nil {
+		t.Fatalf("failed to open sqlite db: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	r := NewGoldenRepository(db)
+	if err := r.InitSchema(context.Background()); err != nil {
+		t.Fatalf("InitSchema() failed: %v", err)
+	}
+	return r
+}
+
+func TestGoldenRepository_InitSchema_Idempotent_Integration(t *testing.T) {
+	r := helperIntegrationRepository(t)
+
+	if err := r.InitSchema(context.Background()); err != nil {
+		t.Fatalf("second InitSchema() unexpected error: %v", err)
+	}
+}
+
+func TestGoldenRepository_CRUD_Integration(t *testing.T) {
+	r := helperIntegrationRepository(t)
+	ctx := context.Background()
+	older := helperRandomGolden(t)
+	newer := helperRandomGolden(t)
+	newer.UpdatedAt = older.UpdatedAt.Add(time.Hour)
+
+	for _, doc := range []*domain.Golden{older, newer} {
+		if err := r.Create(ctx, doc); err != nil {
+			t.Fatalf("Create() unexpected error: %v", err)
+		}
+	}
+
+	docs, err := r.GetAll(ctx, domain.GoldenViewFull)
+	if err != nil {
+		t.Fatalf("GetAll() unexpected error: %v", err)
+	}
+	if len(docs) != 2 || docs[0].ID != newer.ID {
+		t.Fatalf("expected newest first, got %+v", docs)
+	}
+	if !reflect.DeepEqual(docs[1].Tags, older.Tags) {
+		t.Errorf("Tags: want %v, got %v", older.Tags, docs[1].Tags)
+	}
+
+	basic, err := r.GetByID(ctx, older.ID, domain.GoldenViewBasic)
+	if err != nil {
+		t.Fatalf("GetByID() unexpected error: %v", err)
+	}
+	if basic.ContentB64 != "" || basic.ContentHash == "" {
+		t.Errorf("expected basic view with digest only, got %+v", basic)
+	}
+
+	older.Title = older.Title + "-updated"
+	if err := r.Update(ctx, older); err != nil {
+		t.Fatalf("Update() unexpected error: %v", err)
+	}
+	if err := r.Delete(ctx, newer.ID); err != nil {
+		t.Fatalf("Delete() unexpected error: %v", err)
+	}
+	if _, err := r.GetByID(ctx, newer.ID, domain.GoldenViewFull); err == nil {
+		t.Fatal("GetByID() expected not found error")
+	}
+	if err := r.Update(ctx, newer); err == nil {
+		t.Fatal("Update() expected not found error")
+	}
+	if err := r.Delete(ctx, newer.ID); err == nil {
+		t.Fatal("Delete() expected not found error")
+	}
+}
+
+func TestGoldenRepository_GetByIDs_Integration(t *testing.T) {
+	r := helperIntegrationRepository(t)
+	ctx := context.Background()
+	first, second := helperRandomGolden(t), helperRandomGolden(t)
+	for _, doc := range []*domain.Golden{first, second} {
+		if err := r.Create(ctx, doc); err != nil {
+			t.Fatalf("Create() unexpected error: %v", err)
+		}
+	}
+
+	docs, err := r.GetByIDs(ctx, []string{second.ID, "missing", first.ID}, domain.GoldenViewBasic)
+	if err != nil {
+		t.Fatalf("GetByIDs() unexpected error: %v", err)
+	}
+	ids := map[string]bool{}
+	for _, doc := range docs {
+		ids[doc.ID] = true
+		if doc.ContentB64 != "" {
+			t.Errorf("basic view returned content for %s", doc.ID)
+		}
+	}
+	if len(docs) != 2 || !ids[first.ID] || !ids[second.ID] {
+		t.Fatalf("GetByIDs() = %+v", docs)
+	}
+}
+
+func TestGoldenRepository_GetByCategories_Integration(t *testing.T) {
+	r := helperIntegrationRepository(t)
+	ctx := context.Background()
+	for _, doc := range []domain.Golden{
+		{ID: "old", Title: "Old", Category: "ci-cd", UpdatedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{ID: "new", Title: "New", Category: "devops", UpdatedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{ID: "api", Title: "API", Category: "apis", UpdatedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+	} {
+		if err := r.Create(ctx, &doc); err != nil {
+			t.Fatalf("Create() unexpected error: %v", err)
+		}
+	}
+
+	docs, err := r.GetByCategories(ctx, []string{"devops", "ci-cd"}, domain.GoldenViewBasic)
+	if err != nil {
+		t.Fatalf("GetByCategories() unexpected error: %v", err)
+	}
+	if len(docs) != 2 || docs[0].ID != "new" || docs[1].ID != "old" {
+		t.Fatalf("GetByCategories() = %+v", docs)
+	}
+}
+
+func TestGoldenRepository_CountFacets_Integration(t *testing.T) {
+	r := helperIntegrationRepository(t)
+	ctx := context.Background()
+	for _, doc := range []domain.Golden{
+		{ID: "a", Title: "A", Category: "DevOps", Tags: []string{"k8s", "ci-cd", "k8s"}},
+		{ID: "b", Title: "B", Category: "DevOps", Tags: []string{"k8s"}},
+		{ID: "c", Title: "C", Category: "APIs", Tags: []string{"rest"}},
+		{ID: "d", Title: "D", Tags: []string{"rest"}},
+		{ID: "draft", Title: "Draft", Category: "APIs", Tags: []string{"k8s"}, Status: domain.StatusDraft},
+		{ID: "due", Title: "Due", Category: "DevOps", Tags: []string{"ci-cd"}, Status: domain.StatusScheduled, PublishAt: time.Now().Add(-time.Hour)},
+		{ID: "later", Title: "Later", Category: "APIs", Tags: []string{"rest"}, Status: domain.StatusScheduled, PublishAt: time.Now().Add(time.Hour)},
+		{ID: "expired", Title: "Expired", Category: "APIs", Tags: []string{"rest"}, ExpireAt: time.Now().Add(-time.Hour)},
+	} {
+		if doc.Status == "" {
+			doc.Status = domain.StatusPublished
+		}
+		doc.UpdatedAt = time.Now().UTC()
+		if err := r.Create(ctx, &doc); err != nil {
+			t.Fatalf("Create() unexpected error: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		count func() ([]domain.FacetCount, error)
+		want  []domain.FacetCount
+	}{
+		{
+			name:  "categories",
+			count: func() ([]domain.FacetCount, error) { return r.CountCategories(ctx, "") },
+			want:  []domain.FacetCount{{Value: "DevOps", Count: 3}, {Value: "APIs", Count: 1}},
+		},
+		{
+			name:  "categories-by-tag",
+			count: func() ([]domain.FacetCount, error) { return r.CountCategories(ctx, "rest") },
+			want:  []domain.FacetCount{{Value: "APIs", Count: 1}},
+		},
+		{
+			name:  "tags",
+			count: func() ([]domain.FacetCount, error) { return r.CountTags(ctx, nil) },
+			want:  []domain.FacetCount{{Value: "ci-cd", Count: 2}, {Value: "k8s", Count: 2}, {Value: "rest", Count: 2}},
+		},
+		{
+			name:  "tags-by-category",
+			count: func() ([]domain.FacetCount, error) { return r.CountTags(ctx, []string{"APIs"}) },
+			want:  []domain.FacetCount{{Value: "rest", Count: 1}},
+		},
+		{
+			name:  "tags-by-categories",
+			count: func() ([]domain.FacetCount, error) { return r.CountTags(ctx, []string{"APIs", "DevOps"}) },
+			want:  []domain.FacetCount{{Value: "ci-cd", Count: 2}, {Value: "k8s", Count: 2}, {Value: "rest", Count: 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.count()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGoldenRepository_Upsert_Integration(t *testing.T) {
+	r := helperIntegrationRepository(t)
+	ctx := context.Background()
+	existing := helperRandomGolden(t)
+	created := helperRandomGolden(t)
+	if err := r.Create(ctx, existing); err != nil {
+		t.Fatalf("Create() unexpected error: %v", err)
+	}
+
+	results, err := r.Upsert(ctx, []domain.Golden{*existing, *created}, true)
+	if err != nil {
+		t.Fatalf("Upsert() unexpected error: %v", err)
+	}
+	if results[0].Status != domain.ImportStatusUpdated || results[1].Status != domain.ImportStatusCreated {
+		t.Fatalf("unexpected results %+v", results)
+	}
+	if _, err := r.GetByID(ctx, created.ID, domain.GoldenViewFull); err == nil {
+		t.Fatal("expected dry run to roll back")
+	}
+
+	if _, err := r.Upsert(ctx, []domain.Golden{*created}, false); err != nil {
+		t.Fatalf("Upsert() unexpected error: %v", err)
+	}
+	if _, err := r.GetByID(ctx, created.ID, domain.GoldenViewFull); err != nil {
+		t.Fatalf("expected upserted golden, got %v", err)
+	}
+}
+
+func TestGoldenRepository_SlugRedirects_Integration(t *testing.T) {
+	r := helperIntegrationRepository(t)
+	ctx := context.Background()
+	doc := helperRandomGolden(t)
+	doc.Slug = "keptn"
+	if err := r.Create(ctx, doc); err != nil {
+		t.Fatalf("Create() unexpected error: %v", err)
+	}
+
+	other := helperRandomGolden(t)
+	other.Slug = "keptn-2"
+	if err := r.Create(ctx, other); err != nil {
+		t.Fatalf("Create() unexpected error: %v", err)
+	}
+
+	doc.Slug = "keptn-guide"
+	if err := r.Update(ctx, doc); err != nil {
+		t.Fatalf("Update() unexpected error: %v", err)
+	}
+
+	for _, slug := range []string{"keptn", "keptn-guide"} {
+		got, err := r.GetBySlug(ctx, slug, domain.GoldenViewBasic)
+		if err != nil || got.ID != doc.ID || got.Slug != "keptn-guide" {
+			t.Fatalf("GetBySlug(%q) = %+v, %v", slug, got, err)
+		}
+	}
+
+	taken, err := r.SlugsInUse(ctx, "keptn")
+	if err != nil {
+		t.Fatalf("SlugsInUse() unexpected error: %v", err)
+	}
+	if domain.UniqueSlug("keptn", taken) != "keptn-3" {
+		t.Fatalf("SlugsInUse() = %v, want keptn and keptn-2", taken)
+	}
+
+	// An empty slug keeps the current one.
+	doc.Slug = ""
+	if err := r.Update(ctx, doc); err != nil || doc.Slug != "keptn-guide" {
+		t.Fatalf("Update() slug = %q, %v", doc.Slug, err)
+	}
+
+	// Another golden cannot take a slug redirecting to this one, but the
+	// golden itself can go back to it.
+	other.Slug = "keptn"
+	if err := r.Update(ctx, other); !errors.Is(err, domain.ErrSlugTaken) {
+		t.Fatalf("Update() onto a redirect of another golden: expected ErrSlugTaken, got %v", err)
+	}
+	doc.Slug = "keptn"
+	if err := r.Update(ctx, doc); err != nil {
+		t.Fatalf("Update() unexpected error: %v", err)
+	}
+	for _, slug := range []string{"keptn", "keptn-guide"} {
+		got, err := r.GetBySlug(ctx, slug, domain.GoldenViewBasic)
+		if err != nil || got.ID != doc.ID || got.Slug != "keptn" {
+			t.Fatalf("GetBySlug(%q) after renaming back = %+v, %v", slug, got, err)
+		}
+	}
+}
+
+func TestGoldenRepository_StatusAndTransitions_Integration(t *testing.T) {
+	r := helperIntegrationRepository(t)
+	ctx := context.Background()
+	doc := helperRandomGolden(t)
+	doc.Status, doc.PublishedAt = "", time.Time{}
+	if err := r.Create(ctx, doc); err != nil {
+		t.Fatalf("Create() unexpected error: %v", err)
+	}
+
+	got, err := r.GetByID(ctx, doc.ID, domain.GoldenViewBasic)
+	if err != nil || got.Status != domain.StatusDraft {
+		t.Fatalf("GetByID() = %+v, %v; want a draft", got, err)
+	}
+
+	at := time.Date(2026, 3, 7, 9, 0, 0, 0, time.UTC)
+	transition, err := got.Transition(domain.StatusInReview, "ready", at)
+	if err != nil {
+		t.Fatalf("Transition() unexpected error: %v", err)
+	}
+	got.PublishAt = at.Add(24 * time.Hour)
+	if err := r.Update(ctx, got); err != nil {
+		t.Fatalf("Update() unexpected error: %v", err)
+	}
+	if err := r.AppendTransition(ctx, transition); err != nil {
+		t.Fatalf("AppendTransition() unexpected error: %v", err)
+	}
+
+	// Writes without a status keep the stored one.
+	doc.Status = ""
+	if err := r.Update(ctx, doc); err != nil {
+		t.Fatalf("Update() unexpected error: %v", err)
+	}
+	if doc.Status != domain.StatusInReview || !doc.SubmittedAt.Equal(at) {
+		t.Fatalf("Update() left %s submitted at %v, want the stored status", doc.Status, doc.SubmittedAt)
+	}
+	// Unlike the status, publish_at is written as given.
+	if stored, err := r.GetByID(ctx, doc.ID, domain.GoldenViewBasic); err != nil || !stored.PublishAt.IsZero() {
+		t.Fatalf("GetByID() after update = %+v, %v; want publish_at cleared", stored, err)
+	}
+	if _, err := r.Upsert(ctx, []domain.Golden{*doc}, false); err != nil {
+		t.Fatalf("Upsert() unexpected error: %v", err)
+	}
+	if got, err = r.GetByID(ctx, doc.ID, domain.GoldenViewBasic); err != nil || got.Status != domain.StatusInReview {
+		t.Fatalf("GetByID() after upsert = %+v, %v; want in_review", got, err)
+	}
+
+	// Imports restore the status they carry.
+	restored := helperRandomGolden(t)
+	restored.Status, restored.PublishedAt = domain.StatusArchived, at
+	restored.ArchivedAt = at.Add(time.Hour)
+	if _, err := r.Upsert(ctx, []domain.Golden{*restored}, false); err != nil {
+		t.Fatalf("Upsert() unexpected error: %v", err)
+	}
+	if got, err = r.GetByID(ctx, restored.ID, domain.GoldenViewBasic); err != nil || got.Status != domain.StatusArchived || !got.ArchivedAt.Equal(restored.ArchivedAt) {
+		t.Fatalf("GetByID() after restoring upsert = %+v, %v; want archived", got, err)
+	}
+
+	transitions, err := r.ListTransitions(ctx, doc.ID)
+	if err != nil {
+		t.Fatalf("ListTransitions() unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(transitions, []domain.GoldenTransition{transition}) {
+		t.Fatalf("ListTransitions() = %+v, want %+v", transitions, transition)
+	}
+}
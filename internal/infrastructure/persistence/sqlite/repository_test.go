@@ -0,0 +1,178 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"markitos-it-svc-goldens/internal/domain"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// unavailableDriver fails every connection, standing in for a broken database.
+type unavailableDriver struct{}
+
+func (unavailableDriver) Open(name string) (driver.Conn, error) {
+	return nil, errors.New("database unavailable")
+}
+
+func init() {
+	sql.Register("sqlite-unavailable", unavailableDriver{})
+}
+
+func helperUnavailableDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite-unavailable", "")
+	if err != nil {
+		t.Fatalf("failed to create db handle: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	return db
+}
+
+func helperRandomGolden(t *testing.T) *domain.Golden {
+	t.Helper()
+
+	prefix := domain.HelperRandomAlphaPrefix(t, 8)
+	return &domain.Golden{
+		ID:          prefix + "-golden-id",
+		Slug:        prefix + "-golden-slug",
+		Title:       prefix + "-golden-title",
+		Description: prefix + "-golden-description",
+		Category:    prefix + "-golden-category",
+		Tags:        []string{prefix + "-go", prefix + "-grpc", prefix + "-sqlite"},
+		UpdatedAt:   time.Date(2026, 3, 6, 12, 0, 0, 0, time.UTC),
+		ContentB64:  prefix + "-Y29udGVudA==",
+		CoverImage:  "https://example.com/" + prefix + "/cover.png",
+		Status:      domain.StatusPublished,
+		PublishedAt: time.Date(2026, 3, 6, 12, 0, 0, 0, time.UTC),
+		ExpireAt:    time.Date(2027, 3, 6, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestNewGoldenRepository(t *testing.T) {
+	db := helperUnavailableDB(t)
+
+	got := NewGoldenRepository(db)
+	if got == nil {
+		t.Fatal("NewGoldenRepository() returned nil")
+	}
+	if got.db != db {
+		t.Errorf("NewGoldenRepository().db = %v, want %v", got.db, db)
+	}
+}
+
+func TestGoldenRepository_ReturnsErrorsOnUnavailableDB(t *testing.T) {
+	r := NewGoldenRepository(helperUnavailableDB(t))
+	doc := helperRandomGolden(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{name: "InitSchema", call: func() error { return r.InitSchema(ctx) }},
+		{name: "GetAll", call: func() error { _, err := r.GetAll(ctx, domain.GoldenViewFull); return err }},
+		{name: "GetByID", call: func() error { _, err := r.GetByID(ctx, doc.ID, domain.GoldenViewBasic); return err }},
+		{name: "GetByIDs", call: func() error { _, err := r.GetByIDs(ctx, []string{doc.ID}, domain.GoldenViewBasic); return err }},
+		{name: "GetByCategories", call: func() error { _, err := r.GetByCategories(ctx, []string{"devops"}, domain.GoldenViewBasic); return err }},
+		{name: "CountCategories", call: func() error { _, err := r.CountCategories(ctx, ""); return err }},
+		{name: "CountTags", call: func() error { _, err := r.CountTags(ctx, nil); return err }},
+		{name: "Create", call: func() error { return r.Create(ctx, doc) }},
+		{name: "Update", call: func() error { return r.Update(ctx, doc) }},
+		{name: "Delete", call: func() error { return r.Delete(ctx, doc.ID) }},
+		{name: "Upsert", call: func() error { _, err := r.Upsert(ctx, []domain.Golden{*doc}, false); return err }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); err == nil {
+				t.Fatalf("GoldenRepository.%s() expected error", tt.name)
+			}
+		})
+	}
+}
+
+func TestGoldenRepository_Upsert_EmptyBatchIsNoop(t *testing.T) {
+	r := NewGoldenRepository(helperUnavailableDB(t))
+
+	got, err := r.Upsert(context.Background(), nil, false)
+	if err != nil || got != nil {
+		t.Fatalf("Upsert() = %v, %v; want nil, nil", got, err)
+	}
+}
+
+func TestWriteArgs_EncodesTagsAndDigest(t *testing.T) {
+	doc := helperRandomGolden(t)
+	doc.Tags = nil
+
+	args, err := writeArgs(doc)
+	if err != nil {
+		t.Fatalf("writeArgs() unexpected error: %v", err)
+	}
+
+	wantSize, wantHash := domain.ContentDigest(doc.ContentB64)
+	if args[4] != "[]" {
+		t.Errorf("tags: want [], got %v", args[4])
+	}
+	if args[5] != "2026-03-06T12:00:00.000000000Z" {
+		t.Errorf("updated_at: want fixed width UTC, got %v", args[5])
+	}
+	if args[8] != wantSize || args[9] != wantHash {
+		t.Errorf("digest: want %d/%s, got %v/%v", wantSize, wantHash, args[8], args[9])
+	}
+}
+
+type fakeRow []any
+
+func (r fakeRow) Scan(dest ...any) error {
+	for i, d := range dest {
+		elem := reflect.ValueOf(d).Elem()
+		elem.Set(reflect.ValueOf(r[i]).Convert(elem.Type()))
+	}
+	return nil
+}
+
+func TestScanGolden(t *testing.T) {
+	doc := helperRandomGolden(t)
+	args, err := writeArgs(doc)
+	if err != nil {
+		t.Fatalf("writeArgs() unexpected error: %v", err)
+	}
+
+	got, err := scanGolden(fakeRow(args))
+	if err != nil {
+		t.Fatalf("scanGolden() unexpected error: %v", err)
+	}
+	doc.ContentSize, doc.ContentHash = domain.ContentDigest(doc.ContentB64)
+	if !reflect.DeepEqual(got, doc) {
+		t.Fatalf("scanGolden() = %+v, want %+v", got, doc)
+	}
+
+	args[4] = "not json"
+	if _, err := scanGolden(fakeRow(args)); err == nil {
+		t.Fatal("scanGolden() expected error for invalid tags")
+	}
+}
+
+func TestSelectColumns(t *testing.T) {
+	if got := selectColumns(domain.GoldenViewBasic); !strings.Contains(got, "'' AS content_b64") {
+		t.Errorf("basic view should omit content, got %q", got)
+	}
+	if got := selectColumns(domain.GoldenViewFull); strings.Contains(got, "''") {
+		t.Errorf("full view should select content, got %q", got)
+	}
+}
+
+func TestDSN(t *testing.T) {
+	got := DSN("/tmp/goldens.db")
+	if !strings.HasPrefix(got, "file:/tmp/goldens.db?") || !strings.Contains(got, "journal_mode(WAL)") {
+		t.Fatalf("unexpected DSN %q", got)
+	}
+}
@@ -0,0 +1,75 @@
+package rest
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	grpcserver "markitos-it-svc-goldens/internal/infrastructure/grpc"
+)
+
+// CORSConfig lets browsers on other origins call the gateway. CORS headers
+// are only sent when AllowedOrigins is not empty.
+type CORSConfig struct {
+	// AllowedOrigins lists the exact origins allowed, or "*" for any.
+	AllowedOrigins []string
+	AllowedHeaders []string
+	ExposedHeaders []string
+	MaxAge         time.Duration
+}
+
+func DefaultCORSConfig() CORSConfig {
+	return CORSConfig{
+		AllowedHeaders: []string{"Authorization", "Content-Type", grpcserver.IdempotencyKeyHeader},
+		ExposedHeaders: []string{grpcserver.IdempotentReplayHeader},
+		MaxAge:         10 * time.Minute,
+	}
+}
+
+const allowedMethods = "GET, POST, PUT, DELETE"
+
+func (c CORSConfig) allows(origin string) bool {
+	return slices.Contains(c.AllowedOrigins, "*") || slices.Contains(c.AllowedOrigins, origin)
+}
+
+// Wrap answers preflight requests and adds the CORS headers to the responses
+// of next for allowed origins.
+func (c CORSConfig) Wrap(next http.Handler) http.Handler {
+	if len(c.AllowedOrigins) == 0 {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := w.Header()
+		header.Add("Vary", "Origin")
+
+		origin := r.Header.Get("Origin")
+		if origin == "" || !c.allows(origin) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		header.Set("Access-Control-Allow-Origin", origin)
+		if len(c.ExposedHeaders) > 0 {
+			header.Set("Access-Control-Expose-Headers", strings.Join(c.ExposedHeaders, ", "))
+		}
+
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			header.Add("Vary", "Access-Control-Request-Method")
+			header.Add("Vary", "Access-Control-Request-Headers")
+			header.Set("Access-Control-Allow-Methods", allowedMethods)
+			if len(c.AllowedHeaders) > 0 {
+				header.Set("Access-Control-Allow-Headers", strings.Join(c.AllowedHeaders, ", "))
+			}
+			if c.MaxAge > 0 {
+				header.Set("Access-Control-Max-Age", strconv.Itoa(int(c.MaxAge.Seconds())))
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
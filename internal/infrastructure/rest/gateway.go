@@ -0,0 +1,219 @@
+// Package rest serves GoldenService as JSON over plain HTTP for clients that
+// cannot speak gRPC.
+package rest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	pb "markitos-it-svc-goldens/proto/goldens/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+// route maps an HTTP pattern to a GoldenService method. Path wildcards and
+// query parameters are copied to the request fields of the same name.
+type route struct {
+	pattern string
+	method  string
+	// body names the request field decoded from the JSON body, "*" for the
+	// whole request and "" for no body.
+	body string
+	// fields overrides the request field of a path wildcard.
+	fields map[string]string
+}
+
+// Streaming methods (ImportGoldens, ExportGoldens) are gRPC only.
+var routes = []route{
+	{pattern: "GET /v1/goldens", method: "GetAllGoldens"},
+	{pattern: "GET /v1/goldens/{id}", method: "GetGoldenById"},
+	{pattern: "GET /v1/goldens/by-slug/{slug}", method: "GetGoldenBySlug"},
+	{pattern: "GET /v1/goldens:batchGet", method: "BatchGetGoldens"},
+	{pattern: "GET /v1/facets/categories", method: "ListCategories"},
+	{pattern: "GET /v1/facets/tags", method: "ListTags"},
+	{pattern: "POST /v1/goldens", method: "CreateGolden", body: "golden"},
+	{pattern: "PUT /v1/goldens/{id}", method: "UpdateGolden", body: "golden", fields: map[string]string{"id": "golden.id"}},
+	{pattern: "DELETE /v1/goldens/{id}", method: "DeleteGolden"},
+	{pattern: "POST /v1/goldens:transition", method: "TransitionGolden", body: "*"},
+	{pattern: "GET /v1/goldens:transitions", method: "ListGoldenTransitions"},
+	{pattern: "GET /v1/categories", method: "ListCategoryTree"},
+	{pattern: "GET /v1/categories/{id}", method: "GetCategory"},
+	{pattern: "POST /v1/categories", method: "CreateCategory", body: "category"},
+	{pattern: "PUT /v1/categories/{id}", method: "UpdateCategory", body: "category", fields: map[string]string{"id": "category.id"}},
+	{pattern: "DELETE /v1/categories/{id}", method: "DeleteCategory"},
+	{pattern: "GET /v1/tags", method: "ListRegisteredTags"},
+	{pattern: "PUT /v1/tags/{name}", method: "RegisterTag", body: "tag", fields: map[string]string{"name": "tag.name"}},
+	{pattern: "DELETE /v1/tags/{name}", method: "UnregisterTag"},
+	{pattern: "POST /v1/tags:rename", method: "RenameTag", body: "*"},
+	{pattern: "POST /v1/tags:merge", method: "MergeTags", body: "*"},
+	{pattern: "POST /v1/webhooks", method: "CreateWebhookSubscription", body: "*"},
+	{pattern: "GET /v1/webhooks", method: "ListWebhookSubscriptions"},
+	{pattern: "DELETE /v1/webhooks/{id}", method: "DeleteWebhookSubscription"},
+	{pattern: "GET /v1/webhooks/deliveries", method: "ListWebhookDeliveries"},
+}
+
+var wildcardPattern = regexp.MustCompile(`\{(\w+)\}`)
+
+// maxBodyBytes matches the default gRPC receive limit.
+const maxBodyBytes = 4 << 20
+
+type Option func(*Gateway)
+
+// WithInterceptors runs the unary interceptors of the gRPC server around every
+// call, so that both transports share idempotency keys and write tracking.
+func WithInterceptors(interceptors ...grpc.UnaryServerInterceptor) Option {
+	return func(g *Gateway) {
+		g.interceptors = append(g.interceptors, interceptors...)
+	}
+}
+
+func WithCORS(config CORSConfig) Option {
+	return func(g *Gateway) {
+		g.cors = config
+	}
+}
+
+// Gateway is an http.Handler calling a GoldenServiceServer in process.
+type Gateway struct {
+	server       pb.GoldenServiceServer
+	interceptors []grpc.UnaryServerInterceptor
+	cors         CORSConfig
+	handler      http.Handler
+}
+
+func NewGateway(server pb.GoldenServiceServer, opts ...Option) *Gateway {
+	g := &Gateway{server: server}
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	methods := make(map[string]grpc.MethodDesc)
+	for _, desc := range pb.GoldenService_ServiceDesc.Methods {
+		methods[desc.MethodName] = desc
+	}
+
+	mux := http.NewServeMux()
+	interceptor := chainUnaryInterceptors(g.interceptors)
+	for _, rt := range routes {
+		desc, ok := methods[rt.method]
+		if !ok {
+			panic(fmt.Sprintf("rest: route %q targets unknown method %s", rt.pattern, rt.method))
+		}
+		mux.Handle(rt.pattern, g.handle(rt, desc, interceptor))
+	}
+	mux.HandleFunc("GET /openapi.json", serveOpenAPI)
+	g.handler = g.cors.Wrap(mux)
+
+	return g
+}
+
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.handler.ServeHTTP(w, r)
+}
+
+// handle decodes the request through the generated method handler, which
+// also applies the interceptors as a gRPC call would.
+func (g *Gateway) handle(rt route, desc grpc.MethodDesc, interceptor grpc.UnaryServerInterceptor) http.Handler {
+	fullMethod := "/" + pb.GoldenService_ServiceDesc.ServiceName + "/" + desc.MethodName
+	wildcards := wildcardPattern.FindAllStringSubmatch(rt.pattern, -1)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stream := &transportStream{method: fullMethod}
+		ctx := metadata.NewIncomingContext(r.Context(), incomingMetadata(r))
+		ctx = grpc.NewContextWithServerTransportStream(ctx, stream)
+
+		dec := func(req any) error {
+			msg := req.(proto.Message)
+			if err := decodeBody(w, r, rt.body, msg); err != nil {
+				return err
+			}
+			if rt.body != "*" {
+				if err := decodeQuery(r.URL.Query(), msg); err != nil {
+					return err
+				}
+			}
+			for _, wildcard := range wildcards {
+				field := wildcard[1]
+				if override, ok := rt.fields[field]; ok {
+					field = override
+				}
+				if err := setField(msg.ProtoReflect(), field, r.PathValue(wildcard[1])); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		resp, err := desc.Handler(g.server, ctx, dec, interceptor)
+		stream.copyTo(w.Header())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeMessage(w, http.StatusOK, resp.(proto.Message))
+	})
+}
+
+// incomingMetadata forwards the request headers as gRPC metadata, with the
+// lower-case keys gRPC uses.
+func incomingMetadata(r *http.Request) metadata.MD {
+	md := metadata.MD{}
+	for key, values := range r.Header {
+		md.Append(strings.ToLower(key), values...)
+	}
+	return md
+}
+
+// chainUnaryInterceptors nests interceptors so that the first one is the
+// outermost, like grpc.ChainUnaryInterceptor.
+func chainUnaryInterceptors(interceptors []grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	if len(interceptors) == 0 {
+		return nil
+	}
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		for i := len(interceptors) - 1; i > 0; i-- {
+			interceptor, next := interceptors[i], handler
+			handler = func(ctx context.Context, req any) (any, error) {
+				return interceptor(ctx, req, info, next)
+			}
+		}
+		return interceptors[0](ctx, req, info, handler)
+	}
+}
+
+// transportStream collects the headers and trailers set by the handler, which
+// are sent back as HTTP response headers.
+type transportStream struct {
+	method string
+	header metadata.MD
+}
+
+func (s *transportStream) Method() string {
+	return s.method
+}
+
+func (s *transportStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *transportStream) SendHeader(md metadata.MD) error {
+	return s.SetHeader(md)
+}
+
+func (s *transportStream) SetTrailer(md metadata.MD) error {
+	return s.SetHeader(md)
+}
+
+func (s *transportStream) copyTo(header http.Header) {
+	for key, values := range s.header {
+		for _, value := range values {
+			header.Add(key, value)
+		}
+	}
+}
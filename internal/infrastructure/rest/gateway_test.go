@@ -0,0 +1,252 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	pb "markitos-it-svc-goldens/proto/goldens/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+type stubServer struct {
+	pb.UnimplementedGoldenServiceServer
+	got proto.Message
+	err error
+}
+
+func (s *stubServer) GetGoldenById(_ context.Context, req *pb.GetGoldenByIdRequest) (*pb.GetGoldenByIdResponse, error) {
+	s.got = req
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &pb.GetGoldenByIdResponse{Golden: &pb.Golden{Id: req.Id, Title: "Keptn"}}, nil
+}
+
+func (s *stubServer) BatchGetGoldens(_ context.Context, req *pb.BatchGetGoldensRequest) (*pb.BatchGetGoldensResponse, error) {
+	s.got = req
+	return &pb.BatchGetGoldensResponse{MissingIds: req.Ids}, nil
+}
+
+func (s *stubServer) UpdateGolden(_ context.Context, req *pb.UpdateGoldenRequest) (*pb.UpdateGoldenResponse, error) {
+	s.got = req
+	return &pb.UpdateGoldenResponse{Golden: req.Golden}, nil
+}
+
+func (s *stubServer) CreateWebhookSubscription(_ context.Context, req *pb.CreateWebhookSubscriptionRequest) (*pb.CreateWebhookSubscriptionResponse, error) {
+	s.got = req
+	return &pb.CreateWebhookSubscriptionResponse{Subscription: &pb.WebhookSubscription{Id: "sub-1", Url: req.Url}}, nil
+}
+
+func (s *stubServer) ListWebhookDeliveries(_ context.Context, req *pb.ListWebhookDeliveriesRequest) (*pb.ListWebhookDeliveriesResponse, error) {
+	s.got = req
+	return &pb.ListWebhookDeliveriesResponse{}, nil
+}
+
+func serve(t *testing.T, handler http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestGateway_PathAndQueryParameters(t *testing.T) {
+	srv := &stubServer{}
+	rec := serve(t, NewGateway(srv), http.MethodGet, "/v1/goldens/keptn?view=GOLDEN_VIEW_BASIC", "")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+	want := &pb.GetGoldenByIdRequest{Id: "keptn", View: pb.GoldenView_GOLDEN_VIEW_BASIC}
+	if !proto.Equal(srv.got, want) {
+		t.Fatalf("request = %v, want %v", srv.got, want)
+	}
+
+	var resp struct {
+		Golden struct {
+			ID    string   `json:"id"`
+			Title string   `json:"title"`
+			Tags  []string `json:"tags"`
+		} `json:"golden"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid JSON response %s: %v", rec.Body, err)
+	}
+	if resp.Golden.ID != "keptn" || resp.Golden.Title != "Keptn" || resp.Golden.Tags == nil {
+		t.Fatalf("unexpected response %s", rec.Body)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/json" {
+		t.Fatalf("Content-Type = %q", got)
+	}
+}
+
+func TestGateway_BodyField(t *testing.T) {
+	srv := &stubServer{}
+	rec := serve(t, NewGateway(srv), http.MethodPut, "/v1/goldens/keptn", `{"id": "ignored", "title": "Keptn", "tags": ["cd"]}`)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+	want := &pb.UpdateGoldenRequest{Golden: &pb.Golden{Id: "keptn", Title: "Keptn", Tags: []string{"cd"}}}
+	if !proto.Equal(srv.got, want) {
+		t.Fatalf("request = %v, want %v", srv.got, want)
+	}
+}
+
+func TestGateway_WholeBody(t *testing.T) {
+	srv := &stubServer{}
+	rec := serve(t, NewGateway(srv), http.MethodPost, "/v1/webhooks", `{"url": "https://example.com/hook", "eventTypes": ["golden.created"]}`)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+	want := &pb.CreateWebhookSubscriptionRequest{Url: "https://example.com/hook", EventTypes: []string{"golden.created"}}
+	if !proto.Equal(srv.got, want) {
+		t.Fatalf("request = %v, want %v", srv.got, want)
+	}
+}
+
+func TestGateway_RoutesLiteralSegments(t *testing.T) {
+	srv := &stubServer{}
+	rec := serve(t, NewGateway(srv), http.MethodGet, "/v1/webhooks/deliveries?subscription_id=sub-1&limit=5", "")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+	want := &pb.ListWebhookDeliveriesRequest{SubscriptionId: "sub-1", Limit: 5}
+	if !proto.Equal(srv.got, want) {
+		t.Fatalf("request = %v, want %v", srv.got, want)
+	}
+}
+
+func TestGateway_RepeatedQueryParameters(t *testing.T) {
+	srv := &stubServer{}
+	rec := serve(t, NewGateway(srv), http.MethodGet, "/v1/goldens:batchGet?ids=b&ids=a", "")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+	want := &pb.BatchGetGoldensRequest{Ids: []string{"b", "a"}}
+	if !proto.Equal(srv.got, want) {
+		t.Fatalf("request = %v, want %v", srv.got, want)
+	}
+}
+
+func TestGateway_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		srv      *stubServer
+		method   string
+		target   string
+		body     string
+		wantCode int
+		wantRPC  codes.Code
+	}{
+		{"not found", &stubServer{err: status.Error(codes.NotFound, "missing")}, http.MethodGet, "/v1/goldens/x", "", http.StatusNotFound, codes.NotFound},
+		{"unavailable", &stubServer{err: status.Error(codes.Unavailable, "down")}, http.MethodGet, "/v1/goldens/x", "", http.StatusServiceUnavailable, codes.Unavailable},
+		{"unknown query parameter", &stubServer{}, http.MethodGet, "/v1/goldens/x?colour=red", "", http.StatusBadRequest, codes.InvalidArgument},
+		{"invalid enum", &stubServer{}, http.MethodGet, "/v1/goldens/x?view=HUGE", "", http.StatusBadRequest, codes.InvalidArgument},
+		{"invalid body", &stubServer{}, http.MethodPut, "/v1/goldens/x", `{"title": 1}`, http.StatusBadRequest, codes.InvalidArgument},
+		{"unimplemented", &stubServer{}, http.MethodDelete, "/v1/goldens/x", "", http.StatusNotImplemented, codes.Unimplemented},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(t, NewGateway(tt.srv), tt.method, tt.target, tt.body)
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.wantCode, rec.Body)
+			}
+			var body struct {
+				Code    codes.Code `json:"code"`
+				Message string     `json:"message"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("invalid JSON error %s: %v", rec.Body, err)
+			}
+			if body.Code != tt.wantRPC || body.Message == "" {
+				t.Fatalf("error body = %s", rec.Body)
+			}
+		})
+	}
+}
+
+func TestGateway_Interceptors(t *testing.T) {
+	var order []string
+	interceptor := func(name string) grpc.UnaryServerInterceptor {
+		return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			order = append(order, name)
+			if info.FullMethod != pb.GoldenService_GetGoldenById_FullMethodName {
+				t.Errorf("FullMethod = %q", info.FullMethod)
+			}
+			if got := metadata.ValueFromIncomingContext(ctx, "x-request-id"); len(got) != 1 || got[0] != "r-1" {
+				t.Errorf("x-request-id metadata = %v", got)
+			}
+			if err := grpc.SetHeader(ctx, metadata.Pairs("x-"+name, "true")); err != nil {
+				t.Errorf("SetHeader() error = %v", err)
+			}
+			return handler(ctx, req)
+		}
+	}
+
+	gateway := NewGateway(&stubServer{}, WithInterceptors(interceptor("outer"), interceptor("inner")))
+	req := httptest.NewRequest(http.MethodGet, "/v1/goldens/keptn", nil)
+	req.Header.Set("X-Request-Id", "r-1")
+	rec := httptest.NewRecorder()
+	gateway.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+	if strings.Join(order, ",") != "outer,inner" {
+		t.Fatalf("interceptor order = %v", order)
+	}
+	if rec.Header().Get("X-Outer") != "true" || rec.Header().Get("X-Inner") != "true" {
+		t.Fatalf("response headers = %v", rec.Header())
+	}
+}
+
+func TestGateway_CORS(t *testing.T) {
+	config := DefaultCORSConfig()
+	config.AllowedOrigins = []string{"https://portal.example.com"}
+	gateway := NewGateway(&stubServer{}, WithCORS(config))
+
+	preflight := httptest.NewRequest(http.MethodOptions, "/v1/goldens", nil)
+	preflight.Header.Set("Origin", "https://portal.example.com")
+	preflight.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	rec := httptest.NewRecorder()
+	gateway.ServeHTTP(rec, preflight)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("preflight status = %d", rec.Code)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://portal.example.com" {
+		t.Fatalf("Access-Control-Allow-Origin = %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Headers"); !strings.Contains(got, "idempotency-key") {
+		t.Fatalf("Access-Control-Allow-Headers = %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Max-Age"); got != "600" {
+		t.Fatalf("Access-Control-Max-Age = %q", got)
+	}
+
+	other := httptest.NewRequest(http.MethodGet, "/v1/goldens/keptn", nil)
+	other.Header.Set("Origin", "https://evil.example.com")
+	rec = httptest.NewRecorder()
+	gateway.ServeHTTP(rec, other)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Fatalf("disallowed origin got Access-Control-Allow-Origin = %q", got)
+	}
+}
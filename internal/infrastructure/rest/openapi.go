@@ -0,0 +1,253 @@
+package rest
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	pb "markitos-it-svc-goldens/proto/goldens/v1"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// openAPISpec is the output of OpenAPI, regenerated by make proto.
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+const statusSchema = "Status"
+
+type openAPIDocument struct {
+	OpenAPI    string                          `json:"openapi"`
+	Info       openAPIInfo                     `json:"info"`
+	Paths      map[string]map[string]operation `json:"paths"`
+	Components openAPIComponents               `json:"components"`
+}
+
+type openAPIInfo struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Version     string `json:"version"`
+}
+
+type openAPIComponents struct {
+	Schemas map[string]*schema `json:"schemas"`
+}
+
+type operation struct {
+	OperationID string              `json:"operationId"`
+	Tags        []string            `json:"tags"`
+	Parameters  []parameter         `json:"parameters,omitempty"`
+	RequestBody *requestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]response `json:"responses"`
+}
+
+type parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *schema `json:"schema"`
+}
+
+type requestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]mediaType `json:"content"`
+}
+
+type response struct {
+	Description string               `json:"description"`
+	Content     map[string]mediaType `json:"content"`
+}
+
+type mediaType struct {
+	Schema *schema `json:"schema"`
+}
+
+type schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Items                *schema            `json:"items,omitempty"`
+	Properties           map[string]*schema `json:"properties,omitempty"`
+	AdditionalProperties *schema            `json:"additionalProperties,omitempty"`
+}
+
+// OpenAPI describes the routes of the gateway as an OpenAPI 3 document, with
+// the schemas of their messages as encoded by protojson.
+func OpenAPI() ([]byte, error) {
+	service := pb.File_proto_goldens_v1_golden_proto.Services().ByName("GoldenService")
+	doc := openAPIDocument{
+		OpenAPI: "3.0.3",
+		Info: openAPIInfo{
+			Title:       "Goldens Service",
+			Description: "JSON gateway for " + string(service.FullName()) + ". Generated from proto/goldens/v1/golden.proto by make proto.",
+			Version:     "v1",
+		},
+		Paths: map[string]map[string]operation{},
+		Components: openAPIComponents{Schemas: map[string]*schema{
+			statusSchema: {
+				Type: "object",
+				Properties: map[string]*schema{
+					"code":    {Type: "integer", Format: "int32"},
+					"message": {Type: "string"},
+					"details": {Type: "array", Items: &schema{Type: "object"}},
+				},
+			},
+		}},
+	}
+	schemas := doc.Components.Schemas
+
+	for _, rt := range routes {
+		method := service.Methods().ByName(protoreflect.Name(rt.method))
+		httpMethod, path, _ := strings.Cut(rt.pattern, " ")
+		input := method.Input()
+
+		op := operation{
+			OperationID: rt.method,
+			Tags:        []string{string(service.Name())},
+			Responses: map[string]response{
+				"200": {
+					Description: "OK",
+					Content:     jsonContent(messageRef(method.Output(), schemas)),
+				},
+				"default": {
+					Description: "Error",
+					Content:     jsonContent(&schema{Ref: "#/components/schemas/" + statusSchema}),
+				},
+			},
+		}
+
+		bound := map[string]bool{}
+		for _, wildcard := range wildcardPattern.FindAllStringSubmatch(path, -1) {
+			field := wildcard[1]
+			if override, ok := rt.fields[field]; ok {
+				field = override
+			}
+			bound[field] = true
+			op.Parameters = append(op.Parameters, parameter{
+				Name:     wildcard[1],
+				In:       "path",
+				Required: true,
+				Schema:   fieldSchema(fieldByPath(input, field), schemas),
+			})
+		}
+
+		if rt.body == "*" {
+			op.RequestBody = &requestBody{Required: true, Content: jsonContent(messageRef(input, schemas))}
+		} else {
+			if rt.body != "" {
+				fd := input.Fields().ByName(protoreflect.Name(rt.body))
+				op.RequestBody = &requestBody{Required: true, Content: jsonContent(messageRef(fd.Message(), schemas))}
+			}
+			// Fields outside the body and the path are read from the query.
+			fields := input.Fields()
+			for i := 0; i < fields.Len(); i++ {
+				fd := fields.Get(i)
+				if bound[string(fd.Name())] || fd.Message() != nil {
+					continue
+				}
+				op.Parameters = append(op.Parameters, parameter{
+					Name:   string(fd.Name()),
+					In:     "query",
+					Schema: fieldSchema(fd, schemas),
+				})
+			}
+		}
+
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = map[string]operation{}
+		}
+		doc.Paths[path][strings.ToLower(httpMethod)] = op
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+func serveOpenAPI(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(openAPISpec)
+}
+
+func jsonContent(s *schema) map[string]mediaType {
+	return map[string]mediaType{"application/json": {Schema: s}}
+}
+
+func fieldByPath(msg protoreflect.MessageDescriptor, path string) protoreflect.FieldDescriptor {
+	names := strings.Split(path, ".")
+	for _, name := range names[:len(names)-1] {
+		msg = msg.Fields().ByName(protoreflect.Name(name)).Message()
+	}
+	return msg.Fields().ByName(protoreflect.Name(names[len(names)-1]))
+}
+
+// messageRef adds the schema of msg, and of the messages it uses, to schemas
+// and returns a reference to it.
+func messageRef(msg protoreflect.MessageDescriptor, schemas map[string]*schema) *schema {
+	if msg.FullName() == "google.protobuf.Timestamp" {
+		return &schema{Type: "string", Format: "date-time"}
+	}
+
+	name := string(msg.Name())
+	ref := &schema{Ref: "#/components/schemas/" + name}
+	if _, ok := schemas[name]; ok {
+		return ref
+	}
+
+	s := &schema{Type: "object", Properties: map[string]*schema{}}
+	schemas[name] = s
+	fields := msg.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		s.Properties[string(fd.Name())] = fieldSchema(fd, schemas)
+	}
+	return ref
+}
+
+func fieldSchema(fd protoreflect.FieldDescriptor, schemas map[string]*schema) *schema {
+	if fd.IsMap() {
+		return &schema{Type: "object", AdditionalProperties: singularSchema(fd.MapValue(), schemas)}
+	}
+	if fd.IsList() {
+		return &schema{Type: "array", Items: singularSchema(fd, schemas)}
+	}
+	return singularSchema(fd, schemas)
+}
+
+// singularSchema follows the protojson encoding, which writes 64-bit integers
+// as strings and enums by name.
+func singularSchema(fd protoreflect.FieldDescriptor, schemas map[string]*schema) *schema {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return messageRef(fd.Message(), schemas)
+	case protoreflect.EnumKind:
+		values := fd.Enum().Values()
+		s := &schema{Type: "string"}
+		for i := 0; i < values.Len(); i++ {
+			s.Enum = append(s.Enum, string(values.Get(i).Name()))
+		}
+		return s
+	case protoreflect.BoolKind:
+		return &schema{Type: "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return &schema{Type: "integer", Format: "int32"}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return &schema{Type: "integer", Format: "int64"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return &schema{Type: "string", Format: "int64"}
+	case protoreflect.FloatKind:
+		return &schema{Type: "number", Format: "float"}
+	case protoreflect.DoubleKind:
+		return &schema{Type: "number", Format: "double"}
+	case protoreflect.BytesKind:
+		return &schema{Type: "string", Format: "byte"}
+	default:
+		return &schema{Type: "string"}
+	}
+}
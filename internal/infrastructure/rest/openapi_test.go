@@ -0,0 +1,71 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestOpenAPI_UpToDate(t *testing.T) {
+	spec, err := OpenAPI()
+	if err != nil {
+		t.Fatalf("OpenAPI() error = %v", err)
+	}
+	if !bytes.Equal(spec, openAPISpec) {
+		t.Fatal("openapi.json is stale; run make proto to regenerate it")
+	}
+}
+
+func TestOpenAPI_DescribesRoutesAndMessages(t *testing.T) {
+	var doc openAPIDocument
+	if err := json.Unmarshal(openAPISpec, &doc); err != nil {
+		t.Fatalf("invalid openapi.json: %v", err)
+	}
+
+	for _, rt := range routes {
+		method, path, _ := bytes.Cut([]byte(rt.pattern), []byte(" "))
+		op, ok := doc.Paths[string(path)][string(bytes.ToLower(method))]
+		if !ok || op.OperationID != rt.method {
+			t.Errorf("missing operation %s for %q", rt.method, rt.pattern)
+		}
+	}
+
+	update := doc.Paths["/v1/goldens/{id}"]["put"]
+	if len(update.Parameters) != 3 || update.Parameters[0].In != "path" || update.Parameters[0].Schema.Type != "string" {
+		t.Errorf("UpdateGolden parameters = %+v", update.Parameters)
+	} else if clear := update.Parameters[1]; clear.Name != "clear_publish_at" || clear.In != "query" || clear.Schema.Type != "boolean" {
+		t.Errorf("UpdateGolden query parameter = %+v", clear)
+	}
+	if ref := update.RequestBody.Content["application/json"].Schema.Ref; ref != "#/components/schemas/Golden" {
+		t.Errorf("UpdateGolden body = %q", ref)
+	}
+
+	golden := doc.Components.Schemas["Golden"]
+	if golden == nil {
+		t.Fatal("missing Golden schema")
+	}
+	for field, want := range map[string]schema{
+		"content_b64":  {Type: "string"},
+		"content_size": {Type: "string", Format: "int64"},
+		"updated_at":   {Type: "string", Format: "date-time"},
+	} {
+		got := golden.Properties[field]
+		if got == nil || got.Type != want.Type || got.Format != want.Format {
+			t.Errorf("Golden.%s = %+v, want %+v", field, got, want)
+		}
+	}
+	if tags := golden.Properties["tags"]; tags == nil || tags.Type != "array" || tags.Items.Type != "string" {
+		t.Errorf("Golden.tags = %+v", tags)
+	}
+}
+
+func TestGateway_ServesOpenAPI(t *testing.T) {
+	rec := serve(t, NewGateway(&stubServer{}), http.MethodGet, "/openapi.json", "")
+	if rec.Code != http.StatusOK || !bytes.Equal(rec.Body.Bytes(), openAPISpec) {
+		t.Fatalf("GET /openapi.json = %d", rec.Code)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/json" {
+		t.Fatalf("Content-Type = %q", got)
+	}
+}
@@ -0,0 +1,236 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"markitos-it-svc-goldens/internal/domain"
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+const (
+	HeaderEvent     = "X-Goldens-Event"
+	HeaderDelivery  = "X-Goldens-Delivery"
+	HeaderSignature = "X-Goldens-Signature"
+)
+
+type Config struct {
+	// Timeout bounds every single POST.
+	Timeout time.Duration
+	// AllowedHosts may be reached on private addresses by the default client.
+	AllowedHosts []string
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Timeout: 10 * time.Second,
+	}
+}
+
+// Payload is the JSON body POSTed to subscribers.
+type Payload struct {
+	ID          string          `json:"id"`
+	Type        string          `json:"type"`
+	AggregateID string          `json:"aggregate_id"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+	Data        json.RawMessage `json:"data"`
+}
+
+// Dispatcher is a domain.EventPublisher POSTing events to every matching
+// webhook subscription. Subscriptions are served concurrently, one attempt
+// each per Publish: the outbox relay retries events with backoff, and the
+// delivery log, which keeps every attempt, tells which subscriptions still
+// need the event.
+type Dispatcher struct {
+	store  domain.WebhookStore
+	client *http.Client
+	config Config
+}
+
+func NewDispatcher(store domain.WebhookStore, client *http.Client, config Config) *Dispatcher {
+	if client == nil {
+		client = NewClient(config.AllowedHosts)
+	}
+	return &Dispatcher{
+		store:  store,
+		client: client,
+		config: config,
+	}
+}
+
+// NewClient returns the HTTP client used when NewDispatcher is given none.
+// It refuses to connect to addresses that are not public, checked on the
+// address actually dialled so DNS cannot point a subscription back into the
+// network, except for allowedHosts. A proxy would hide that address, so
+// none is used.
+func NewClient(allowedHosts []string) *http.Client {
+	dialer := &net.Dialer{Timeout: 10 * time.Second, KeepAlive: 30 * time.Second}
+	guarded := &net.Dialer{Timeout: 10 * time.Second, KeepAlive: 30 * time.Second, Control: func(network, address string, _ syscall.RawConn) error {
+		addr, err := netip.ParseAddrPort(address)
+		if err != nil {
+			return err
+		}
+		if !domain.PublicAddr(addr.Addr()) {
+			return fmt.Errorf("refusing to connect to non-public address %s", address)
+		}
+		return nil
+	}}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+		if host, _, err := net.SplitHostPort(address); err == nil && slices.Contains(allowedHosts, host) {
+			return dialer.DialContext(ctx, network, address)
+		}
+		return guarded.DialContext(ctx, network, address)
+	}
+
+	return &http.Client{Transport: transport}
+}
+
+// Sign returns the HMAC-SHA256 signature sent in HeaderSignature, which
+// subscribers recompute over the raw body with their secret.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Publish fails while a matching subscription may still take the event, so
+// the relay retries it. Subscriptions that took it or answered with a client
+// error other than 429 are not tried again.
+func (d *Dispatcher) Publish(ctx context.Context, event domain.Event) error {
+	subs, err := d.store.ListSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+	past, err := d.store.ListEventDeliveries(ctx, event.ID)
+	if err != nil {
+		return err
+	}
+
+	attempts := make(map[string]int)
+	done := make(map[string]bool)
+	for _, delivery := range past {
+		attempts[delivery.SubscriptionID] = delivery.Attempt
+		done[delivery.SubscriptionID] = finished(delivery)
+	}
+
+	var golden domain.GoldenEventPayload
+	if err := json.Unmarshal(event.Payload, &golden); err != nil {
+		return fmt.Errorf("failed to decode %s payload: %w", event.Type, err)
+	}
+
+	body, err := json.Marshal(Payload{
+		ID:          event.ID,
+		Type:        string(event.Type),
+		AggregateID: event.AggregateID,
+		OccurredAt:  event.OccurredAt,
+		Data:        event.Payload,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	var wg sync.WaitGroup
+	var pending atomic.Int32
+	for _, sub := range subs {
+		if !sub.Matches(event.Type, golden.Category) || done[sub.ID] {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if !d.deliver(ctx, sub, event, body, attempts[sub.ID]+1) {
+				pending.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if n := pending.Load(); n > 0 {
+		return fmt.Errorf("%d webhook subscriptions still pending for event %s", n, event.ID)
+	}
+	return nil
+}
+
+// deliver makes one attempt and reports whether the subscription is done
+// with the event.
+func (d *Dispatcher) deliver(ctx context.Context, sub domain.WebhookSubscription, event domain.Event, body []byte, attempt int) bool {
+	delivery := d.post(ctx, sub, event, body)
+	delivery.Attempt = attempt
+	if err := d.store.RecordDelivery(ctx, &delivery); err != nil {
+		log.Printf("⚠️  Failed to record webhook delivery for %s: %v", sub.ID, err)
+	}
+
+	if !delivery.Succeeded && !retryable(delivery.StatusCode) {
+		log.Printf("⚠️  Webhook %s gave up on event %s: %s", sub.ID, event.ID, delivery.Error)
+	}
+	return finished(delivery)
+}
+
+func (d *Dispatcher) post(ctx context.Context, sub domain.WebhookSubscription, event domain.Event, body []byte) domain.WebhookDelivery {
+	delivery := domain.WebhookDelivery{
+		SubscriptionID: sub.ID,
+		EventID:        event.ID,
+		EventType:      event.Type,
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, d.config.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		delivery.Error = err.Error()
+		return delivery
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, string(event.Type))
+	req.Header.Set(HeaderDelivery, event.ID)
+	req.Header.Set(HeaderSignature, Sign(sub.Secret, body))
+
+	start := time.Now()
+	resp, err := d.client.Do(req)
+	delivery.Duration = time.Since(start)
+	if err != nil {
+		delivery.Error = err.Error()
+		return delivery
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	delivery.StatusCode = resp.StatusCode
+	delivery.Succeeded = resp.StatusCode >= 200 && resp.StatusCode < 300
+	if !delivery.Succeeded {
+		delivery.Error = resp.Status
+	}
+
+	return delivery
+}
+
+// finished reports whether a delivery ends the attempts of its subscription.
+func finished(delivery domain.WebhookDelivery) bool {
+	return delivery.Succeeded || !retryable(delivery.StatusCode)
+}
+
+// retryable reports whether a response status is worth another attempt.
+// Zero stands for a request that got no response at all.
+func retryable(statusCode int) bool {
+	return statusCode == 0 || statusCode == http.StatusTooManyRequests || statusCode >= 500
+}
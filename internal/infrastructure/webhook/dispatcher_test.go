@@ -0,0 +1,216 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"markitos-it-svc-goldens/internal/domain"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// memoryStore is an in-memory domain.WebhookStore.
+type memoryStore struct {
+	mu         sync.Mutex
+	subs       []domain.WebhookSubscription
+	deliveries []domain.WebhookDelivery
+}
+
+func (s *memoryStore) CreateSubscription(ctx context.Context, sub *domain.WebhookSubscription) error {
+	s.subs = append(s.subs, *sub)
+	return nil
+}
+
+func (s *memoryStore) ListSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	return s.subs, nil
+}
+
+func (s *memoryStore) DeleteSubscription(ctx context.Context, id string) error {
+	return nil
+}
+
+func (s *memoryStore) RecordDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deliveries = append(s.deliveries, *delivery)
+	return nil
+}
+
+func (s *memoryStore) ListDeliveries(ctx context.Context, subscriptionID string, limit int) ([]domain.WebhookDelivery, error) {
+	return s.deliveries, nil
+}
+
+func (s *memoryStore) ListEventDeliveries(ctx context.Context, eventID string) ([]domain.WebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var deliveries []domain.WebhookDelivery
+	for _, d := range s.deliveries {
+		if d.EventID == eventID {
+			deliveries = append(deliveries, d)
+		}
+	}
+	return deliveries, nil
+}
+
+func helperConfig() Config {
+	return Config{Timeout: time.Second}
+}
+
+func helperEvent(t *testing.T, category string) domain.Event {
+	t.Helper()
+
+	prefix := domain.HelperRandomAlphaPrefix(t, 8)
+	event, err := domain.NewGoldenEvent(domain.EventGoldenCreated, domain.Golden{ID: prefix, Title: prefix, Category: category})
+	if err != nil {
+		t.Fatalf("NewGoldenEvent() unexpected error: %v", err)
+	}
+	return event
+}
+
+func TestDispatcher_Publish_SignsPayload(t *testing.T) {
+	event := helperEvent(t, "DevOps")
+	received := make(chan *http.Request, 1)
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		received <- r
+	}))
+	defer server.Close()
+
+	store := &memoryStore{subs: []domain.WebhookSubscription{{ID: "sub", URL: server.URL, Secret: "s3cret"}}}
+	if err := NewDispatcher(store, server.Client(), helperConfig()).Publish(context.Background(), event); err != nil {
+		t.Fatalf("Publish() unexpected error: %v", err)
+	}
+
+	req := <-received
+	if got, want := req.Header.Get(HeaderSignature), Sign("s3cret", body); got != want {
+		t.Errorf("signature: want %q, got %q", want, got)
+	}
+	if got := req.Header.Get(HeaderEvent); got != string(domain.EventGoldenCreated) {
+		t.Errorf("event header: got %q", got)
+	}
+
+	var payload Payload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("body is not JSON: %v", err)
+	}
+	if payload.ID != event.ID || payload.AggregateID != event.AggregateID {
+		t.Errorf("unexpected payload: %+v", payload)
+	}
+	if len(store.deliveries) != 1 || !store.deliveries[0].Succeeded || store.deliveries[0].StatusCode != http.StatusOK {
+		t.Errorf("expected one successful delivery, got %+v", store.deliveries)
+	}
+}
+
+func TestDispatcher_Publish_RetriesOnlyPendingSubscriptions(t *testing.T) {
+	var okCalls, flakyCalls atomic.Int32
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		okCalls.Add(1)
+	}))
+	defer ok.Close()
+	flaky := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if flakyCalls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer flaky.Close()
+
+	store := &memoryStore{subs: []domain.WebhookSubscription{{ID: "ok", URL: ok.URL}, {ID: "flaky", URL: flaky.URL}}}
+	dispatcher := NewDispatcher(store, http.DefaultClient, helperConfig())
+	event := helperEvent(t, "")
+
+	// The relay publishes the event again until nothing is pending.
+	for i := 1; i <= 3; i++ {
+		err := dispatcher.Publish(context.Background(), event)
+		if (i < 3) != (err != nil) {
+			t.Fatalf("Publish() #%d error = %v", i, err)
+		}
+	}
+
+	if okCalls.Load() != 1 || flakyCalls.Load() != 3 {
+		t.Fatalf("expected 1 call to ok and 3 to flaky, got %d and %d", okCalls.Load(), flakyCalls.Load())
+	}
+	var attempts []int
+	for _, d := range store.deliveries {
+		if d.SubscriptionID == "flaky" {
+			attempts = append(attempts, d.Attempt)
+		}
+	}
+	if len(attempts) != 3 || attempts[2] != 3 || !store.deliveries[len(store.deliveries)-1].Succeeded {
+		t.Fatalf("expected 3 logged attempts ending in success, got %+v", store.deliveries)
+	}
+}
+
+func TestDispatcher_Publish_GivesUpOnClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusGone)
+	}))
+	defer server.Close()
+
+	store := &memoryStore{subs: []domain.WebhookSubscription{{ID: "sub", URL: server.URL}}}
+	dispatcher := NewDispatcher(store, server.Client(), helperConfig())
+	event := helperEvent(t, "")
+	for range 2 {
+		if err := dispatcher.Publish(context.Background(), event); err != nil {
+			t.Fatalf("Publish() unexpected error: %v", err)
+		}
+	}
+
+	if calls.Load() != 1 {
+		t.Fatalf("expected a single call, got %d", calls.Load())
+	}
+	if store.deliveries[0].Succeeded || store.deliveries[0].Error == "" {
+		t.Fatalf("expected failed delivery, got %+v", store.deliveries[0])
+	}
+}
+
+func TestDispatcher_Publish_RefusesPrivateAddresses(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer server.Close()
+
+	store := &memoryStore{subs: []domain.WebhookSubscription{{ID: "sub", URL: server.URL}}}
+	if err := NewDispatcher(store, nil, helperConfig()).Publish(context.Background(), helperEvent(t, "")); err == nil {
+		t.Fatal("expected the loopback subscription to stay pending")
+	}
+	if calls.Load() != 0 || store.deliveries[0].Error == "" {
+		t.Fatalf("expected no call and a failed delivery, got %d calls and %+v", calls.Load(), store.deliveries)
+	}
+
+	config := helperConfig()
+	config.AllowedHosts = []string{"127.0.0.1"}
+	if err := NewDispatcher(store, nil, config).Publish(context.Background(), helperEvent(t, "")); err != nil {
+		t.Fatalf("Publish() to an allowed host unexpected error: %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("expected the allowed host to be called once, got %d", calls.Load())
+	}
+}
+
+func TestDispatcher_Publish_AppliesFilters(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer server.Close()
+
+	store := &memoryStore{subs: []domain.WebhookSubscription{
+		{ID: "all", URL: server.URL},
+		{ID: "devops", URL: server.URL, Categories: []string{"DevOps"}},
+		{ID: "apis", URL: server.URL, Categories: []string{"APIs"}},
+		{ID: "deletes", URL: server.URL, EventTypes: []domain.EventType{domain.EventGoldenDeleted}},
+	}}
+	NewDispatcher(store, server.Client(), helperConfig()).Publish(context.Background(), helperEvent(t, "DevOps"))
+
+	if calls.Load() != 2 {
+		t.Fatalf("expected 2 matching subscriptions, got %d calls", calls.Load())
+	}
+}
@@ -0,0 +1,316 @@
+package proto
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"testing"
+
+	pb "markitos-it-svc-goldens/proto/goldens/v1"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	gproto "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const baselineFile = "testdata/descriptors.json"
+
+var update = flag.Bool("update", false, "record the current protos as the baseline in "+baselineFile)
+
+// currentDescriptors returns the compiled protos, dependencies first.
+func currentDescriptors() *descriptorpb.FileDescriptorSet {
+	set := &descriptorpb.FileDescriptorSet{}
+	for _, file := range []protoreflect.FileDescriptor{
+		timestamppb.File_google_protobuf_timestamp_proto,
+		pb.File_proto_goldens_v1_golden_proto,
+		File_proto_golden_proto,
+	} {
+		set.File = append(set.File, protodesc.ToFileDescriptorProto(file))
+	}
+	return set
+}
+
+func marshalDescriptors(t *testing.T, set *descriptorpb.FileDescriptorSet) []byte {
+	t.Helper()
+	data, err := protojson.Marshal(set)
+	if err != nil {
+		t.Fatalf("protojson.Marshal() error = %v", err)
+	}
+	// protojson output is not stable, so it is normalised before comparing.
+	var compact, indented bytes.Buffer
+	if err := json.Compact(&compact, data); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Indent(&indented, compact.Bytes(), "", "  "); err != nil {
+		t.Fatal(err)
+	}
+	indented.WriteByte('\n')
+	return indented.Bytes()
+}
+
+func helperFiles(t *testing.T, set *descriptorpb.FileDescriptorSet) *protoregistry.Files {
+	t.Helper()
+	files, err := protodesc.NewFiles(set)
+	if err != nil {
+		t.Fatalf("protodesc.NewFiles() error = %v", err)
+	}
+	return files
+}
+
+// TestDescriptors_BackwardCompatible fails when a change to the protos would
+// break clients built against the baseline. Once a change is reviewed, record
+// it with: go test ./proto -update
+func TestDescriptors_BackwardCompatible(t *testing.T) {
+	current := currentDescriptors()
+	data := marshalDescriptors(t, current)
+	if *update {
+		if err := os.WriteFile(baselineFile, data, 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", baselineFile, err)
+		}
+		return
+	}
+
+	stored, err := os.ReadFile(baselineFile)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", baselineFile, err)
+	}
+	baseline := &descriptorpb.FileDescriptorSet{}
+	if err := protojson.Unmarshal(stored, baseline); err != nil {
+		t.Fatalf("invalid %s: %v", baselineFile, err)
+	}
+
+	problems := compatibility(helperFiles(t, baseline), helperFiles(t, current))
+	for _, problem := range problems {
+		t.Errorf("breaking change: %s", problem)
+	}
+	if len(problems) == 0 && !bytes.Equal(stored, data) {
+		t.Errorf("%s is stale; run go test ./proto -update to record the new API", baselineFile)
+	}
+}
+
+func TestCompatibility_DetectsBreakingChanges(t *testing.T) {
+	golden := func(file *descriptorpb.FileDescriptorProto) *descriptorpb.DescriptorProto {
+		for _, msg := range file.MessageType {
+			if msg.GetName() == "Golden" {
+				return msg
+			}
+		}
+		t.Fatal("message Golden not found")
+		return nil
+	}
+
+	tests := []struct {
+		name     string
+		edit     func(file *descriptorpb.FileDescriptorProto)
+		breaking bool
+	}{
+		{
+			name: "add-field",
+			edit: func(file *descriptorpb.FileDescriptorProto) {
+				msg := golden(file)
+				msg.Field = append(msg.Field, &descriptorpb.FieldDescriptorProto{
+					Name:     gproto.String("extra"),
+					JsonName: gproto.String("extra"),
+					Number:   gproto.Int32(99),
+					Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+					Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				})
+			},
+		},
+		{
+			name: "remove-reserved-field",
+			edit: func(file *descriptorpb.FileDescriptorProto) {
+				msg := golden(file)
+				msg.Field = msg.Field[1:]
+				msg.ReservedRange = append(msg.ReservedRange, &descriptorpb.DescriptorProto_ReservedRange{Start: gproto.Int32(1), End: gproto.Int32(2)})
+				msg.ReservedName = append(msg.ReservedName, "id")
+			},
+		},
+		{
+			name:     "remove-field",
+			edit:     func(file *descriptorpb.FileDescriptorProto) { golden(file).Field = golden(file).Field[1:] },
+			breaking: true,
+		},
+		{
+			name: "change-field-type",
+			edit: func(file *descriptorpb.FileDescriptorProto) {
+				golden(file).Field[0].Type = descriptorpb.FieldDescriptorProto_TYPE_BYTES.Enum()
+			},
+			breaking: true,
+		},
+		{
+			name: "make-field-repeated",
+			edit: func(file *descriptorpb.FileDescriptorProto) {
+				golden(file).Field[0].Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+			},
+			breaking: true,
+		},
+		{
+			name: "rename-field",
+			edit: func(file *descriptorpb.FileDescriptorProto) {
+				golden(file).Field[0].Name = gproto.String("identifier")
+				golden(file).Field[0].JsonName = gproto.String("identifier")
+			},
+			breaking: true,
+		},
+		{
+			name: "remove-enum-value",
+			edit: func(file *descriptorpb.FileDescriptorProto) {
+				view := file.EnumType[0]
+				view.Value = view.Value[:len(view.Value)-1]
+			},
+			breaking: true,
+		},
+		{
+			name: "remove-method",
+			edit: func(file *descriptorpb.FileDescriptorProto) {
+				service := file.Service[0]
+				service.Method = service.Method[1:]
+			},
+			breaking: true,
+		},
+		{
+			name: "change-streaming",
+			edit: func(file *descriptorpb.FileDescriptorProto) {
+				file.Service[0].Method[0].ServerStreaming = gproto.Bool(true)
+			},
+			breaking: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			baseline := currentDescriptors()
+			current := currentDescriptors()
+			for _, file := range current.File {
+				if file.GetName() == pb.File_proto_goldens_v1_golden_proto.Path() {
+					tt.edit(file)
+				}
+			}
+
+			problems := compatibility(helperFiles(t, baseline), helperFiles(t, current))
+			if got := len(problems) > 0; got != tt.breaking {
+				t.Fatalf("problems = %q, want breaking = %v", problems, tt.breaking)
+			}
+		})
+	}
+}
+
+// compatibility lists the changes from baseline to current that break the
+// wire or JSON format of the services declared in baseline.
+func compatibility(baseline, current *protoregistry.Files) []string {
+	c := &compatChecker{current: current, seen: make(map[protoreflect.FullName]bool)}
+	baseline.RangeFiles(func(file protoreflect.FileDescriptor) bool {
+		services := file.Services()
+		for i := 0; i < services.Len(); i++ {
+			c.service(services.Get(i))
+		}
+		return true
+	})
+	return c.problems
+}
+
+type compatChecker struct {
+	current  *protoregistry.Files
+	seen     map[protoreflect.FullName]bool
+	problems []string
+}
+
+func (c *compatChecker) report(format string, args ...any) {
+	c.problems = append(c.problems, fmt.Sprintf(format, args...))
+}
+
+func (c *compatChecker) service(old protoreflect.ServiceDescriptor) {
+	desc, err := c.current.FindDescriptorByName(old.FullName())
+	if err != nil {
+		c.report("service %s was removed", old.FullName())
+		return
+	}
+	service, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		c.report("service %s is no longer a service", old.FullName())
+		return
+	}
+
+	methods := old.Methods()
+	for i := 0; i < methods.Len(); i++ {
+		oldMethod := methods.Get(i)
+		method := service.Methods().ByName(oldMethod.Name())
+		if method == nil {
+			c.report("method %s was removed", oldMethod.FullName())
+			continue
+		}
+		if method.IsStreamingClient() != oldMethod.IsStreamingClient() || method.IsStreamingServer() != oldMethod.IsStreamingServer() {
+			c.report("method %s changed its streaming", oldMethod.FullName())
+		}
+		c.message(oldMethod.Input(), method.Input())
+		c.message(oldMethod.Output(), method.Output())
+	}
+}
+
+func (c *compatChecker) message(old, msg protoreflect.MessageDescriptor) {
+	if c.seen[old.FullName()] {
+		return
+	}
+	c.seen[old.FullName()] = true
+
+	fields := old.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		oldField := fields.Get(i)
+		field := msg.Fields().ByNumber(oldField.Number())
+		if field == nil {
+			if !msg.ReservedRanges().Has(oldField.Number()) {
+				c.report("field %s (%d) was removed without reserving its number", oldField.FullName(), oldField.Number())
+			}
+			continue
+		}
+		if field.Name() != oldField.Name() || field.JSONName() != oldField.JSONName() {
+			c.report("field %s (%d) was renamed to %s", oldField.FullName(), oldField.Number(), field.Name())
+		}
+		if field.Kind() != oldField.Kind() {
+			c.report("field %s changed its type from %s to %s", oldField.FullName(), oldField.Kind(), field.Kind())
+			continue
+		}
+		if field.Cardinality() != oldField.Cardinality() || field.IsMap() != oldField.IsMap() {
+			c.report("field %s changed its cardinality", oldField.FullName())
+			continue
+		}
+		switch {
+		case oldField.Message() != nil:
+			c.message(oldField.Message(), field.Message())
+		case oldField.Enum() != nil:
+			c.enum(oldField.Enum(), field.Enum())
+		}
+	}
+
+	ranges := old.ReservedRanges()
+	for i := 0; i < msg.Fields().Len(); i++ {
+		if field := msg.Fields().Get(i); ranges.Has(field.Number()) {
+			c.report("field %s reuses the reserved number %d", field.FullName(), field.Number())
+		}
+	}
+}
+
+func (c *compatChecker) enum(old, enum protoreflect.EnumDescriptor) {
+	if c.seen[old.FullName()] {
+		return
+	}
+	c.seen[old.FullName()] = true
+
+	values := old.Values()
+	for i := 0; i < values.Len(); i++ {
+		oldValue := values.Get(i)
+		value := enum.Values().ByNumber(oldValue.Number())
+		switch {
+		case value == nil && !enum.ReservedRanges().Has(oldValue.Number()):
+			c.report("enum value %s (%d) was removed without reserving its number", oldValue.FullName(), oldValue.Number())
+		case value != nil && value.Name() != oldValue.Name():
+			c.report("enum value %s (%d) was renamed to %s", oldValue.FullName(), oldValue.Number(), value.Name())
+		}
+	}
+}